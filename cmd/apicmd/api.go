@@ -0,0 +1,291 @@
+package apicmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/renato0307/learning-go-cli/internal/api"
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/renato0307/learning-go-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+const (
+	MethodFlag string = "method"
+	FieldFlag  string = "field"
+	HeaderFlag string = "header"
+	InputFlag  string = "input"
+)
+
+// NewApiCmd represents the api command
+func NewApiCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "api <path>",
+		Short: "Calls any API endpoint",
+		Long: `Calls an endpoint of the learning-go-api, using the configured API endpoint
+and access token, and prints the response. It is useful to call endpoints
+without a dedicated command yet, like:
+
+  learning-go-cli api /programming/uuid -X POST -f no-hyphens=true
+
+The method is GET by default, or POST when there are fields or an input.
+
+Fields given with --field are typed: true, false, null and numbers are sent
+as JSON values and everything else as strings. They are sent as a JSON
+object in the body or, for GET requests and when --input is used, as query
+parameters. Query parameters can also be part of the path.
+
+The body can also be read from a file with --input, or from the input with
+--input -, and is sent as it is. Bodies made of fields are sent with the
+application/json Content-Type, other bodies only with the one given with
+--header, like -H 'Content-Type: text/plain'.
+
+The configured access token is sent unless the request has its own, like
+-H 'Authorization: Bearer <token>'.
+
+JSON responses are printed with the selected output format and jq filter,
+other responses are printed as they are, ending with a new line.`,
+		Args: clierrors.UsageArgs(cobra.ExactArgs(1)),
+		RunE: executeApi(iostreams, api.DefaultFactory),
+	}
+
+	cmd.Flags().StringP(MethodFlag,
+		"X",
+		"",
+		"the HTTP method, GET by default or POST with fields or an input")
+	cmd.Flags().StringArrayP(FieldFlag,
+		"f",
+		[]string{},
+		"a field as key=value, sent in the body or as a query parameter")
+	cmd.Flags().StringArrayP(HeaderFlag,
+		"H",
+		[]string{},
+		"a request header as name:value")
+	cmd.Flags().String(InputFlag,
+		"",
+		"the file with the request body, or - to read it from the input")
+
+	return cmd
+}
+
+// executeApi implements all the logic associated with this command.
+func executeApi(iostreams *iostreams.IOStreams, newRequester api.Factory) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		request, err := apiRequest(cmd, iostreams, args[0])
+		if err != nil {
+			return err
+		}
+
+		requester, ok := newRequester().(api.RawRequester)
+		if !ok {
+			return errors.New("the API client cannot return the responses as they are")
+		}
+		response, err := requester.DoRaw(cmd.Context(), request)
+		if err != nil {
+			return err
+		}
+		defer response.Body.Close()
+
+		content, err := ioutil.ReadAll(response.Body)
+		if err != nil {
+			return fmt.Errorf("error reading the API response: %w", err)
+		}
+		if response.StatusCode < 200 || response.StatusCode > 299 {
+			return api.DecodeError(response.StatusCode, response.Header, content)
+		}
+
+		return printResponse(iostreams, response.Header, content)
+	}
+}
+
+// apiRequest creates the request from the path and the flags
+func apiRequest(cmd *cobra.Command, iostreams *iostreams.IOStreams, path string) (api.Request, error) {
+	request := api.Request{}
+
+	target, err := url.Parse(path)
+	if err != nil {
+		return request, clierrors.Usagef("invalid path %q: %s", path, err)
+	}
+	request.Path = target.Path
+	request.Query = target.Query()
+
+	fields, err := cmd.Flags().GetStringArray(FieldFlag)
+	if err != nil {
+		return request, err
+	}
+	body, err := parseFields(fields)
+	if err != nil {
+		return request, err
+	}
+
+	input, err := cmd.Flags().GetString(InputFlag)
+	if err != nil {
+		return request, err
+	}
+	if input != "" {
+		request.Body, err = readInput(iostreams, input)
+		if err != nil {
+			return request, err
+		}
+	}
+
+	request.Method, err = cmd.Flags().GetString(MethodFlag)
+	if err != nil {
+		return request, err
+	}
+	request.Method = strings.ToUpper(request.Method)
+	if request.Method == "" {
+		request.Method = http.MethodGet
+		if len(body) > 0 || input != "" {
+			request.Method = http.MethodPost
+		}
+	}
+
+	// fields go in the query when the body is the input or there is no body
+	if len(body) > 0 {
+		if input != "" || request.Method == http.MethodGet || request.Method == http.MethodHead {
+			for key, value := range body {
+				request.Query.Add(key, fieldText(value))
+			}
+		} else {
+			request.Body = body
+		}
+	}
+
+	headers, err := cmd.Flags().GetStringArray(HeaderFlag)
+	if err != nil {
+		return request, err
+	}
+	request.Header, err = parseHeaders(headers)
+	if err != nil {
+		return request, err
+	}
+
+	return request, nil
+}
+
+// parseFields reads the key=value fields, typing their values
+func parseFields(fields []string) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	for _, field := range fields {
+		key, value, found := cut(field, "=")
+		if !found || key == "" {
+			return nil, clierrors.Usagef("invalid field %q, expected key=value", field)
+		}
+		values[key] = fieldValue(value)
+	}
+	return values, nil
+}
+
+// fieldValue types the value of a field: true, false, null and numbers are
+// JSON values, kept exactly as typed, and anything else is a string
+func fieldValue(value string) interface{} {
+	switch value {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+
+	var number json.Number
+	if json.Unmarshal([]byte(value), &number) == nil {
+		return number
+	}
+	return value
+}
+
+// fieldText returns a typed field value as text, for query parameters
+func fieldText(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	return fmt.Sprint(value)
+}
+
+// parseHeaders reads the name:value headers
+func parseHeaders(headers []string) (http.Header, error) {
+	header := http.Header{}
+	for _, text := range headers {
+		name, value, found := cut(text, ":")
+		if !found || strings.TrimSpace(name) == "" {
+			return nil, clierrors.Usagef("invalid header %q, expected name:value", text)
+		}
+		header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	return header, nil
+}
+
+// cut slices the text around the first separator, like strings.Cut
+func cut(text string, separator string) (string, string, bool) {
+	if i := strings.Index(text, separator); i >= 0 {
+		return text[:i], text[i+len(separator):], true
+	}
+	return text, "", false
+}
+
+// readInput reads the request body from the file, or from the input if the
+// file is -. The body is read at once so it can be sent again on retries.
+func readInput(iostreams *iostreams.IOStreams, file string) ([]byte, error) {
+	var reader io.Reader
+	if file == "-" {
+		if iostreams.In == nil {
+			return nil, clierrors.Usagef("there is no input to read the body from")
+		}
+		reader = iostreams.In
+	} else {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the input: %w", err)
+		}
+		defer f.Close()
+		reader = f
+	}
+
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the input: %w", err)
+	}
+	return content, nil
+}
+
+// printResponse prints JSON responses with the output formatter and other
+// responses as they are, ending with a new line
+func printResponse(iostreams *iostreams.IOStreams, header http.Header, content []byte) error {
+	if len(bytes.TrimSpace(content)) == 0 {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	if mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.UseNumber()
+		var data interface{}
+		err := decoder.Decode(&data)
+		if err != nil {
+			return fmt.Errorf("error parsing the API response: %w", err)
+		}
+		return output.Print(iostreams, data)
+	}
+
+	text := string(content)
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	_, err := iostreams.Fprint(text)
+	if err != nil {
+		return fmt.Errorf("error writing to the output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package apicmd
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/api"
+	"github.com/renato0307/learning-go-cli/internal/auth"
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordedRequest keeps what the test server received
+type recordedRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	Body   string
+}
+
+// newTestServer creates a server recording the request and answering with
+// the content type, status and body
+func newTestServer(recorded *recordedRequest, contentType string, status int, body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, _ := ioutil.ReadAll(r.Body)
+		*recorded = recordedRequest{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.RawQuery,
+			Header: r.Header,
+			Body:   string(content),
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+}
+
+// clientFactory returns an api.Factory creating clients for the test server
+func clientFactory(url string, tokenSource api.TokenSource) api.Factory {
+	return func() api.Requester {
+		return api.NewClient(url, tokenSource)
+	}
+}
+
+func TestNewApiCmd(t *testing.T) {
+	// act
+	cmd := NewApiCmd(nil)
+
+	// assert
+	assert.Equal(t, "api", cmd.Name())
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+	for _, flag := range []string{MethodFlag, FieldFlag, HeaderFlag, InputFlag} {
+		assert.NotNil(t, cmd.Flags().Lookup(flag), flag)
+	}
+}
+
+func TestExecuteApi(t *testing.T) {
+	testCases := []struct {
+		Purpose        string
+		Args           []string
+		Input          string
+		ExpectedMethod string
+		ExpectedQuery  string
+		ExpectedBody   string
+		ExpectedType   string
+	}{
+		{
+			Purpose:        "GET with query parameters in the path and fields",
+			Args:           []string{"/programming/uuid?no-hyphens=true", "-X", "GET", "-f", "count=2"},
+			ExpectedMethod: http.MethodGet,
+			ExpectedQuery:  "count=2&no-hyphens=true",
+		},
+		{
+			Purpose:        "fields are typed in the body",
+			Args:           []string{"/programming/uuid", "-f", "count=2", "-f", "amount=12.50", "-f", "local=true", "-f", "name=x=y"},
+			ExpectedMethod: http.MethodPost,
+			ExpectedBody:   `{"amount":12.50,"count":2,"local":true,"name":"x=y"}`,
+			ExpectedType:   "application/json",
+		},
+		{
+			Purpose:        "explicit method",
+			Args:           []string{"/programming/uuid", "-X", "put", "-f", "value=null"},
+			ExpectedMethod: http.MethodPut,
+			ExpectedBody:   `{"value":null}`,
+			ExpectedType:   "application/json",
+		},
+		{
+			Purpose:        "body from the input and fields in the query",
+			Args:           []string{"/programming/uuid", "--input", "-", "-f", "count=2"},
+			Input:          `{"raw": true}`,
+			ExpectedMethod: http.MethodPost,
+			ExpectedQuery:  "count=2",
+			ExpectedBody:   `{"raw": true}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		recorded := recordedRequest{}
+		srv := newTestServer(&recorded, "application/json", http.StatusOK, `{"uuid": "da308fbd"}`)
+		iostreams, in, out, _ := iostreams.Test()
+		in.WriteString(tc.Input)
+		cmd := NewApiCmd(iostreams)
+		cmd.RunE = executeApi(iostreams, clientFactory(srv.URL, nil))
+
+		// act
+		cmd.SetArgs(tc.Args)
+		err := cmd.Execute()
+		srv.Close()
+
+		// assert
+		assert.NoError(t, err, tc.Purpose)
+		assert.Equal(t, tc.ExpectedMethod, recorded.Method, tc.Purpose)
+		assert.Equal(t, "/programming/uuid", recorded.Path, tc.Purpose)
+		assert.Equal(t, tc.ExpectedQuery, recorded.Query, tc.Purpose)
+		assert.Equal(t, tc.ExpectedBody, recorded.Body, tc.Purpose)
+		assert.Equal(t, tc.ExpectedType, recorded.Header.Get("Content-Type"), tc.Purpose)
+		assert.Equal(t, "{\n  \"uuid\": \"da308fbd\"\n}\n", out.String(), tc.Purpose)
+	}
+}
+
+func TestExecuteApiWithHeadersAndInputFile(t *testing.T) {
+	// arrange
+	file := filepath.Join(t.TempDir(), "body.txt")
+	err := os.WriteFile(file, []byte("plain body"), 0600)
+	assert.NoError(t, err)
+
+	recorded := recordedRequest{}
+	srv := newTestServer(&recorded, "text/plain", http.StatusOK, "plain response\n")
+	defer srv.Close()
+	iostreams, _, out, _ := iostreams.Test()
+	cmd := NewApiCmd(iostreams)
+	cmd.RunE = executeApi(iostreams, clientFactory(srv.URL, nil))
+
+	// act
+	cmd.SetArgs([]string{"/echo", "--input", file, "-H", "Content-Type: text/plain", "-H", "x-trace:1"})
+	err = cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "plain body", recorded.Body)
+	assert.Equal(t, "text/plain", recorded.Header.Get("Content-Type"))
+	assert.Equal(t, "1", recorded.Header.Get("X-Trace"))
+	assert.Equal(t, "plain response\n", out.String(), "Responses other than JSON must be printed as they are")
+}
+
+func TestExecuteApiWithPlainResponse(t *testing.T) {
+	// arrange
+	recorded := recordedRequest{}
+	srv := newTestServer(&recorded, "text/plain", http.StatusOK, "plain response")
+	defer srv.Close()
+	iostreams, _, out, _ := iostreams.Test()
+	cmd := NewApiCmd(iostreams)
+	cmd.RunE = executeApi(iostreams, clientFactory(srv.URL, nil))
+
+	// act
+	cmd.SetArgs([]string{"/echo"})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "plain response\n", out.String(), "The output must end with a new line")
+}
+
+func TestExecuteApiWithAuthorizationHeader(t *testing.T) {
+	// arrange
+	recorded := recordedRequest{}
+	srv := newTestServer(&recorded, "application/json", http.StatusOK, `{}`)
+	defer srv.Close()
+	iostreams, _, _, _ := iostreams.Test()
+	cmd := NewApiCmd(iostreams)
+	cmd.RunE = executeApi(iostreams, clientFactory(srv.URL,
+		func(ctx context.Context) (auth.AccessToken, error) {
+			t.Error("The token of the CLI must not be fetched")
+			return auth.AccessToken{AccessToken: "token"}, nil
+		}))
+
+	// act
+	cmd.SetArgs([]string{"/items", "-H", "Authorization: Bearer other"})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Bearer other"}, recorded.Header.Values("Authorization"))
+}
+
+func TestExecuteApiWithErrorResponse(t *testing.T) {
+	// arrange
+	recorded := recordedRequest{}
+	srv := newTestServer(&recorded, "application/json", http.StatusNotFound, `{"message": "not found"}`)
+	defer srv.Close()
+	iostreams, _, out, _ := iostreams.Test()
+	cmd := NewApiCmd(iostreams)
+	cmd.RunE = executeApi(iostreams, clientFactory(srv.URL, nil))
+
+	// act
+	cmd.SetArgs([]string{"/missing"})
+	err := cmd.Execute()
+
+	// assert
+	var apiErr *api.APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.Equal(t, "not found", apiErr.Message)
+	assert.Empty(t, out.String())
+}
+
+func TestExecuteApiWithInvalidArgs(t *testing.T) {
+	testCases := []struct {
+		Purpose string
+		Args    []string
+		Error   string
+	}{
+		{
+			Purpose: "missing path",
+			Args:    []string{},
+			Error:   "accepts 1 arg(s), received 0",
+		},
+		{
+			Purpose: "invalid field",
+			Args:    []string{"/path", "-f", "count"},
+			Error:   "invalid field \"count\", expected key=value",
+		},
+		{
+			Purpose: "invalid header",
+			Args:    []string{"/path", "-H", "x-trace"},
+			Error:   "invalid header \"x-trace\", expected name:value",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		iostreams, _, _, _ := iostreams.Test()
+		cmd := NewApiCmd(iostreams)
+		cmd.RunE = executeApi(iostreams, func() api.Requester {
+			t.Error("The API must not be called")
+			return nil
+		})
+
+		// act
+		cmd.SetArgs(tc.Args)
+		err := cmd.Execute()
+
+		// assert
+		assert.EqualError(t, err, tc.Error, tc.Purpose)
+		var usageErr *clierrors.UsageError
+		assert.True(t, errors.As(err, &usageErr), tc.Purpose)
+	}
+}
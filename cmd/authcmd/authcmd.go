@@ -0,0 +1,36 @@
+package authcmd
+
+import (
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/spf13/cobra"
+)
+
+// NewAuthCmd represents the auth command
+func NewAuthCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Manages the authentication",
+		Long: `Allows to log in and out of the API, check if the credentials of the
+profile work, inspect the access tokens and get them to call the API with
+other tools.`,
+		RunE: executeAuth(),
+	}
+
+	cmd.AddCommand(NewAuthLoginCmd(iostreams))
+	cmd.AddCommand(NewAuthLogoutCmd(iostreams))
+	config.AddCommandWithConfigPreCheck(cmd, NewAuthInspectCmd(iostreams))
+	config.AddCommandWithConfigPreCheck(cmd, NewAuthStatusCmd(iostreams))
+	config.AddCommandWithConfigPreCheck(cmd, NewAuthTokenCmd(iostreams))
+
+	return cmd
+}
+
+// executeAuth implements all the logic associated with this command.
+// In this case as it is an aggregation command will return an error
+func executeAuth() func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		return clierrors.Usagef("must specify a subcommand")
+	}
+}
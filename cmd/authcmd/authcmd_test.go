@@ -0,0 +1,47 @@
+package authcmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAuthCmd(t *testing.T) {
+	// act
+	cmd := NewAuthCmd(nil)
+
+	// assert
+	assert.Equal(t, "auth", cmd.Use)
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+
+	testCases := []struct {
+		Name     string
+		PreCheck bool
+	}{
+		{Name: "login", PreCheck: false},
+		{Name: "logout", PreCheck: false},
+		{Name: "inspect", PreCheck: true},
+		{Name: "status", PreCheck: true},
+		{Name: "token", PreCheck: true},
+	}
+	for _, tc := range testCases {
+		subCmd, _, err := cmd.Find([]string{tc.Name})
+		assert.NoError(t, err)
+		assert.Equal(t, tc.Name, subCmd.Name())
+		assert.Equal(t, tc.PreCheck, subCmd.PreRunE != nil, "invalid configuration check for "+tc.Name)
+	}
+}
+
+func TestExecuteAuth(t *testing.T) {
+	// arrange
+	cmd := NewAuthCmd(nil)
+	cmd.SetArgs([]string{})
+
+	// act
+	err := cmd.Execute()
+
+	// assert
+	assert.EqualError(t, err, "must specify a subcommand")
+}
@@ -0,0 +1,242 @@
+package authcmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/renato0307/learning-go-cli/internal/auth"
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/renato0307/learning-go-cli/internal/jwt"
+	"github.com/renato0307/learning-go-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+const (
+	IntrospectFlag string = "introspect"
+)
+
+// Formats of the access tokens
+const (
+	TokenFormatJwt    string = "jwt"
+	TokenFormatOpaque string = "opaque"
+)
+
+// cachedTokenSource returns the access token used by the CLI with the
+// moment it was issued, replaced in tests
+type cachedTokenSource func(ctx context.Context) (auth.CachedToken, error)
+
+// introspector asks the introspection endpoint about a token, replaced in
+// tests
+type introspector func(
+	ctx context.Context,
+	credentials auth.Credentials,
+	endpoint string,
+	token string) (auth.Introspection, error)
+
+// TokenInspection describes the access token used by the CLI
+type TokenInspection struct {
+	Profile       string             `json:"profile"`
+	TokenType     string             `json:"token_type"`
+	Format        string             `json:"format"`
+	Issuer        string             `json:"issuer,omitempty"`
+	Subject       string             `json:"subject,omitempty"`
+	Audience      []string           `json:"audience,omitempty"`
+	Scopes        []string           `json:"scopes"`
+	IssuedAt      string             `json:"issued_at,omitempty"`
+	ExpiresAt     string             `json:"expires_at,omitempty"`
+	Expired       bool               `json:"expired"`
+	Introspection auth.Introspection `json:"introspection,omitempty"`
+}
+
+// NewAuthInspectCmd represents the auth inspect command
+func NewAuthInspectCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inspect",
+		Short: "Describes the access token",
+		Long: `Describes the access token used by the CLI, the cached one while it is
+valid or a new one otherwise. JWT tokens are decoded locally, showing their
+issuer, subject, audience, scopes and expiration, without verifying their
+signature.
+
+Warnings are reported in the error output when the token:
+  - is expired or not valid yet
+  - expires within the token-expiry-skew, which makes every command fetch a
+    new token as tokens this close to expiring are never cached
+  - is not for the configured audience or resource
+
+With --introspect the token is also sent to the introspection endpoint
+(RFC 7662) of the profile, configured with:
+
+  learning-go-cli config set introspection-endpoint https://auth.example.com/introspect
+
+and the command fails if the server reports the token as inactive.`,
+		Args: clierrors.UsageArgs(cobra.NoArgs),
+		RunE: executeAuthInspect(iostreams, auth.GetCachedToken, auth.Introspect, time.Now),
+	}
+
+	cmd.Flags().Bool(IntrospectFlag,
+		false,
+		"if set the token is also checked by the introspection endpoint")
+
+	return cmd
+}
+
+// executeAuthInspect implements all the logic associated with this command.
+func executeAuthInspect(
+	iostreams *iostreams.IOStreams,
+	getToken cachedTokenSource,
+	introspect introspector,
+	now func() time.Time) func(cmd *cobra.Command, args []string) error {
+
+	return func(cmd *cobra.Command, args []string) error {
+		useIntrospection, err := cmd.Flags().GetBool(IntrospectFlag)
+		if err != nil {
+			return err
+		}
+		endpoint := config.GetString(config.IntrospectionEndpointFlag)
+		if useIntrospection && endpoint == "" {
+			return &config.InvalidConfigError{
+				Profile: config.Profile(),
+				Err: fmt.Errorf("--%s requires the %s setting: "+
+					"please run `learning-go-cli config set %s <url> --profile %s`",
+					IntrospectFlag,
+					config.IntrospectionEndpointFlag,
+					config.IntrospectionEndpointFlag,
+					config.Profile()),
+			}
+		}
+
+		cached, err := getToken(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		inspection, expiresAt, notBefore := inspectToken(cached)
+		checkToken(iostreams, &inspection, expiresAt, notBefore, now())
+
+		if useIntrospection {
+			inspection.Introspection, err = introspect(cmd.Context(),
+				auth.CredentialsFor(cmd.Context()),
+				endpoint,
+				cached.AccessToken.AccessToken)
+			if err != nil {
+				return fmt.Errorf("error introspecting the token: %w", err)
+			}
+		}
+
+		err = output.Print(iostreams, inspection)
+		if err != nil {
+			return err
+		}
+		if inspection.Introspection != nil && !inspection.Introspection.Active() {
+			return errors.New("the introspection endpoint reports the token as inactive")
+		}
+		return nil
+	}
+}
+
+// inspectToken describes the token, decoding it when it is a JWT, and
+// returns when it expires and when it starts to be valid, zero if unknown.
+// The dates of the claims are preferred to the ones of the token response.
+func inspectToken(cached auth.CachedToken) (TokenInspection, time.Time, time.Time) {
+	inspection := TokenInspection{
+		Profile:   config.Profile(),
+		TokenType: cached.TokenType,
+		Format:    TokenFormatOpaque,
+		Scopes:    tokenScopes(cached.AccessToken),
+	}
+
+	var expiresAt, notBefore time.Time
+	if cached.ExpiresIn > 0 {
+		expiresAt = cached.ExpiresAt()
+	}
+
+	decoded, err := jwt.Decode(cached.AccessToken.AccessToken)
+	if err != nil {
+		return inspection, expiresAt, notBefore
+	}
+	inspection.Format = TokenFormatJwt
+	inspection.Issuer, _ = decoded.Claims["iss"].(string)
+	inspection.Subject, _ = decoded.Claims["sub"].(string)
+	inspection.Audience = claimStrings(decoded.Claims["aud"])
+	if issuedAt, ok := decoded.Time("iat"); ok {
+		inspection.IssuedAt = issuedAt.Format(time.RFC3339)
+	}
+	if exp, ok := decoded.Time("exp"); ok {
+		expiresAt = exp
+	}
+	if nbf, ok := decoded.Time("nbf"); ok {
+		notBefore = nbf
+	}
+	return inspection, expiresAt, notBefore
+}
+
+// checkToken validates the dates and the audience of the token locally,
+// warning about the problems found
+func checkToken(iostreams *iostreams.IOStreams, inspection *TokenInspection, expiresAt time.Time, notBefore time.Time, now time.Time) {
+	if !expiresAt.IsZero() {
+		inspection.ExpiresAt = expiresAt.UTC().Format(time.RFC3339)
+		skew := auth.ExpirySkew()
+		remaining := expiresAt.Sub(now).Round(time.Second)
+		switch {
+		case remaining <= 0:
+			inspection.Expired = true
+			iostreams.Errorf("warning: the token expired at %s\n", inspection.ExpiresAt)
+		case remaining <= skew:
+			iostreams.Errorf("warning: the token expires in %s, within the %s of %s, "+
+				"so every command fetches a new token\n",
+				remaining,
+				config.TokenSkewFlag,
+				skew)
+		}
+	}
+
+	if !notBefore.IsZero() && now.Before(notBefore) {
+		iostreams.Errorf("warning: the token is not valid before %s\n",
+			notBefore.UTC().Format(time.RFC3339))
+	}
+
+	if inspection.Format != TokenFormatJwt || len(inspection.Audience) == 0 {
+		return
+	}
+	for _, expected := range []string{
+		config.GetString(config.AudienceFlag),
+		config.GetString(config.ResourceFlag),
+	} {
+		if expected != "" && !contains(inspection.Audience, expected) {
+			iostreams.Errorf("warning: the token audience does not include %s\n", expected)
+		}
+	}
+}
+
+// claimStrings returns the values of a claim that can be a string or an
+// array of strings, like aud
+func claimStrings(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		values := []string{}
+		for _, item := range value {
+			if text, ok := item.(string); ok {
+				values = append(values, text)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// contains checks if the value is one of the values
+func contains(values []string, value string) bool {
+	for _, current := range values {
+		if current == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,254 @@
+package authcmd
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/renato0307/learning-go-cli/internal/auth"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+// inspectNow is the moment the tokens are inspected in the tests
+var inspectNow = time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)
+
+// unsignedToken creates a JWT with the claims, without a signature
+func unsignedToken(claims string) string {
+	encode := base64.RawURLEncoding.EncodeToString
+	return encode([]byte(`{"alg":"none"}`)) + "." + encode([]byte(claims)) + "."
+}
+
+// fixedToken returns a token source always returning the token, issued at
+// the inspection moment
+func fixedToken(token auth.AccessToken) cachedTokenSource {
+	return func(ctx context.Context) (auth.CachedToken, error) {
+		return auth.CachedToken{AccessToken: token, IssuedAt: inspectNow}, nil
+	}
+}
+
+// noIntrospection fails the test if the introspection endpoint is called
+func noIntrospection(t *testing.T) introspector {
+	return func(ctx context.Context, credentials auth.Credentials, endpoint string, token string) (auth.Introspection, error) {
+		t.Error("the introspection endpoint must not be called")
+		return nil, nil
+	}
+}
+
+func TestNewAuthInspectCmd(t *testing.T) {
+	// act
+	cmd := NewAuthInspectCmd(nil)
+
+	// assert
+	assert.Equal(t, "inspect", cmd.Use)
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+	assert.NotNil(t, cmd.Flag(IntrospectFlag))
+}
+
+func TestExecuteAuthInspect(t *testing.T) {
+	// arrange
+	t.Setenv("HOME", t.TempDir())
+	fileName := config.CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+
+	iostreams, _, out, errOut := iostreams.Test()
+	cmd := NewAuthInspectCmd(iostreams)
+	token := auth.AccessToken{
+		AccessToken: unsignedToken(`{"iss":"https://auth.example.com","sub":"client",` +
+			`"aud":["api","other"],"scope":"uuid:write","iat":1645557742,"exp":1645561342}`),
+		TokenType: "Bearer",
+		ExpiresIn: 600,
+	}
+	cmd.RunE = executeAuthInspect(iostreams, fixedToken(token), noIntrospection(t),
+		func() time.Time { return inspectNow })
+
+	// act
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Empty(t, errOut.String())
+	assert.Equal(t, `{
+  "profile": "default",
+  "token_type": "Bearer",
+  "format": "jwt",
+  "issuer": "https://auth.example.com",
+  "subject": "client",
+  "audience": [
+    "api",
+    "other"
+  ],
+  "scopes": [
+    "uuid:write"
+  ],
+  "issued_at": "2022-02-22T19:22:22Z",
+  "expires_at": "2022-02-22T20:22:22Z",
+  "expired": false
+}
+`, out.String(), "the dates of the claims must be preferred")
+}
+
+func TestExecuteAuthInspectWarnings(t *testing.T) {
+	testCases := []struct {
+		Purpose  string
+		Token    auth.AccessToken
+		Audience string
+		Expected string
+	}{
+		{
+			Purpose:  "opaque token expiring within the skew",
+			Token:    auth.AccessToken{AccessToken: "opaque", ExpiresIn: 20},
+			Expected: "warning: the token expires in 20s, within the token-expiry-skew of 30s, so every command fetches a new token\n",
+		},
+		{
+			Purpose:  "expired token",
+			Token:    auth.AccessToken{AccessToken: unsignedToken(`{"exp":1645557000}`), ExpiresIn: 3600},
+			Expected: "warning: the token expired at 2022-02-22T19:10:00Z\n",
+		},
+		{
+			Purpose:  "token not valid yet",
+			Token:    auth.AccessToken{AccessToken: unsignedToken(`{"nbf":1645560000}`), ExpiresIn: 3600},
+			Expected: "warning: the token is not valid before 2022-02-22T20:00:00Z\n",
+		},
+		{
+			Purpose:  "token for another audience",
+			Token:    auth.AccessToken{AccessToken: unsignedToken(`{"aud":"other"}`), ExpiresIn: 3600},
+			Audience: "api",
+			Expected: "warning: the token audience does not include api\n",
+		},
+		{
+			Purpose:  "opaque token without expiration",
+			Token:    auth.AccessToken{AccessToken: "opaque"},
+			Audience: "api",
+			Expected: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Logf("testing %s", tc.Purpose)
+
+		// arrange
+		t.Setenv("HOME", t.TempDir())
+		fileName := config.CreateFakeConfigFile(t)
+		config.Set(config.AudienceFlag, tc.Audience)
+
+		iostreams, _, _, errOut := iostreams.Test()
+		cmd := NewAuthInspectCmd(iostreams)
+		cmd.RunE = executeAuthInspect(iostreams, fixedToken(tc.Token), noIntrospection(t),
+			func() time.Time { return inspectNow })
+
+		// act
+		cmd.SetArgs([]string{})
+		err := cmd.Execute()
+		os.Remove(fileName)
+
+		// assert
+		assert.NoError(t, err, tc.Purpose)
+		assert.Equal(t, tc.Expected, errOut.String(), tc.Purpose)
+	}
+}
+
+func TestExecuteAuthInspectWithIntrospection(t *testing.T) {
+	testCases := []struct {
+		Purpose       string
+		Introspection auth.Introspection
+		ExpectedError string
+	}{
+		{
+			Purpose:       "active token",
+			Introspection: auth.Introspection{"active": true, "scope": "uuid:write"},
+		},
+		{
+			Purpose:       "inactive token",
+			Introspection: auth.Introspection{"active": false},
+			ExpectedError: "the introspection endpoint reports the token as inactive",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Logf("testing %s", tc.Purpose)
+
+		// arrange
+		t.Setenv("HOME", t.TempDir())
+		fileName := config.CreateFakeConfigFile(t)
+		config.Set(config.IntrospectionEndpointFlag, "https://auth.example.com/introspect")
+
+		iostreams, _, out, _ := iostreams.Test()
+		cmd := NewAuthInspectCmd(iostreams)
+		introspected := ""
+		introspect := func(ctx context.Context, credentials auth.Credentials, endpoint string, token string) (auth.Introspection, error) {
+			assert.Equal(t, "fake_client_id", credentials.ClientId)
+			assert.Equal(t, "https://auth.example.com/introspect", endpoint)
+			introspected = token
+			return tc.Introspection, nil
+		}
+		cmd.RunE = executeAuthInspect(iostreams,
+			fixedToken(auth.AccessToken{AccessToken: "opaque", ExpiresIn: 3600}),
+			introspect,
+			func() time.Time { return inspectNow })
+
+		// act
+		cmd.SetArgs([]string{"--" + IntrospectFlag})
+		err := cmd.Execute()
+		os.Remove(fileName)
+
+		// assert
+		if tc.ExpectedError == "" {
+			assert.NoError(t, err, tc.Purpose)
+		} else {
+			assert.EqualError(t, err, tc.ExpectedError, tc.Purpose)
+		}
+		assert.Equal(t, "opaque", introspected, tc.Purpose)
+		assert.Contains(t, out.String(), `"introspection": {`, tc.Purpose)
+	}
+}
+
+func TestExecuteAuthInspectWithoutIntrospectionEndpoint(t *testing.T) {
+	// arrange
+	t.Setenv("HOME", t.TempDir())
+	fileName := config.CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+
+	iostreams, _, out, _ := iostreams.Test()
+	cmd := NewAuthInspectCmd(iostreams)
+	cmd.RunE = executeAuthInspect(iostreams,
+		fixedToken(auth.AccessToken{AccessToken: "opaque"}),
+		noIntrospection(t),
+		time.Now)
+
+	// act
+	cmd.SetArgs([]string{"--" + IntrospectFlag})
+	err := cmd.Execute()
+
+	// assert
+	invalidConfig := &config.InvalidConfigError{}
+	assert.ErrorAs(t, err, &invalidConfig)
+	assert.Contains(t, err.Error(), "--introspect requires the introspection-endpoint setting")
+	assert.Empty(t, out.String())
+}
+
+func TestClaimStrings(t *testing.T) {
+	testCases := []struct {
+		Purpose  string
+		Claim    interface{}
+		Expected []string
+	}{
+		{Purpose: "single value", Claim: "api", Expected: []string{"api"}},
+		{Purpose: "array", Claim: []interface{}{"api", 1, "other"}, Expected: []string{"api", "other"}},
+		{Purpose: "missing claim", Claim: nil, Expected: nil},
+	}
+
+	for _, tc := range testCases {
+		// act
+		values := claimStrings(tc.Claim)
+
+		// assert
+		assert.Equal(t, tc.Expected, values, tc.Purpose)
+	}
+}
@@ -0,0 +1,359 @@
+package authcmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/renato0307/learning-go-cli/internal/auth"
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/renato0307/learning-go-cli/internal/jwt"
+	"github.com/spf13/cobra"
+)
+
+const (
+	DeviceFlag string = "device"
+	WebFlag    string = "web"
+)
+
+// tokenRequester fetches a token with the given credentials, replaced in
+// tests
+type tokenRequester func(ctx context.Context, credentials auth.Credentials) (auth.AccessToken, error)
+
+// deviceLogin logs in a user with the device authorization flow, replaced
+// in tests
+type deviceLogin func(
+	ctx context.Context,
+	credentials auth.Credentials,
+	deviceEndpoint string,
+	show func(authorization auth.DeviceAuthorization) error) (auth.AccessToken, error)
+
+// webLogin logs in a user with the authorization code flow, replaced in
+// tests
+type webLogin func(
+	ctx context.Context,
+	credentials auth.Credentials,
+	authorizationEndpoint string,
+	port int,
+	open func(authorizationURL string) error) (auth.AccessToken, error)
+
+// loginFlows are the ways to get the token of the login
+type loginFlows struct {
+	clientCredentials tokenRequester
+	device            deviceLogin
+	web               webLogin
+	openBrowser       func(url string) error
+}
+
+// NewAuthLoginCmd represents the auth login command
+func NewAuthLoginCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Configures the credentials interactively",
+		Long: `Asks for the API endpoints and the client credentials, an interactive
+alternative to the configure command. The current values of the profile are
+suggested and kept when nothing is typed.
+
+By default the CLI authenticates as a client, with the client credentials
+flow. With --device or --web people log in with their own identity instead:
+
+  --device shows a code to type in a login page, which can be opened in any
+           device, like when using the CLI through SSH
+  --web    opens the login page in the browser, which returns to the CLI
+           through a local port, random unless redirect-port is configured
+
+Interactive logins get a refresh token, kept in the secret store, which is
+used to get new access tokens until it expires or the profile logs out.
+Clients without a client secret are public clients, with auth-method none.
+
+The client secret is only asked with the client_secret_basic authentication
+method. The keys and certificates of the private_key_jwt and tls_client_auth
+methods are set with the config set command, like:
+
+  learning-go-cli config set auth-method private_key_jwt
+  learning-go-cli config set private-key ~/.keys/client.pem
+
+The credentials are only stored after getting an access token with them, so
+invalid credentials never replace working ones.`,
+		Args: clierrors.UsageArgs(cobra.NoArgs),
+		RunE: executeAuthLogin(iostreams, loginFlows{
+			clientCredentials: auth.RequestToken,
+			device:            auth.LoginWithDevice,
+			web:               auth.LoginWithBrowser,
+			openBrowser:       openBrowser,
+		}),
+	}
+
+	cmd.Flags().Bool(DeviceFlag,
+		false,
+		"log in with your identity, typing a code in a login page")
+	cmd.Flags().Bool(WebFlag,
+		false,
+		"log in with your identity, in the browser")
+
+	return cmd
+}
+
+// executeAuthLogin implements all the logic associated with this command.
+func executeAuthLogin(iostreams *iostreams.IOStreams, flows loginFlows) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		device, err := cmd.Flags().GetBool(DeviceFlag)
+		if err != nil {
+			return err
+		}
+		web, err := cmd.Flags().GetBool(WebFlag)
+		if err != nil {
+			return err
+		}
+		if device && web {
+			return clierrors.Usagef("--%s and --%s cannot be used together", DeviceFlag, WebFlag)
+		}
+
+		if iostreams.In == nil {
+			return clierrors.Usagef("login asks for the credentials in the input, " +
+				"use configure to give them as flags")
+		}
+		p := &prompter{iostreams: iostreams, reader: bufio.NewReader(iostreams.In)}
+
+		apiEndpoint, err := p.ask("API endpoint", config.GetString(config.APIEndpointFlag))
+		if err != nil {
+			return err
+		}
+		tokenEndpoint, err := p.ask("Token endpoint", config.GetString(config.TokenEndpointFlag))
+		if err != nil {
+			return err
+		}
+
+		// the endpoint where the interactive login starts
+		var loginEndpointFlag, loginEndpoint string
+		switch {
+		case device:
+			loginEndpointFlag = config.DeviceEndpointFlag
+			loginEndpoint, err = p.ask("Device authorization endpoint", config.GetString(config.DeviceEndpointFlag))
+		case web:
+			loginEndpointFlag = config.AuthorizationEndpointFlag
+			loginEndpoint, err = p.ask("Authorization endpoint", config.GetString(config.AuthorizationEndpointFlag))
+		}
+		if err != nil {
+			return err
+		}
+
+		clientId, err := p.ask("Client id", config.GetString(config.ClientIdFlag))
+		if err != nil {
+			return err
+		}
+
+		// the keys and certificates of other methods are set with config set
+		credentials := auth.CredentialsFromConfig()
+		credentials.ClientId = clientId
+		credentials.TokenEndpoint = tokenEndpoint
+		clientSecret := ""
+		if device || web {
+			// the refresh token must allow getting the scopes of all commands
+			credentials.Scopes = auth.MergeScopes(credentials.Scopes, allScopes(requiredScopes(cmd.Root())))
+			if credentials.Method == config.AuthMethodClientSecret && credentials.ClientSecret == "" {
+				credentials.Method = config.AuthMethodNone
+			}
+		} else {
+			credentials.ClientSecret = ""
+			if credentials.Method == config.AuthMethodClientSecret {
+				clientSecret, err = p.askSecret("Client secret", config.GetString(config.ClientSecretFlag))
+				if err != nil {
+					return err
+				}
+				credentials.ClientSecret = clientSecret
+			}
+		}
+
+		issuedAt := time.Now()
+		var token auth.AccessToken
+		switch {
+		case device:
+			token, err = flows.device(cmd.Context(), credentials, loginEndpoint, showDeviceCode(iostreams))
+		case web:
+			token, err = flows.web(cmd.Context(),
+				credentials,
+				loginEndpoint,
+				config.GetInt(config.RedirectPortFlag),
+				openLoginPage(iostreams, flows.openBrowser))
+		default:
+			token, err = flows.clientCredentials(cmd.Context(), credentials)
+		}
+		if err != nil {
+			return err
+		}
+
+		if loginEndpointFlag != "" {
+			config.Set(loginEndpointFlag, loginEndpoint)
+			config.Set(config.AuthMethodFlag, credentials.Method)
+		}
+		err = config.WriteAuthenticationConfig(clientId, clientSecret, apiEndpoint, tokenEndpoint)
+		if err != nil {
+			return err
+		}
+
+		identity := clientId
+		if device || web {
+			err = auth.StoreLogin(cmd.Context(), token, issuedAt)
+			identity = tokenIdentity(token, clientId)
+		} else {
+			err = forgetLogin()
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = iostreams.Fprint(fmt.Sprintf("logged in to %s as %s (profile %q)\n",
+			apiEndpoint,
+			identity,
+			config.Profile()))
+		return err
+	}
+}
+
+// showDeviceCode shows the code of a device login and where to type it
+func showDeviceCode(iostreams *iostreams.IOStreams) func(authorization auth.DeviceAuthorization) error {
+	return func(authorization auth.DeviceAuthorization) error {
+		iostreams.Errorf("To log in, open %s and type the code %s\n",
+			authorization.VerificationURI,
+			authorization.UserCode)
+		if authorization.VerificationURIComplete != "" {
+			iostreams.Errorf("or open %s\n", authorization.VerificationURIComplete)
+		}
+		iostreams.Errorf("Waiting for the login...\n")
+		return nil
+	}
+}
+
+// openLoginPage opens the login page in the browser, showing its URL so it
+// can be opened by hand when there is no browser
+func openLoginPage(iostreams *iostreams.IOStreams, open func(url string) error) func(authorizationURL string) error {
+	return func(authorizationURL string) error {
+		iostreams.Errorf("Opening the login page in the browser, "+
+			"if it does not open please open:\n%s\n", authorizationURL)
+		open(authorizationURL)
+		iostreams.Errorf("Waiting for the login...\n")
+		return nil
+	}
+}
+
+// openBrowser opens the URL in the default browser of the system
+func openBrowser(url string) error {
+	var command *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		command = exec.Command("open", url)
+	case "windows":
+		command = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		command = exec.Command("xdg-open", url)
+	}
+	return command.Start()
+}
+
+// tokenIdentity returns who logged in, from the claims of JWT access tokens,
+// or the client id for opaque tokens
+func tokenIdentity(token auth.AccessToken, clientId string) string {
+	decoded, err := jwt.Decode(token.AccessToken)
+	if err != nil {
+		return clientId
+	}
+	for _, claim := range []string{"preferred_username", "email", "sub"} {
+		if value, ok := decoded.Claims[claim].(string); ok && value != "" {
+			return value
+		}
+	}
+	return clientId
+}
+
+// forgetLogin removes the tokens of previous logins, so the client
+// credentials are used instead
+func forgetLogin() error {
+	err := config.DeleteSecret(config.RefreshTokenFlag)
+	if err != nil {
+		return fmt.Errorf("error removing the refresh token: %w", err)
+	}
+	err = auth.RemoveCachedToken()
+	if err != nil {
+		return fmt.Errorf("error removing the cached tokens: %w", err)
+	}
+	return nil
+}
+
+// prompter asks for values in the error output, keeping the output for
+// results, and reads them from the input
+type prompter struct {
+	iostreams *iostreams.IOStreams
+	reader    *bufio.Reader
+}
+
+// ask asks for a required value, suggesting the current one which is kept
+// when nothing is typed
+func (p *prompter) ask(label string, current string) (string, error) {
+	if current != "" {
+		p.iostreams.Errorf("%s [%s]: ", label, current)
+	} else {
+		p.iostreams.Errorf("%s: ", label)
+	}
+
+	value, err := p.readLine()
+	if err != nil {
+		return "", err
+	}
+	return required(label, value, current)
+}
+
+// askSecret asks for a required secret without echoing it on terminals. The
+// current secret is never shown but is kept when nothing is typed.
+func (p *prompter) askSecret(label string, current string) (string, error) {
+	if current != "" {
+		p.iostreams.Errorf("%s [keep the current one]: ", label)
+	} else {
+		p.iostreams.Errorf("%s: ", label)
+	}
+
+	var value string
+	var err error
+	if p.iostreams.IsStdinTTY() {
+		value, err = p.iostreams.ReadPassword()
+		p.iostreams.Errorf("\n")
+	} else {
+		value, err = p.readLine()
+	}
+	if err != nil {
+		return "", err
+	}
+	return required(label, value, current)
+}
+
+// readLine reads a line from the input, without the line break
+func (p *prompter) readLine() (string, error) {
+	line, err := p.reader.ReadString('\n')
+	if errors.Is(err, io.EOF) && line == "" {
+		return "", clierrors.Usagef("the input ended before all the values were given")
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("error reading the input: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// required returns the value typed, or the current one if nothing was typed,
+// failing if there is none
+func required(label string, value string, current string) (string, error) {
+	if value == "" {
+		value = current
+	}
+	if value == "" {
+		return "", clierrors.Usagef("the %s is required", strings.ToLower(label))
+	}
+	return value, nil
+}
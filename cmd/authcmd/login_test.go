@@ -0,0 +1,319 @@
+package authcmd
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/renato0307/learning-go-cli/internal/auth"
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAuthLoginCmd(t *testing.T) {
+	// act
+	cmd := NewAuthLoginCmd(nil)
+
+	// assert
+	assert.Equal(t, "login", cmd.Use)
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+}
+
+func TestExecuteAuthLogin(t *testing.T) {
+	// arrange
+	t.Setenv("HOME", t.TempDir())
+	fileName := config.CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+
+	iostreams, in, out, errOut := iostreams.Test()
+	in.WriteString("https://api.example.com\n\nnew_client_id\n\n")
+	requested := auth.Credentials{}
+	requestToken := func(ctx context.Context, credentials auth.Credentials) (auth.AccessToken, error) {
+		requested = credentials
+		return auth.AccessToken{AccessToken: "token"}, nil
+	}
+	cmd := NewAuthLoginCmd(iostreams)
+	cmd.RunE = executeAuthLogin(iostreams, loginFlows{clientCredentials: requestToken})
+
+	// act
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, auth.Credentials{
+		Profile:       config.DefaultProfile,
+		ClientId:      "new_client_id",
+		ClientSecret:  "fake_client_secret",
+		TokenEndpoint: "fake_endpoint",
+		Method:        config.AuthMethodClientSecret,
+		Scopes:        []string{},
+	}, requested, "Values not typed must be kept")
+	assert.Equal(t, "https://api.example.com", config.GetString(config.APIEndpointFlag))
+	assert.Equal(t, "new_client_id", config.GetString(config.ClientIdFlag))
+	assert.Equal(t, "logged in to https://api.example.com as new_client_id (profile \"default\")\n", out.String())
+	assert.Equal(t, "API endpoint [fake_endpoint]: "+
+		"Token endpoint [fake_endpoint]: "+
+		"Client id [fake_client_id]: "+
+		"Client secret [keep the current one]: ", errOut.String())
+	assert.NotContains(t, errOut.String(), "fake_client_secret", "Secrets must never be shown")
+}
+
+func TestExecuteAuthLoginWithPrivateKeyJwt(t *testing.T) {
+	// arrange
+	t.Setenv("HOME", t.TempDir())
+	fileName := config.CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+	config.Set(config.AuthMethodFlag, config.AuthMethodPrivateKeyJwt)
+	config.Set(config.PrivateKeyFlag, "client.pem")
+
+	iostreams, in, _, errOut := iostreams.Test()
+	in.WriteString("\n\n\n")
+	requested := auth.Credentials{}
+	requestToken := func(ctx context.Context, credentials auth.Credentials) (auth.AccessToken, error) {
+		requested = credentials
+		return auth.AccessToken{AccessToken: "token"}, nil
+	}
+	cmd := NewAuthLoginCmd(iostreams)
+	cmd.RunE = executeAuthLogin(iostreams, loginFlows{clientCredentials: requestToken})
+
+	// act
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, config.AuthMethodPrivateKeyJwt, requested.Method)
+	assert.Equal(t, "client.pem", requested.PrivateKeyFile)
+	assert.Empty(t, requested.ClientSecret, "The client secret must not be sent")
+	assert.NotContains(t, errOut.String(), "Client secret", "The client secret must not be asked")
+}
+
+func TestExecuteAuthLoginWithDevice(t *testing.T) {
+	// arrange
+	t.Setenv("HOME", t.TempDir())
+	fileName := config.CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+	config.Unset(config.ClientSecretFlag)
+
+	iostreams, in, out, errOut := iostreams.Test()
+	in.WriteString("https://api.example.com\nhttps://auth.example.com/token\nhttps://auth.example.com/device\nclient_id\n")
+	requested := auth.Credentials{}
+	device := func(ctx context.Context, credentials auth.Credentials, deviceEndpoint string, show func(auth.DeviceAuthorization) error) (auth.AccessToken, error) {
+		requested = credentials
+		assert.Equal(t, "https://auth.example.com/device", deviceEndpoint)
+		err := show(auth.DeviceAuthorization{
+			UserCode:                "ABCD-EFGH",
+			VerificationURI:         "https://auth.example.com/activate",
+			VerificationURIComplete: "https://auth.example.com/activate?code=ABCD-EFGH",
+		})
+		claims := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"1234","email":"jane@example.com"}`))
+		return auth.AccessToken{
+			AccessToken:  "eyJhbGciOiJub25lIn0." + claims + ".",
+			ExpiresIn:    3600,
+			RefreshToken: "refresh_token",
+		}, err
+	}
+	cmd := NewAuthLoginCmd(iostreams)
+	cmd.RunE = executeAuthLogin(iostreams, loginFlows{device: device})
+
+	// act
+	cmd.SetArgs([]string{"--device"})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, config.AuthMethodNone, requested.Method, "Clients without a secret must be public clients")
+	assert.Equal(t, "client_id", requested.ClientId)
+	assert.Equal(t, "logged in to https://api.example.com as jane@example.com (profile \"default\")\n", out.String())
+	assert.Equal(t, "API endpoint [fake_endpoint]: "+
+		"Token endpoint [fake_endpoint]: "+
+		"Device authorization endpoint: "+
+		"Client id [fake_client_id]: "+
+		"To log in, open https://auth.example.com/activate and type the code ABCD-EFGH\n"+
+		"or open https://auth.example.com/activate?code=ABCD-EFGH\n"+
+		"Waiting for the login...\n", errOut.String())
+	assert.Equal(t, "https://auth.example.com/device", config.GetString(config.DeviceEndpointFlag))
+	assert.Equal(t, config.AuthMethodNone, config.GetString(config.AuthMethodFlag))
+	assert.Equal(t, "refresh_token", config.GetString(config.RefreshTokenFlag))
+	token, err := auth.GetAccessToken(context.Background())
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token.AccessToken, "The access token must be cached")
+}
+
+func TestExecuteAuthLoginWithWeb(t *testing.T) {
+	// arrange
+	t.Setenv("HOME", t.TempDir())
+	fileName := config.CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+	config.Set(config.RedirectPortFlag, 8085)
+
+	iostreams, in, out, errOut := iostreams.Test()
+	in.WriteString("\n\nhttps://auth.example.com/authorize\n\n")
+	opened := ""
+	requested := auth.Credentials{}
+	web := func(ctx context.Context, credentials auth.Credentials, authorizationEndpoint string, port int, open func(string) error) (auth.AccessToken, error) {
+		requested = credentials
+		assert.Equal(t, "https://auth.example.com/authorize", authorizationEndpoint)
+		assert.Equal(t, 8085, port)
+		err := open("https://auth.example.com/authorize?state=x")
+		return auth.AccessToken{AccessToken: "opaque", ExpiresIn: 3600, RefreshToken: "refresh_token"}, err
+	}
+	cmd := NewAuthLoginCmd(iostreams)
+	cmd.RunE = executeAuthLogin(iostreams, loginFlows{
+		web:         web,
+		openBrowser: func(url string) error { opened = url; return nil },
+	})
+
+	// act
+	cmd.SetArgs([]string{"--web"})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, config.AuthMethodClientSecret, requested.Method, "Clients with a secret must authenticate")
+	assert.Equal(t, "fake_client_secret", requested.ClientSecret)
+	assert.Equal(t, "https://auth.example.com/authorize?state=x", opened)
+	assert.Contains(t, errOut.String(), "https://auth.example.com/authorize?state=x\n", "The URL must be shown")
+	assert.NotContains(t, errOut.String(), "Client secret", "The client secret must not be asked")
+	assert.Equal(t, "logged in to fake_endpoint as fake_client_id (profile \"default\")\n", out.String())
+	assert.Equal(t, "https://auth.example.com/authorize", config.GetString(config.AuthorizationEndpointFlag))
+	assert.Equal(t, "refresh_token", config.GetString(config.RefreshTokenFlag))
+}
+
+func TestExecuteAuthLoginForgetsInteractiveLogins(t *testing.T) {
+	// arrange
+	t.Setenv("HOME", t.TempDir())
+	fileName := config.CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+	err := auth.StoreLogin(context.Background(), auth.AccessToken{AccessToken: "user_token", ExpiresIn: 3600, RefreshToken: "refresh_token"}, time.Now())
+	assert.NoError(t, err)
+
+	iostreams, in, _, _ := iostreams.Test()
+	in.WriteString("\n\n\n\n")
+	requestToken := func(ctx context.Context, credentials auth.Credentials) (auth.AccessToken, error) {
+		return auth.AccessToken{AccessToken: "token"}, nil
+	}
+	cmd := NewAuthLoginCmd(iostreams)
+	cmd.RunE = executeAuthLogin(iostreams, loginFlows{clientCredentials: requestToken})
+
+	// act
+	cmd.SetArgs([]string{})
+	err = cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Empty(t, config.GetString(config.RefreshTokenFlag), "The refresh token must be removed")
+	cacheFile, err := config.TokenCacheFile()
+	assert.NoError(t, err)
+	_, found, err := auth.NewTokenCache(cacheFile).Get(auth.CacheKey(auth.CredentialsFromConfig(), auth.GrantRefreshToken))
+	assert.NoError(t, err)
+	assert.False(t, found, "The token of the user must be removed")
+}
+
+func TestExecuteAuthLoginWithDeviceAndWeb(t *testing.T) {
+	// arrange
+	iostreams, _, _, _ := iostreams.Test()
+	cmd := NewAuthLoginCmd(iostreams)
+	cmd.RunE = executeAuthLogin(iostreams, loginFlows{})
+
+	// act
+	cmd.SetArgs([]string{"--device", "--web"})
+	err := cmd.Execute()
+
+	// assert
+	assert.EqualError(t, err, "--device and --web cannot be used together")
+	var usageErr *clierrors.UsageError
+	assert.True(t, errors.As(err, &usageErr))
+}
+
+func TestTokenIdentity(t *testing.T) {
+	// arrange
+	jwtToken := func(claims string) string {
+		encode := base64.RawURLEncoding.EncodeToString
+		return encode([]byte(`{"alg":"none"}`)) + "." + encode([]byte(claims)) + "."
+	}
+
+	// act & assert
+	assert.Equal(t, "jane", tokenIdentity(auth.AccessToken{AccessToken: jwtToken(`{"preferred_username":"jane","sub":"1"}`)}, "client"))
+	assert.Equal(t, "1", tokenIdentity(auth.AccessToken{AccessToken: jwtToken(`{"sub":"1"}`)}, "client"))
+	assert.Equal(t, "client", tokenIdentity(auth.AccessToken{AccessToken: "opaque"}, "client"))
+}
+
+func TestExecuteAuthLoginWithInvalidCredentials(t *testing.T) {
+	// arrange
+	t.Setenv("HOME", t.TempDir())
+	fileName := config.CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+
+	iostreams, in, out, _ := iostreams.Test()
+	in.WriteString("\n\nnew_client_id\nwrong_secret\n")
+	requestToken := func(ctx context.Context, credentials auth.Credentials) (auth.AccessToken, error) {
+		return auth.AccessToken{}, &auth.TokenError{StatusCode: 401, Message: "invalid_client"}
+	}
+	cmd := NewAuthLoginCmd(iostreams)
+	cmd.RunE = executeAuthLogin(iostreams, loginFlows{clientCredentials: requestToken})
+
+	// act
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+
+	// assert
+	var tokenErr *auth.TokenError
+	assert.True(t, errors.As(err, &tokenErr))
+	assert.Empty(t, out.String())
+	assert.Equal(t, "fake_client_id", config.GetString(config.ClientIdFlag), "Invalid credentials must not be stored")
+	assert.Equal(t, "fake_client_secret", config.GetString(config.ClientSecretFlag))
+}
+
+func TestExecuteAuthLoginWithMissingValues(t *testing.T) {
+	testCases := []struct {
+		Purpose string
+		Input   string
+		Error   string
+	}{
+		{
+			Purpose: "input ended",
+			Input:   "https://api.example.com\n",
+			Error:   "the input ended before all the values were given",
+		},
+		{
+			Purpose: "required value",
+			Input:   "\n",
+			Error:   "the api endpoint is required",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		t.Setenv("HOME", t.TempDir())
+		config.CreateFakeConfigFile(t)
+		config.Set(config.APIEndpointFlag, "")
+
+		iostreams, in, _, _ := iostreams.Test()
+		in.WriteString(tc.Input)
+		cmd := NewAuthLoginCmd(iostreams)
+		cmd.RunE = executeAuthLogin(iostreams, loginFlows{
+			clientCredentials: func(ctx context.Context, credentials auth.Credentials) (auth.AccessToken, error) {
+				t.Error("No token must be requested")
+				return auth.AccessToken{}, nil
+			},
+		})
+
+		// act
+		cmd.SetArgs([]string{})
+		err := cmd.Execute()
+
+		// assert
+		assert.EqualError(t, err, tc.Error, tc.Purpose)
+		var usageErr *clierrors.UsageError
+		assert.True(t, errors.As(err, &usageErr), tc.Purpose)
+	}
+}
@@ -0,0 +1,54 @@
+package authcmd
+
+import (
+	"fmt"
+
+	"github.com/renato0307/learning-go-cli/internal/auth"
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/spf13/cobra"
+)
+
+// NewAuthLogoutCmd represents the auth logout command
+func NewAuthLogoutCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logout",
+		Short: "Removes the credentials of the profile",
+		Long: `Removes the cached access tokens, the refresh token of interactive logins
+and the client secret of the profile, so no more calls to the API can be made
+until logging in again. The other settings, like the endpoints and the client
+id, are kept.
+
+Secrets are removed from the secret store and from the config file, including
+the settings at its top level used before profiles existed. The command fails
+if a secret can still be read, like from an environment variable.`,
+		Args: clierrors.UsageArgs(cobra.NoArgs),
+		RunE: executeAuthLogout(iostreams),
+	}
+
+	return cmd
+}
+
+// executeAuthLogout implements all the logic associated with this command.
+func executeAuthLogout(iostreams *iostreams.IOStreams) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		err := auth.RemoveCachedToken()
+		if err != nil {
+			return fmt.Errorf("error removing the cached tokens: %w", err)
+		}
+
+		err = config.DeleteSecret(config.RefreshTokenFlag)
+		if err != nil {
+			return fmt.Errorf("error removing the refresh token: %w", err)
+		}
+
+		err = config.DeleteSecret(config.ClientSecretFlag)
+		if err != nil {
+			return fmt.Errorf("error removing the client secret: %w", err)
+		}
+
+		_, err = iostreams.Fprint(fmt.Sprintf("logged out of profile %q\n", config.Profile()))
+		return err
+	}
+}
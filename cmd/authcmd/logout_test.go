@@ -0,0 +1,109 @@
+package authcmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/auth"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAuthLogoutCmd(t *testing.T) {
+	// act
+	cmd := NewAuthLogoutCmd(nil)
+
+	// assert
+	assert.Equal(t, "logout", cmd.Use)
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+}
+
+func TestExecuteAuthLogout(t *testing.T) {
+	// arrange
+	t.Setenv("HOME", t.TempDir())
+	fileName := config.CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+
+	cacheFile, err := config.TokenCacheFile()
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Dir(fileName), filepath.Dir(cacheFile))
+	cache := auth.NewTokenCache(cacheFile)
+	key := auth.CacheKey(auth.CredentialsFromConfig(), auth.GrantClientCredentials)
+	err = cache.Put(key, auth.CachedToken{AccessToken: auth.AccessToken{AccessToken: "token"}})
+	assert.NoError(t, err)
+	err = config.SetSecret(config.RefreshTokenFlag, "refresh_token")
+	assert.NoError(t, err)
+
+	iostreams, _, out, _ := iostreams.Test()
+	cmd := NewAuthLogoutCmd(iostreams)
+
+	// act
+	cmd.SetArgs([]string{})
+	err = cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "logged out of profile \"default\"\n", out.String())
+	assert.Empty(t, config.GetString(config.ClientSecretFlag), "The client secret must be removed")
+	assert.Empty(t, config.GetString(config.RefreshTokenFlag), "The refresh token must be removed")
+	assert.Equal(t, "fake_client_id", config.GetString(config.ClientIdFlag), "Other settings must be kept")
+	_, found, err := cache.Get(key)
+	assert.NoError(t, err)
+	assert.False(t, found, "The cached token must be removed")
+}
+
+func TestExecuteAuthLogoutFlatConfig(t *testing.T) {
+	// arrange
+	t.Setenv("HOME", t.TempDir())
+	fileName := filepath.Join(t.TempDir(), "config.yaml")
+	err := ioutil.WriteFile(fileName, []byte("client-id: flat_client_id\n"+
+		"client-secret: flat_client_secret\n"+
+		"refresh-token: flat_refresh_token\n"+
+		"secret-store: plaintext\n"), 0600)
+	assert.NoError(t, err)
+	viper.Reset()
+	defer viper.Reset()
+	viper.SetConfigFile(fileName)
+	viper.ReadInConfig()
+
+	iostreams, _, out, _ := iostreams.Test()
+	cmd := NewAuthLogoutCmd(iostreams)
+
+	// act
+	cmd.SetArgs([]string{})
+	err = cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "logged out of profile \"default\"\n", out.String())
+	assert.Empty(t, config.GetString(config.ClientSecretFlag), "The client secret must be removed")
+	assert.Empty(t, config.GetString(config.RefreshTokenFlag), "The refresh token must be removed")
+	content, _ := ioutil.ReadFile(fileName)
+	assert.Equal(t, "client-id: flat_client_id\nsecret-store: plaintext\n", string(content))
+}
+
+func TestExecuteAuthLogoutWithSecretInEnvironment(t *testing.T) {
+	// arrange
+	t.Setenv("HOME", t.TempDir())
+	fileName := config.CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+	t.Setenv(config.EnvVar(config.ClientSecretFlag), "env_client_secret")
+
+	iostreams, _, out, _ := iostreams.Test()
+	cmd := NewAuthLogoutCmd(iostreams)
+
+	// act
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+
+	// assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "LEARNING_GO_CLI_CLIENT_SECRET")
+	assert.Empty(t, out.String(), "Success must not be reported")
+}
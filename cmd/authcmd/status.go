@@ -0,0 +1,172 @@
+package authcmd
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/renato0307/learning-go-cli/internal/api"
+	"github.com/renato0307/learning-go-cli/internal/auth"
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/renato0307/learning-go-cli/internal/jwt"
+	"github.com/renato0307/learning-go-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// AuthStatus represents the result of getting a token with the credentials
+// of the profile
+type AuthStatus struct {
+	Profile       string   `json:"profile"`
+	ClientId      string   `json:"client_id"`
+	APIEndpoint   string   `json:"api_endpoint"`
+	TokenEndpoint string   `json:"token_endpoint"`
+	TokenType     string   `json:"token_type"`
+	ExpiresAt     string   `json:"expires_at,omitempty"`
+	Scopes        []string `json:"scopes"`
+	MissingScopes []string `json:"missing_scopes,omitempty"`
+}
+
+// NewAuthStatusCmd represents the auth status command
+func NewAuthStatusCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Checks if the credentials work",
+		Long: `Checks if the credentials of the profile work, getting a new access token
+from the token endpoint, and reports the type of the token, when it expires,
+its scopes and the endpoints used.
+
+The token is requested with the configured scopes only. The scopes granted
+are compared with the scopes required by each command, and the ones missing
+are reported with the commands requiring them, which may fail calling the
+API if the token endpoint does not grant them when they are requested.
+
+Cached tokens are not used, so invalid credentials are always detected.`,
+		Args: clierrors.UsageArgs(cobra.NoArgs),
+		RunE: executeAuthStatus(iostreams, auth.NewAccessToken, time.Now),
+	}
+
+	return cmd
+}
+
+// executeAuthStatus implements all the logic associated with this command.
+func executeAuthStatus(iostreams *iostreams.IOStreams, newToken api.TokenSource, now func() time.Time) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		requested := strings.Fields(config.GetString(config.ScopesFlag))
+
+		issuedAt := now()
+		token, err := newToken(auth.WithScopes(cmd.Context(), requested))
+		if err != nil {
+			return err
+		}
+
+		// without scopes in the response the token has the requested ones
+		granted := tokenScopes(token)
+		if len(granted) == 0 {
+			granted = requested
+		}
+
+		status := AuthStatus{
+			Profile:       config.Profile(),
+			ClientId:      config.GetString(config.ClientIdFlag),
+			APIEndpoint:   config.GetString(config.APIEndpointFlag),
+			TokenEndpoint: config.GetString(config.TokenEndpointFlag),
+			TokenType:     token.TokenType,
+			Scopes:        granted,
+		}
+		if token.ExpiresIn > 0 {
+			expiresAt := issuedAt.Add(time.Duration(token.ExpiresIn) * time.Second)
+			status.ExpiresAt = expiresAt.UTC().Format(time.RFC3339)
+		}
+
+		status.MissingScopes = missingScopes(iostreams, requiredScopes(cmd.Root()), granted)
+
+		return output.Print(iostreams, status)
+	}
+}
+
+// requiredScopes returns the scopes required by the commands of the tree
+// declaring them, by command path. The subcommands inheriting the scopes of
+// a command are not repeated.
+func requiredScopes(root *cobra.Command) map[string][]string {
+	scopes := map[string][]string{}
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		if cmd.Annotations[config.ScopesAnnotation] != "" {
+			scopes[cmd.CommandPath()] = config.RequiredScopes(cmd)
+		}
+		for _, child := range cmd.Commands() {
+			walk(child)
+		}
+	}
+	walk(root)
+	return scopes
+}
+
+// allScopes returns the scopes required by any of the commands, sorted
+func allScopes(commandScopes map[string][]string) []string {
+	all := []string{}
+	for _, scopes := range commandScopes {
+		all = auth.MergeScopes(all, scopes)
+	}
+	sort.Strings(all)
+	return all
+}
+
+// missingScopes warns about the commands requiring scopes not granted,
+// returning all the missing scopes
+func missingScopes(iostreams *iostreams.IOStreams, commandScopes map[string][]string, granted []string) []string {
+	grantedSet := map[string]bool{}
+	for _, scope := range granted {
+		grantedSet[scope] = true
+	}
+
+	paths := []string{}
+	for path := range commandScopes {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	missing := []string{}
+	for _, path := range paths {
+		commandMissing := []string{}
+		for _, scope := range commandScopes[path] {
+			if !grantedSet[scope] {
+				commandMissing = append(commandMissing, scope)
+			}
+		}
+		if len(commandMissing) > 0 {
+			iostreams.Errorf("warning: the token is missing the scopes %s required by %s\n",
+				strings.Join(commandMissing, " "),
+				path)
+			missing = auth.MergeScopes(missing, commandMissing)
+		}
+	}
+	return missing
+}
+
+// tokenScopes returns the scopes granted to the token, from the token
+// response or, when missing, from the scope or scp claims of JWT tokens
+func tokenScopes(token auth.AccessToken) []string {
+	if token.Scope != "" {
+		return strings.Fields(token.Scope)
+	}
+
+	scopes := []string{}
+	decoded, err := jwt.Decode(token.AccessToken)
+	if err != nil {
+		return scopes
+	}
+	if claim, ok := decoded.Claims["scope"].(string); ok {
+		return strings.Fields(claim)
+	}
+	if claim, ok := decoded.Claims["scp"].([]interface{}); ok {
+		for _, scope := range claim {
+			if text, ok := scope.(string); ok {
+				scopes = append(scopes, text)
+			}
+		}
+	}
+	return scopes
+}
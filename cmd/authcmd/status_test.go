@@ -0,0 +1,169 @@
+package authcmd
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/renato0307/learning-go-cli/internal/auth"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAuthStatusCmd(t *testing.T) {
+	// act
+	cmd := NewAuthStatusCmd(nil)
+
+	// assert
+	assert.Equal(t, "status", cmd.Use)
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+}
+
+func TestExecuteAuthStatus(t *testing.T) {
+	// arrange
+	t.Setenv("HOME", t.TempDir())
+	fileName := config.CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+
+	iostreams, _, out, _ := iostreams.Test()
+	cmd := NewAuthStatusCmd(iostreams)
+	newToken := func(ctx context.Context) (auth.AccessToken, error) {
+		return auth.AccessToken{
+			AccessToken: "token",
+			ExpiresIn:   3600,
+			TokenType:   "Bearer",
+			Scope:       "uuid:write currency:read",
+		}, nil
+	}
+	now := func() time.Time { return time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC) }
+	cmd.RunE = executeAuthStatus(iostreams, newToken, now)
+
+	// act
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, `{
+  "profile": "default",
+  "client_id": "fake_client_id",
+  "api_endpoint": "fake_endpoint",
+  "token_endpoint": "fake_endpoint",
+  "token_type": "Bearer",
+  "expires_at": "2022-02-22T20:22:22Z",
+  "scopes": [
+    "uuid:write",
+    "currency:read"
+  ]
+}
+`, out.String())
+}
+
+func TestExecuteAuthStatusWithMissingScopes(t *testing.T) {
+	// arrange
+	t.Setenv("HOME", t.TempDir())
+	fileName := config.CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+	config.Set(config.ScopesFlag, "profile")
+
+	run := func(cmd *cobra.Command, args []string) error { return nil }
+	root := &cobra.Command{Use: "cli"}
+	convert := &cobra.Command{Use: "convert", RunE: run}
+	config.RequireScopes(convert, "currency:read")
+	uuid := &cobra.Command{Use: "uuid", RunE: run}
+	config.RequireScopes(uuid, "uuid:write", "uuid:read")
+	uuid.AddCommand(&cobra.Command{Use: "parse", RunE: run})
+	root.AddCommand(convert, uuid)
+
+	iostreams, _, out, errOut := iostreams.Test()
+	cmd := NewAuthStatusCmd(iostreams)
+	root.AddCommand(cmd)
+	requested := []string{}
+	newToken := func(ctx context.Context) (auth.AccessToken, error) {
+		// the scopes of the context are requested by the token source
+		requested = auth.CredentialsFor(ctx).Scopes
+		return auth.AccessToken{AccessToken: "token", Scope: "profile uuid:write"}, nil
+	}
+	cmd.RunE = executeAuthStatus(iostreams, newToken, time.Now)
+
+	// act
+	root.SetArgs([]string{"status"})
+	err := root.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"profile"}, requested, "Only the configured scopes must be requested")
+	assert.Equal(t, "warning: the token is missing the scopes currency:read required by cli convert\n"+
+		"warning: the token is missing the scopes uuid:read required by cli uuid\n", errOut.String())
+	assert.Contains(t, out.String(), `"missing_scopes": [
+    "currency:read",
+    "uuid:read"
+  ]`)
+}
+
+func TestExecuteAuthStatusWithInvalidCredentials(t *testing.T) {
+	// arrange
+	iostreams, _, out, _ := iostreams.Test()
+	cmd := NewAuthStatusCmd(iostreams)
+	newToken := func(ctx context.Context) (auth.AccessToken, error) {
+		return auth.AccessToken{}, &auth.TokenError{StatusCode: 401, Message: "invalid_client"}
+	}
+	cmd.RunE = executeAuthStatus(iostreams, newToken, time.Now)
+
+	// act
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+
+	// assert
+	assert.EqualError(t, err, "error getting token: invalid_client (HTTP 401 Unauthorized)")
+	assert.Empty(t, out.String())
+}
+
+func TestTokenScopes(t *testing.T) {
+	// arrange
+	jwtToken := func(claims string) string {
+		encode := base64.RawURLEncoding.EncodeToString
+		return encode([]byte(`{"alg":"none"}`)) + "." + encode([]byte(claims)) + "."
+	}
+
+	testCases := []struct {
+		Purpose  string
+		Token    auth.AccessToken
+		Expected []string
+	}{
+		{
+			Purpose:  "scopes in the response",
+			Token:    auth.AccessToken{AccessToken: "opaque", Scope: "a b"},
+			Expected: []string{"a", "b"},
+		},
+		{
+			Purpose:  "scope claim",
+			Token:    auth.AccessToken{AccessToken: jwtToken(`{"scope":"a b"}`)},
+			Expected: []string{"a", "b"},
+		},
+		{
+			Purpose:  "scp claim",
+			Token:    auth.AccessToken{AccessToken: jwtToken(`{"scp":["a","b"]}`)},
+			Expected: []string{"a", "b"},
+		},
+		{
+			Purpose:  "opaque token without scopes",
+			Token:    auth.AccessToken{AccessToken: "opaque"},
+			Expected: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		// act
+		scopes := tokenScopes(tc.Token)
+
+		// assert
+		assert.Equal(t, tc.Expected, scopes, tc.Purpose)
+	}
+}
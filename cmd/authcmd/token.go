@@ -0,0 +1,43 @@
+package authcmd
+
+import (
+	"fmt"
+
+	"github.com/renato0307/learning-go-cli/internal/api"
+	"github.com/renato0307/learning-go-cli/internal/auth"
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/spf13/cobra"
+)
+
+// NewAuthTokenCmd represents the auth token command
+func NewAuthTokenCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Prints an access token",
+		Long: `Prints an access token for the profile, reusing the cached one while it
+is valid, to call the API with other tools, like:
+
+  curl -H "Authorization: Bearer $(learning-go-cli auth token)" ...`,
+		Args: clierrors.UsageArgs(cobra.NoArgs),
+		RunE: executeAuthToken(iostreams, auth.GetAccessToken),
+	}
+
+	return cmd
+}
+
+// executeAuthToken implements all the logic associated with this command.
+func executeAuthToken(iostreams *iostreams.IOStreams, getToken api.TokenSource) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		token, err := getToken(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		_, err = iostreams.Fprint(token.AccessToken + "\n")
+		if err != nil {
+			return fmt.Errorf("error writing to the output: %w", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,59 @@
+package authcmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/auth"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAuthTokenCmd(t *testing.T) {
+	// act
+	cmd := NewAuthTokenCmd(nil)
+
+	// assert
+	assert.Equal(t, "token", cmd.Use)
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+}
+
+func TestExecuteAuthToken(t *testing.T) {
+	testCases := []struct {
+		Purpose  string
+		Token    auth.AccessToken
+		Err      error
+		Expected string
+	}{
+		{
+			Purpose:  "token",
+			Token:    auth.AccessToken{AccessToken: "eyJhbGciOiJub25lIn0.e30."},
+			Expected: "eyJhbGciOiJub25lIn0.e30.\n",
+		},
+		{
+			Purpose: "error getting the token",
+			Err:     errors.New("invalid credentials"),
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		iostreams, _, out, _ := iostreams.Test()
+		cmd := NewAuthTokenCmd(iostreams)
+		getToken := func(ctx context.Context) (auth.AccessToken, error) {
+			return tc.Token, tc.Err
+		}
+		cmd.RunE = executeAuthToken(iostreams, getToken)
+
+		// act
+		cmd.SetArgs([]string{})
+		err := cmd.Execute()
+
+		// assert
+		assert.Equal(t, tc.Err, err, tc.Purpose)
+		assert.Equal(t, tc.Expected, out.String(), tc.Purpose)
+	}
+}
@@ -0,0 +1,89 @@
+package configcmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/spf13/cobra"
+)
+
+const ShowSecretsFlag string = "show-secrets"
+
+// maskedSecret replaces the values of secret settings in the output
+const maskedSecret string = "********"
+
+// SettingValue represents the effective value of a setting and where it
+// comes from
+type SettingValue struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// NewConfigCmd represents the config command
+func NewConfigCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manages the CLI configuration",
+		Long: `Allows to read and change individual settings of the configuration,
+list the effective configuration and validate it.`,
+		RunE: executeConfig(),
+	}
+
+	cmd.AddCommand(NewConfigGetCmd(iostreams))
+	cmd.AddCommand(NewConfigSetCmd(iostreams))
+	cmd.AddCommand(NewConfigUnsetCmd(iostreams))
+	cmd.AddCommand(NewConfigListCmd(iostreams))
+	cmd.AddCommand(NewConfigPathCmd(iostreams))
+	cmd.AddCommand(NewConfigValidateCmd(iostreams))
+	cmd.AddCommand(NewConfigMigrateSecretsCmd(iostreams))
+
+	return cmd
+}
+
+// executeConfig implements all the logic associated with this command.
+// In this case as it is an aggregation command will return an error
+func executeConfig() func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		return clierrors.Usagef("must specify a subcommand")
+	}
+}
+
+// findSetting returns the setting with the given key or an error listing the
+// known settings if it does not exist
+func findSetting(key string) (config.Setting, error) {
+	setting, found := config.FindSetting(key)
+	if !found {
+		keys := []string{}
+		for _, s := range config.Settings {
+			keys = append(keys, s.Key)
+		}
+		return setting, fmt.Errorf("unknown setting %q, valid settings are: %s",
+			key,
+			strings.Join(keys, ", "))
+	}
+	return setting, nil
+}
+
+// displayValue returns the value to show for a setting, masking secrets
+// unless showSecrets is set
+func displayValue(setting config.Setting, value string, showSecrets bool) string {
+	if setting.Secret && value != "" && !showSecrets {
+		return maskedSecret
+	}
+	return value
+}
+
+// settingValue returns the effective value of the setting, masking secrets
+// unless showSecrets is set
+func settingValue(setting config.Setting, showSecrets bool) SettingValue {
+	_, source := config.Lookup(setting.Key)
+	return SettingValue{
+		Key:    setting.Key,
+		Value:  displayValue(setting, config.GetString(setting.Key), showSecrets),
+		Source: string(source),
+	}
+}
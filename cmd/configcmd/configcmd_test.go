@@ -0,0 +1,31 @@
+package configcmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConfigCmd(t *testing.T) {
+	// act
+	cmd := NewConfigCmd(nil)
+
+	// assert
+	assert.Equal(t, "config", cmd.Use)
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+	assert.Len(t, cmd.Commands(), 7)
+}
+
+func TestExecute(t *testing.T) {
+	// arrange
+	cmd := NewConfigCmd(nil)
+	cmd.SetArgs([]string{})
+
+	// act
+	err := cmd.Execute()
+
+	// assert
+	assert.Error(t, err)
+}
@@ -0,0 +1,44 @@
+package configcmd
+
+import (
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/renato0307/learning-go-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// NewConfigGetCmd represents the config get command
+func NewConfigGetCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get <key>",
+		Short: "Prints the value of a setting",
+		Long: `Prints the effective value of a setting for the selected profile and
+where the value comes from (flag, env, file or default). Secrets are masked
+unless --show-secrets is set.
+
+The setting is printed with the selected output format. Use --jq .value to
+print only the value, like:
+  learning-go-cli config get client-id --jq .value`,
+		Args: clierrors.UsageArgs(cobra.ExactArgs(1)),
+		RunE: executeConfigGet(iostreams),
+	}
+
+	cmd.Flags().Bool(ShowSecretsFlag,
+		false,
+		"if set the values of secrets are printed")
+
+	return cmd
+}
+
+// executeConfigGet implements all the logic associated with this command.
+func executeConfigGet(iostreams *iostreams.IOStreams) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		setting, err := findSetting(args[0])
+		if err != nil {
+			return err
+		}
+		showSecrets, _ := cmd.Flags().GetBool(ShowSecretsFlag)
+
+		return output.Print(iostreams, settingValue(setting, showSecrets))
+	}
+}
@@ -0,0 +1,94 @@
+package configcmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConfigGetCmd(t *testing.T) {
+	// act
+	cmd := NewConfigGetCmd(nil)
+
+	// assert
+	assert.Equal(t, "get <key>", cmd.Use)
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+	assert.NotNil(t, cmd.Flags().Lookup(ShowSecretsFlag))
+}
+
+func TestExecuteConfigGet(t *testing.T) {
+	testCases := []struct {
+		Args     []string
+		Format   string
+		Filter   string
+		Output   string
+		ErrorNil bool
+		Purpose  string
+	}{
+		{
+			Args:     []string{config.ClientIdFlag},
+			Output:   "{\n  \"key\": \"client-id\",\n  \"value\": \"fake_client_id\",\n  \"source\": \"file\"\n}\n",
+			ErrorNil: true,
+			Purpose:  "regular setting",
+		},
+		{
+			Args:     []string{config.ClientSecretFlag},
+			Format:   config.OutputYAML,
+			Output:   "key: client-secret\nsource: file\nvalue: '" + maskedSecret + "'\n",
+			ErrorNil: true,
+			Purpose:  "secret setting is masked",
+		},
+		{
+			Args:     []string{config.ClientSecretFlag, fmt.Sprintf("--%s", ShowSecretsFlag)},
+			Filter:   ".value",
+			Output:   "fake_client_secret\n",
+			ErrorNil: true,
+			Purpose:  "secret setting is shown",
+		},
+		{
+			Args:     []string{config.TokenSkewFlag},
+			Filter:   `.value + " " + .source`,
+			Output:   "30s default\n",
+			ErrorNil: true,
+			Purpose:  "default value",
+		},
+		{
+			Args:     []string{"unknown"},
+			ErrorNil: false,
+			Purpose:  "unknown setting",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		fileName := config.CreateFakeConfigFile(t)
+		defer os.Remove(fileName)
+		if tc.Format != "" {
+			config.Set(config.OutputFlag, tc.Format)
+		}
+		config.Set(config.JqFlag, tc.Filter)
+
+		buffer := &bytes.Buffer{}
+		iostreams := &iostreams.IOStreams{Out: buffer}
+		cmd := NewConfigGetCmd(iostreams)
+
+		// act
+		cmd.SetArgs(tc.Args)
+		err := cmd.Execute()
+
+		// assert
+		if tc.ErrorNil {
+			assert.NoError(t, err, "error found for "+tc.Purpose)
+			assert.Equal(t, tc.Output, buffer.String(), "invalid output for "+tc.Purpose)
+		} else {
+			assert.Error(t, err, "error not found for "+tc.Purpose)
+		}
+	}
+}
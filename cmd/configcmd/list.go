@@ -0,0 +1,45 @@
+package configcmd
+
+import (
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/renato0307/learning-go-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// NewConfigListCmd represents the config list command
+func NewConfigListCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists the effective configuration",
+		Long: `Lists all the settings for the selected profile, with their effective
+value and where the value comes from (flag, env, file or default).
+Secrets are masked unless --show-secrets is set.
+
+The settings are printed with the selected output format, so --output table
+shows them as a table and --jq selects some of them, like
+--jq '.[] | select(.source == "file") | .key'.`,
+		Args: clierrors.UsageArgs(cobra.NoArgs),
+		RunE: executeConfigList(iostreams),
+	}
+
+	cmd.Flags().Bool(ShowSecretsFlag,
+		false,
+		"if set the values of secrets are printed")
+
+	return cmd
+}
+
+// executeConfigList implements all the logic associated with this command.
+func executeConfigList(iostreams *iostreams.IOStreams) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		showSecrets, _ := cmd.Flags().GetBool(ShowSecretsFlag)
+
+		settings := []SettingValue{}
+		for _, setting := range config.Settings {
+			settings = append(settings, settingValue(setting, showSecrets))
+		}
+		return output.Print(iostreams, settings)
+	}
+}
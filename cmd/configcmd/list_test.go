@@ -0,0 +1,86 @@
+package configcmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConfigListCmd(t *testing.T) {
+	// act
+	cmd := NewConfigListCmd(nil)
+
+	// assert
+	assert.Equal(t, "list", cmd.Use)
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+	assert.NotNil(t, cmd.Flags().Lookup(ShowSecretsFlag))
+}
+
+func TestExecuteConfigList(t *testing.T) {
+	testCases := []struct {
+		Args           []string
+		Format         string
+		OutputContains []string
+		OutputExcludes []string
+		Purpose        string
+	}{
+		{
+			Args: []string{},
+			OutputContains: []string{
+				`"key": "client-id"`,
+				"fake_client_id",
+				maskedSecret,
+				"30s",
+				string(config.SourceFile),
+				string(config.SourceDefault),
+			},
+			OutputExcludes: []string{"fake_client_secret"},
+			Purpose:        "secrets masked",
+		},
+		{
+			Args:           []string{fmt.Sprintf("--%s", ShowSecretsFlag)},
+			OutputContains: []string{"fake_client_secret"},
+			OutputExcludes: []string{maskedSecret},
+			Purpose:        "secrets shown",
+		},
+		{
+			Args:           []string{},
+			Format:         config.OutputTable,
+			OutputContains: []string{"KEY", "SOURCE", "VALUE", "fake_client_id", maskedSecret},
+			Purpose:        "table output",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		fileName := config.CreateFakeConfigFile(t)
+		defer os.Remove(fileName)
+		if tc.Format != "" {
+			config.Set(config.OutputFlag, tc.Format)
+		}
+
+		buffer := &bytes.Buffer{}
+		iostreams := &iostreams.IOStreams{Out: buffer}
+		cmd := NewConfigListCmd(iostreams)
+
+		// act
+		cmd.SetArgs(tc.Args)
+		err := cmd.Execute()
+
+		// assert
+		assert.NoError(t, err, "error found for "+tc.Purpose)
+		for _, s := range tc.OutputContains {
+			assert.Contains(t, buffer.String(), s, "missing output for "+tc.Purpose)
+		}
+		for _, s := range tc.OutputExcludes {
+			assert.NotContains(t, buffer.String(), s, "unexpected output for "+tc.Purpose)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package configcmd
+
+import (
+	"fmt"
+
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/spf13/cobra"
+)
+
+const ToFlag string = "to"
+
+// NewConfigMigrateSecretsCmd represents the config migrate-secrets command
+func NewConfigMigrateSecretsCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate-secrets",
+		Short: "Moves secrets out of the config file",
+		Long: fmt.Sprintf(`Moves the secrets stored in plain text in the config file, for all
+the profiles, to a secret store and makes it the current one.
+
+The keyring store uses the OS keyring. The file store keeps secrets in an
+encrypted file, using the passphrase in the %s environment
+variable.`, config.PassphraseEnvVar),
+		Args: clierrors.UsageArgs(cobra.NoArgs),
+		RunE: executeConfigMigrateSecrets(iostreams),
+	}
+
+	cmd.Flags().String(ToFlag,
+		config.SecretStoreKeyring,
+		"the secret store to move the secrets to: keyring or file")
+
+	return cmd
+}
+
+// executeConfigMigrateSecrets implements all the logic associated with this
+// command.
+func executeConfigMigrateSecrets(iostreams *iostreams.IOStreams) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		to, _ := cmd.Flags().GetString(ToFlag)
+		if to == config.SecretStorePlaintext {
+			return clierrors.Usagef("secrets can only be migrated to the keyring or file stores")
+		}
+
+		store, err := config.NewSecretStore(to)
+		if err != nil {
+			return err
+		}
+
+		migrated, err := config.MigrateSecrets(store)
+		for _, name := range migrated {
+			fmt.Fprintf(iostreams.Out, "- %s moved to the %s store\n", name, store.Name())
+		}
+		if err != nil {
+			return fmt.Errorf("error migrating the secrets: %w", err)
+		}
+
+		fmt.Fprintf(iostreams.Out, "configuration updated!")
+		return nil
+	}
+}
@@ -0,0 +1,77 @@
+package configcmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando/go-keyring"
+)
+
+func TestNewConfigMigrateSecretsCmd(t *testing.T) {
+	// act
+	cmd := NewConfigMigrateSecretsCmd(nil)
+
+	// assert
+	assert.Equal(t, "migrate-secrets", cmd.Use)
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+	assert.NotNil(t, cmd.Flags().Lookup(ToFlag))
+}
+
+func TestExecuteConfigMigrateSecrets(t *testing.T) {
+	testCases := []struct {
+		Args           []string
+		OutputContains string
+		ErrorNil       bool
+		Purpose        string
+	}{
+		{
+			Args:           []string{},
+			OutputContains: "default/client-secret moved to the keyring store",
+			ErrorNil:       true,
+			Purpose:        "migration to the keyring",
+		},
+		{
+			Args:     []string{fmt.Sprintf("--%s", ToFlag), config.SecretStorePlaintext},
+			ErrorNil: false,
+			Purpose:  "migration to plaintext",
+		},
+		{
+			Args:     []string{fmt.Sprintf("--%s", ToFlag), "unknown"},
+			ErrorNil: false,
+			Purpose:  "migration to unknown store",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		keyring.MockInit()
+		fileName := config.CreateFakeConfigFile(t)
+		defer os.Remove(fileName)
+		viper.ReadInConfig()
+
+		buffer := &bytes.Buffer{}
+		iostreams := &iostreams.IOStreams{Out: buffer}
+		cmd := NewConfigMigrateSecretsCmd(iostreams)
+
+		// act
+		cmd.SetArgs(tc.Args)
+		err := cmd.Execute()
+
+		// assert
+		if tc.ErrorNil {
+			assert.NoError(t, err, "error found for "+tc.Purpose)
+			assert.Contains(t, buffer.String(), tc.OutputContains)
+			assert.Equal(t, config.SecretStoreKeyring, config.GetString(config.SecretStoreFlag))
+		} else {
+			assert.Error(t, err, "error not found for "+tc.Purpose)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package configcmd
+
+import (
+	"fmt"
+
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/spf13/cobra"
+)
+
+// NewConfigPathCmd represents the config path command
+func NewConfigPathCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "path",
+		Short: "Prints the config file path",
+		Long:  `Prints the path of the file where the configuration is stored.`,
+		Args:  clierrors.UsageArgs(cobra.NoArgs),
+		RunE:  executeConfigPath(iostreams),
+	}
+
+	return cmd
+}
+
+// executeConfigPath implements all the logic associated with this command.
+func executeConfigPath(iostreams *iostreams.IOStreams) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		configFile, err := config.ConfigFile()
+		if err != nil {
+			return fmt.Errorf("error finding the config file: %w", err)
+		}
+
+		_, err = fmt.Fprintln(iostreams.Out, configFile)
+		if err != nil {
+			return fmt.Errorf("error writing to the output: %w", err)
+		}
+		return nil
+	}
+}
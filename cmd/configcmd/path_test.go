@@ -0,0 +1,40 @@
+package configcmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConfigPathCmd(t *testing.T) {
+	// act
+	cmd := NewConfigPathCmd(nil)
+
+	// assert
+	assert.Equal(t, "path", cmd.Use)
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+}
+
+func TestExecuteConfigPath(t *testing.T) {
+	// arrange
+	fileName := config.CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+
+	buffer := &bytes.Buffer{}
+	iostreams := &iostreams.IOStreams{Out: buffer}
+	cmd := NewConfigPathCmd(iostreams)
+
+	// act
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, fileName+"\n", buffer.String())
+}
@@ -0,0 +1,56 @@
+package configcmd
+
+import (
+	"fmt"
+
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/spf13/cobra"
+)
+
+// NewConfigSetCmd represents the config set command
+func NewConfigSetCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Changes the value of a setting",
+		Long: `Changes the value of a setting in the config file. Settings stored
+per profile are changed in the selected profile and secrets are kept in the
+configured secret store.`,
+		Args: clierrors.UsageArgs(cobra.ExactArgs(2)),
+		RunE: executeConfigSet(iostreams),
+	}
+
+	return cmd
+}
+
+// executeConfigSet implements all the logic associated with this command.
+func executeConfigSet(iostreams *iostreams.IOStreams) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		setting, err := findSetting(args[0])
+		if err != nil {
+			return err
+		}
+
+		value := args[1]
+		if setting.Validate != nil {
+			err = setting.Validate(value)
+			if err != nil {
+				return fmt.Errorf("invalid value for %s: %w", setting.Key, err)
+			}
+		}
+
+		if setting.Secret {
+			err = config.SetSecret(setting.Key, value)
+		} else {
+			config.Set(setting.Key, value)
+			err = config.Write()
+		}
+		if err != nil {
+			return fmt.Errorf("error writing the configuration: %w", err)
+		}
+
+		fmt.Fprintf(iostreams.Out, "configuration updated!")
+		return nil
+	}
+}
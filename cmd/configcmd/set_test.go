@@ -0,0 +1,74 @@
+package configcmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConfigSetCmd(t *testing.T) {
+	// act
+	cmd := NewConfigSetCmd(nil)
+
+	// assert
+	assert.Equal(t, "set <key> <value>", cmd.Use)
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+}
+
+func TestExecuteConfigSet(t *testing.T) {
+	testCases := []struct {
+		Args     []string
+		ErrorNil bool
+		Purpose  string
+	}{
+		{
+			Args:     []string{config.APIEndpointFlag, "https://api.example.com"},
+			ErrorNil: true,
+			Purpose:  "valid value",
+		},
+		{
+			Args:     []string{config.APIEndpointFlag, "not-an-url"},
+			ErrorNil: false,
+			Purpose:  "invalid value",
+		},
+		{
+			Args:     []string{"unknown", "value"},
+			ErrorNil: false,
+			Purpose:  "unknown setting",
+		},
+		{
+			Args:     []string{config.APIEndpointFlag},
+			ErrorNil: false,
+			Purpose:  "missing value",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		fileName := config.CreateFakeConfigFile(t)
+		defer os.Remove(fileName)
+
+		buffer := &bytes.Buffer{}
+		iostreams := &iostreams.IOStreams{Out: buffer}
+		cmd := NewConfigSetCmd(iostreams)
+
+		// act
+		cmd.SetArgs(tc.Args)
+		err := cmd.Execute()
+
+		// assert
+		if tc.ErrorNil {
+			assert.NoError(t, err, "error found for "+tc.Purpose)
+			assert.Equal(t, tc.Args[1], config.GetString(tc.Args[0]))
+			assert.Equal(t, "configuration updated!", buffer.String())
+		} else {
+			assert.Error(t, err, "error not found for "+tc.Purpose)
+		}
+	}
+}
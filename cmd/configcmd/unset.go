@@ -0,0 +1,48 @@
+package configcmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/spf13/cobra"
+)
+
+// NewConfigUnsetCmd represents the config unset command
+func NewConfigUnsetCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unset <key>",
+		Short: "Removes a setting",
+		Long: `Removes a setting from the config file, making its default value
+effective. Settings stored per profile are removed from the selected profile,
+or from the top-level keys of config files created before profiles existed
+when the value is read from them. Unsetting a setting without a value fails.`,
+		Args: clierrors.UsageArgs(cobra.ExactArgs(1)),
+		RunE: executeConfigUnset(iostreams),
+	}
+
+	return cmd
+}
+
+// executeConfigUnset implements all the logic associated with this command.
+func executeConfigUnset(iostreams *iostreams.IOStreams) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		setting, err := findSetting(args[0])
+		if err != nil {
+			return err
+		}
+
+		err = config.Unset(setting.Key)
+		if errors.Is(err, config.ErrNotSet) {
+			return err
+		}
+		if err != nil {
+			return fmt.Errorf("error writing the configuration: %w", err)
+		}
+
+		fmt.Fprintf(iostreams.Out, "configuration updated!")
+		return nil
+	}
+}
@@ -0,0 +1,101 @@
+package configcmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConfigUnsetCmd(t *testing.T) {
+	// act
+	cmd := NewConfigUnsetCmd(nil)
+
+	// assert
+	assert.Equal(t, "unset <key>", cmd.Use)
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+}
+
+func TestExecuteConfigUnset(t *testing.T) {
+	// arrange
+	fileName := config.CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+	config.Set(config.TokenSkewFlag, "1m")
+
+	buffer := &bytes.Buffer{}
+	iostreams := &iostreams.IOStreams{Out: buffer}
+	cmd := NewConfigUnsetCmd(iostreams)
+
+	// act
+	cmd.SetArgs([]string{config.TokenSkewFlag})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "configuration updated!", buffer.String())
+	_, source := config.Lookup(config.TokenSkewFlag)
+	assert.Equal(t, config.SourceDefault, source)
+	assert.Equal(t, "fake_client_id", config.GetString(config.ClientIdFlag))
+}
+
+func TestExecuteConfigUnsetUnknownSetting(t *testing.T) {
+	// arrange
+	cmd := NewConfigUnsetCmd(nil)
+
+	// act
+	cmd.SetArgs([]string{"unknown"})
+	err := cmd.Execute()
+
+	// assert
+	assert.Error(t, err)
+}
+
+func TestExecuteConfigUnsetFlatConfig(t *testing.T) {
+	// arrange
+	fileName := filepath.Join(t.TempDir(), "config.yaml")
+	err := ioutil.WriteFile(fileName, []byte("api-endpoint: https://api.example.com\n"), 0600)
+	assert.NoError(t, err)
+	viper.Reset()
+	defer viper.Reset()
+	viper.SetConfigFile(fileName)
+	viper.ReadInConfig()
+
+	buffer := &bytes.Buffer{}
+	iostreams := &iostreams.IOStreams{Out: buffer}
+	cmd := NewConfigUnsetCmd(iostreams)
+
+	// act
+	cmd.SetArgs([]string{config.APIEndpointFlag})
+	err = cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "configuration updated!", buffer.String())
+	assert.Empty(t, config.GetString(config.APIEndpointFlag))
+}
+
+func TestExecuteConfigUnsetNotSet(t *testing.T) {
+	// arrange
+	fileName := config.CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+
+	buffer := &bytes.Buffer{}
+	iostreams := &iostreams.IOStreams{Out: buffer}
+	cmd := NewConfigUnsetCmd(iostreams)
+
+	// act
+	cmd.SetArgs([]string{config.TokenSkewFlag})
+	err := cmd.Execute()
+
+	// assert
+	assert.EqualError(t, err, "token-expiry-skew is not set")
+	assert.Empty(t, buffer.String())
+}
@@ -0,0 +1,45 @@
+package configcmd
+
+import (
+	"fmt"
+
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/spf13/cobra"
+)
+
+// NewConfigValidateCmd represents the config validate command
+func NewConfigValidateCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validates the configuration",
+		Long: `Validates the configuration of the selected profile, checking that all
+the required settings are defined, values are valid and there are no unknown
+settings in the config file. The problems found are reported in the error
+output.`,
+		Args: clierrors.UsageArgs(cobra.NoArgs),
+		RunE: executeConfigValidate(iostreams),
+	}
+
+	return cmd
+}
+
+// executeConfigValidate implements all the logic associated with this command.
+func executeConfigValidate(iostreams *iostreams.IOStreams) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		problems := config.Validate()
+		if len(problems) == 0 {
+			fmt.Fprintln(iostreams.Out, "configuration is valid!")
+			return nil
+		}
+
+		for _, problem := range problems {
+			iostreams.Errorf("- %s\n", problem)
+		}
+		return &config.InvalidConfigError{
+			Profile: config.Profile(),
+			Err:     fmt.Errorf("invalid configuration: %d problem(s) found", len(problems)),
+		}
+	}
+}
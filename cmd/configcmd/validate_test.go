@@ -0,0 +1,98 @@
+package configcmd
+
+import (
+	"os"
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConfigValidateCmd(t *testing.T) {
+	// act
+	cmd := NewConfigValidateCmd(nil)
+
+	// assert
+	assert.Equal(t, "validate", cmd.Use)
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+}
+
+func TestExecuteConfigValidate(t *testing.T) {
+	testCases := []struct {
+		Settings            map[string]string
+		OutputContains      string
+		ErrorOutputContains string
+		ErrorNil            bool
+		Purpose             string
+	}{
+		{
+			Settings: map[string]string{
+				config.APIEndpointFlag:   "https://api.example.com",
+				config.TokenEndpointFlag: "https://auth.example.com/token",
+			},
+			OutputContains: "configuration is valid!",
+			ErrorNil:       true,
+			Purpose:        "valid configuration",
+		},
+		{
+			Settings: map[string]string{
+				config.APIEndpointFlag:   "https://api.example.com",
+				config.TokenEndpointFlag: "https://auth.example.com/token",
+				config.ClientIdFlag:      "",
+			},
+			ErrorOutputContains: config.ClientIdFlag,
+			ErrorNil:            false,
+			Purpose:             "missing setting",
+		},
+		{
+			Settings: map[string]string{
+				config.APIEndpointFlag:   "https://api.example.com",
+				config.TokenEndpointFlag: "https://auth.example.com/token",
+				"unknown":                "value",
+			},
+			ErrorOutputContains: "unknown",
+			ErrorNil:            false,
+			Purpose:             "unknown setting",
+		},
+		{
+			Settings: map[string]string{
+				config.APIEndpointFlag:   "https://api.example.com",
+				config.TokenEndpointFlag: "https://auth.example.com/token",
+				config.TokenSkewFlag:     "thirty seconds",
+			},
+			ErrorOutputContains: config.TokenSkewFlag,
+			ErrorNil:            false,
+			Purpose:             "invalid value",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		fileName := config.CreateFakeConfigFile(t)
+		defer os.Remove(fileName)
+		for key, value := range tc.Settings {
+			config.Set(key, value)
+		}
+
+		iostreams, _, out, errOut := iostreams.Test()
+		cmd := NewConfigValidateCmd(iostreams)
+
+		// act
+		cmd.SetArgs([]string{})
+		err := cmd.Execute()
+
+		// assert
+		if tc.ErrorNil {
+			assert.NoError(t, err, "error found for "+tc.Purpose)
+			assert.Contains(t, out.String(), tc.OutputContains, "invalid output for "+tc.Purpose)
+			assert.Empty(t, errOut.String(), "unexpected error output for "+tc.Purpose)
+		} else {
+			assert.Error(t, err, "error not found for "+tc.Purpose)
+			assert.Empty(t, out.String(), "unexpected output for "+tc.Purpose)
+			assert.Contains(t, errOut.String(), tc.ErrorOutputContains, "invalid error output for "+tc.Purpose)
+		}
+	}
+}
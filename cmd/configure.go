@@ -3,18 +3,36 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
 	"github.com/renato0307/learning-go-cli/internal/config"
 	"github.com/renato0307/learning-go-cli/internal/iostreams"
 	"github.com/spf13/cobra"
 )
 
+const SetDefaultFlag string = "set-default"
+
 // NewConfigureCommand creates the the configure command
 func NewConfigureCommand(iostreams *iostreams.IOStreams) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "configure",
 		Short: "Configures the CLI",
-		Long:  `Allows to define the API endpoints and the client credentials`,
-		RunE:  executeConfigure(iostreams),
+		Long: fmt.Sprintf(`Allows to define the API endpoints and the client credentials.
+
+The configuration is stored in the profile selected with the --profile flag,
+allowing to keep configurations for several deployments of the API.
+
+The client secret is only used, and required, by the client_secret_basic
+authentication method, the default one. Other methods are selected with
+--auth-method, like private_key_jwt, whose key is then configured with:
+
+  learning-go-cli config set private-key ./client-key.pem
+
+The client secret is stored in the OS keyring by default. Where there is no
+keyring, like in servers without a desktop session, select another secret
+store with --secret-store: file, an encrypted file using the passphrase in
+the %s environment variable, or plaintext, the config file.
+Secrets already stored are not moved, use config migrate-secrets for that.`, config.PassphraseEnvVar),
+		RunE: executeConfigure(iostreams),
 	}
 
 	cmd.Flags().StringP(config.ClientIdFlag,
@@ -26,8 +44,11 @@ func NewConfigureCommand(iostreams *iostreams.IOStreams) *cobra.Command {
 	cmd.Flags().StringP(config.ClientSecretFlag,
 		"s",
 		"",
-		"the client secret to call the API")
-	cmd.MarkFlagRequired(config.ClientSecretFlag)
+		"the client secret to call the API, required by client_secret_basic")
+
+	cmd.Flags().String(config.AuthMethodFlag,
+		"",
+		"how the client authenticates: client_secret_basic, private_key_jwt, tls_client_auth or none")
 
 	cmd.Flags().StringP(config.APIEndpointFlag,
 		"a",
@@ -41,6 +62,14 @@ func NewConfigureCommand(iostreams *iostreams.IOStreams) *cobra.Command {
 		"the endpoint to get authentication tokens")
 	cmd.MarkFlagRequired(config.TokenEndpointFlag)
 
+	cmd.Flags().Bool(SetDefaultFlag,
+		false,
+		"if set the profile becomes the default one")
+
+	cmd.Flags().String(config.SecretStoreFlag,
+		"",
+		"where secrets are stored from now on: keyring, file or plaintext")
+
 	return cmd
 }
 
@@ -52,8 +81,35 @@ func executeConfigure(iostreams *iostreams.IOStreams) func(cmd *cobra.Command, a
 		clientSecret, _ := cmd.Flags().GetString(config.ClientSecretFlag)
 		apiEndpoint, _ := cmd.Flags().GetString(config.APIEndpointFlag)
 		tokenEndpoint, _ := cmd.Flags().GetString(config.TokenEndpointFlag)
+		setDefault, _ := cmd.Flags().GetBool(SetDefaultFlag)
+		secretStore, _ := cmd.Flags().GetString(config.SecretStoreFlag)
+		authMethod, _ := cmd.Flags().GetString(config.AuthMethodFlag)
 
-		err := config.WriteAuthenticationConfig(
+		if secretStore != "" {
+			setting, _ := config.FindSetting(config.SecretStoreFlag)
+			err := setting.Validate(secretStore)
+			if err != nil {
+				return &clierrors.UsageError{Err: err}
+			}
+			config.Set(config.SecretStoreFlag, secretStore)
+		}
+		if authMethod != "" {
+			setting, _ := config.FindSetting(config.AuthMethodFlag)
+			err := setting.Validate(authMethod)
+			if err != nil {
+				return &clierrors.UsageError{Err: err}
+			}
+			config.Set(config.AuthMethodFlag, authMethod)
+		}
+		err := checkClientSecret(config.GetString(config.AuthMethodFlag), clientSecret)
+		if err != nil {
+			return err
+		}
+		if setDefault {
+			config.Set(config.DefaultProfileFlag, config.Profile())
+		}
+
+		err = config.WriteAuthenticationConfig(
 			clientId,
 			clientSecret,
 			apiEndpoint,
@@ -66,3 +122,19 @@ func executeConfigure(iostreams *iostreams.IOStreams) func(cmd *cobra.Command, a
 		return err
 	}
 }
+
+// checkClientSecret checks the client secret is given for the authentication
+// method using it and only for that one
+func checkClientSecret(authMethod string, clientSecret string) error {
+	switch {
+	case authMethod == config.AuthMethodClientSecret && clientSecret == "":
+		return clierrors.Usagef("--%s is required by the %s authentication method",
+			config.ClientSecretFlag,
+			authMethod)
+	case authMethod != config.AuthMethodClientSecret && clientSecret != "":
+		return clierrors.Usagef("--%s is not used by the %s authentication method",
+			config.ClientSecretFlag,
+			authMethod)
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
 	"github.com/renato0307/learning-go-cli/internal/config"
 	"github.com/renato0307/learning-go-cli/internal/iostreams"
 	"github.com/stretchr/testify/assert"
@@ -27,7 +28,7 @@ func TestNewConfigureCommand(t *testing.T) {
 
 func TestExecuteConfigure(t *testing.T) {
 	// arrange
-	// arrange
+	t.Setenv("HOME", t.TempDir())
 	buffer := &bytes.Buffer{}
 	iostreams := &iostreams.IOStreams{Out: buffer}
 	cmd := NewConfigureCommand(iostreams)
@@ -52,3 +53,182 @@ func TestExecuteConfigure(t *testing.T) {
 	assert.Equal(t, "fake-t", config.GetString(config.TokenEndpointFlag))
 	assert.Equal(t, "configuration updated!", buffer.String())
 }
+
+func TestExecuteConfigureWithProfile(t *testing.T) {
+	// arrange
+	t.Setenv("HOME", t.TempDir())
+	buffer := &bytes.Buffer{}
+	iostreams := &iostreams.IOStreams{Out: buffer}
+	cmd := NewConfigureCommand(iostreams)
+
+	fileName := config.CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+	t.Setenv(config.ProfileEnvVar, "staging")
+
+	// act
+	cmd.SetArgs([]string{
+		"-c", "staging-c",
+		"-s", "staging-s",
+		"-a", "staging-a",
+		"-t", "staging-t",
+		"--" + SetDefaultFlag,
+	})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "staging", config.GetString(config.DefaultProfileFlag))
+	assert.Equal(t, "staging-c", config.GetString(config.ClientIdFlag))
+
+	t.Setenv(config.ProfileEnvVar, config.DefaultProfile)
+	assert.Equal(t, "fake_client_id", config.GetString(config.ClientIdFlag))
+}
+
+func TestExecuteConfigureWithSecretStore(t *testing.T) {
+	// arrange
+	t.Setenv("HOME", t.TempDir())
+	buffer := &bytes.Buffer{}
+	iostreams := &iostreams.IOStreams{Out: buffer}
+	cmd := NewConfigureCommand(iostreams)
+
+	fileName := config.CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+	config.Set(config.SecretStoreFlag, config.SecretStoreKeyring)
+
+	// act
+	cmd.SetArgs([]string{
+		"-c", "fake-c",
+		"-s", "fake-s",
+		"-a", "fake-a",
+		"-t", "fake-t",
+		"--" + config.SecretStoreFlag, config.SecretStorePlaintext,
+	})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, config.SecretStorePlaintext, config.GetString(config.SecretStoreFlag))
+	value, source := config.Lookup(config.ClientSecretFlag)
+	assert.Equal(t, "fake-s", value)
+	assert.Equal(t, config.SourceFile, source)
+}
+
+func TestExecuteConfigureWithInvalidSecretStore(t *testing.T) {
+	// arrange
+	t.Setenv("HOME", t.TempDir())
+	cmd := NewConfigureCommand(&iostreams.IOStreams{Out: &bytes.Buffer{}})
+
+	fileName := config.CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+
+	// act
+	cmd.SetArgs([]string{
+		"-c", "fake-c",
+		"-s", "fake-s",
+		"-a", "fake-a",
+		"-t", "fake-t",
+		"--" + config.SecretStoreFlag, "vault",
+	})
+	err := cmd.Execute()
+
+	// assert
+	usageError := &clierrors.UsageError{}
+	assert.ErrorAs(t, err, &usageError)
+	assert.Equal(t, config.SecretStorePlaintext, config.GetString(config.SecretStoreFlag))
+}
+
+func TestExecuteConfigureWithAuthMethod(t *testing.T) {
+	testCases := []struct {
+		Purpose    string
+		AuthMethod string
+		Secret     string
+		Usage      bool
+	}{
+		{
+			Purpose:    "client_secret_basic with the client secret",
+			AuthMethod: config.AuthMethodClientSecret,
+			Secret:     "fake-s",
+		},
+		{
+			Purpose:    "client_secret_basic without the client secret",
+			AuthMethod: config.AuthMethodClientSecret,
+			Usage:      true,
+		},
+		{
+			Purpose: "default method without the client secret",
+			Usage:   true,
+		},
+		{
+			Purpose:    "private_key_jwt without the client secret",
+			AuthMethod: config.AuthMethodPrivateKeyJwt,
+		},
+		{
+			Purpose:    "private_key_jwt with the client secret",
+			AuthMethod: config.AuthMethodPrivateKeyJwt,
+			Secret:     "fake-s",
+			Usage:      true,
+		},
+		{
+			Purpose:    "tls_client_auth without the client secret",
+			AuthMethod: config.AuthMethodTLSClientAuth,
+		},
+		{
+			Purpose:    "tls_client_auth with the client secret",
+			AuthMethod: config.AuthMethodTLSClientAuth,
+			Secret:     "fake-s",
+			Usage:      true,
+		},
+		{
+			Purpose:    "none without the client secret",
+			AuthMethod: config.AuthMethodNone,
+		},
+		{
+			Purpose:    "none with the client secret",
+			AuthMethod: config.AuthMethodNone,
+			Secret:     "fake-s",
+			Usage:      true,
+		},
+		{
+			Purpose:    "unknown method",
+			AuthMethod: "client_secret_post",
+			Secret:     "fake-s",
+			Usage:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		t.Setenv("HOME", t.TempDir())
+		buffer := &bytes.Buffer{}
+		iostreams := &iostreams.IOStreams{Out: buffer}
+		cmd := NewConfigureCommand(iostreams)
+
+		fileName := config.CreateFakeConfigFile(t)
+		args := []string{"-c", "fake-c", "-a", "fake-a", "-t", "fake-t"}
+		if tc.AuthMethod != "" {
+			args = append(args, "--"+config.AuthMethodFlag, tc.AuthMethod)
+		}
+		if tc.Secret != "" {
+			args = append(args, "-s", tc.Secret)
+		}
+
+		// act
+		cmd.SetArgs(args)
+		err := cmd.Execute()
+		os.Remove(fileName)
+
+		// assert
+		if tc.Usage {
+			usageError := &clierrors.UsageError{}
+			assert.ErrorAs(t, err, &usageError, tc.Purpose)
+			assert.Empty(t, buffer.String(), tc.Purpose)
+			continue
+		}
+		assert.NoError(t, err, tc.Purpose)
+		assert.Equal(t, tc.AuthMethod, config.GetString(config.AuthMethodFlag), tc.Purpose)
+		if tc.Secret != "" {
+			assert.Equal(t, tc.Secret, config.GetString(config.ClientSecretFlag), tc.Purpose)
+		}
+		assert.Equal(t, "fake-c", config.GetString(config.ClientIdFlag), tc.Purpose)
+	}
+}
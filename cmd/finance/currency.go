@@ -0,0 +1,63 @@
+package finance
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/spf13/cobra"
+)
+
+// CurrencyScope is the scope the access tokens need to call the currency API
+const CurrencyScope string = "currency:read"
+
+// currencyCodePattern matches ISO 4217 currency codes, like EUR
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// NewFinanceCurrencyCmd represents the finance currency command
+func NewFinanceCurrencyCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "currency",
+		Short: "Currency converter",
+		Long: `Converts amounts between currencies and lists the supported currencies.
+
+The currency API requires the currency:read scope, which is requested in the
+access tokens together with the configured scopes.`,
+		RunE: executeFinanceCurrency(),
+	}
+
+	config.RequireScopes(cmd, CurrencyScope)
+	config.AddCommandWithConfigPreCheck(cmd, NewFinanceCurrencyConvertCmd(iostreams))
+	config.AddCommandWithConfigPreCheck(cmd, NewFinanceCurrencyListCmd(iostreams))
+
+	return cmd
+}
+
+// executeFinanceCurrency implements all the logic associated with this command.
+// In this case as it is an aggregation command will return an error
+func executeFinanceCurrency() func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		return clierrors.Usagef("must specify a subcommand")
+	}
+}
+
+// currencyCode reads the ISO 4217 code in the flag, in upper case
+func currencyCode(cmd *cobra.Command, flag string) (string, error) {
+	value, err := cmd.Flags().GetString(flag)
+	if err != nil {
+		return "", err
+	}
+	if value == "" {
+		return "", clierrors.Usagef("--%s is required", flag)
+	}
+
+	code := strings.ToUpper(value)
+	if !currencyCodePattern.MatchString(code) {
+		return "", clierrors.Usagef("invalid --%s %q, currencies are ISO 4217 codes like EUR or USD",
+			flag,
+			value)
+	}
+	return code, nil
+}
@@ -0,0 +1,79 @@
+package finance
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFinanceCurrencyCmd(t *testing.T) {
+	// act
+	cmd := NewFinanceCurrencyCmd(nil)
+
+	// assert
+	assert.Equal(t, "currency", cmd.Use)
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+
+	for _, name := range []string{"convert", "list"} {
+		subCmd, _, err := cmd.Find([]string{name})
+		assert.NoError(t, err)
+		assert.Equal(t, name, subCmd.Name())
+		assert.NotNil(t, subCmd.PreRunE, "The %s command must check the configuration", name)
+		assert.Equal(t, []string{CurrencyScope}, config.RequiredScopes(subCmd), name)
+	}
+}
+
+func TestExecuteFinanceCurrency(t *testing.T) {
+	// arrange
+	cmd := NewFinanceCurrencyCmd(nil)
+	cmd.SetArgs([]string{})
+
+	// act
+	err := cmd.Execute()
+
+	// assert
+	assert.EqualError(t, err, "must specify a subcommand")
+}
+
+func TestCurrencyCode(t *testing.T) {
+	testCases := []struct {
+		Purpose  string
+		Value    string
+		Expected string
+		Error    string
+	}{
+		{Purpose: "upper case", Value: "EUR", Expected: "EUR"},
+		{Purpose: "lower case", Value: "usd", Expected: "USD"},
+		{Purpose: "missing", Value: "", Error: "--from is required"},
+		{
+			Purpose: "not a code",
+			Value:   "euro",
+			Error:   "invalid --from \"euro\", currencies are ISO 4217 codes like EUR or USD",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		cmd := &cobra.Command{}
+		cmd.Flags().String(FromFlag, tc.Value, "")
+
+		// act
+		code, err := currencyCode(cmd, FromFlag)
+
+		// assert
+		if tc.Error != "" {
+			assert.EqualError(t, err, tc.Error, tc.Purpose)
+			var usageErr *clierrors.UsageError
+			assert.True(t, errors.As(err, &usageErr), tc.Purpose)
+			continue
+		}
+		assert.NoError(t, err, tc.Purpose)
+		assert.Equal(t, tc.Expected, code, tc.Purpose)
+	}
+}
@@ -0,0 +1,102 @@
+package finance
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/renato0307/learning-go-cli/internal/api"
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/decimal"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/renato0307/learning-go-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+const (
+	FromFlag   string = "from"
+	ToFlag     string = "to"
+	AmountFlag string = "amount"
+)
+
+// CurrencyConversion represents the response of the currency convert API.
+// Amounts are decimals so they are never rounded as float64.
+type CurrencyConversion struct {
+	From   string          `json:"from"`
+	To     string          `json:"to"`
+	Amount decimal.Decimal `json:"amount"`
+	Rate   decimal.Decimal `json:"rate"`
+	Result decimal.Decimal `json:"result"`
+}
+
+// NewFinanceCurrencyConvertCmd represents the finance currency convert command
+func NewFinanceCurrencyConvertCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "convert",
+		Short: "Converts an amount between currencies",
+		Long: `Converts an amount from a currency to another, using the exchange rate of
+the API, like:
+
+  learning-go-cli finance currency convert --from EUR --to USD --amount 12.5
+
+Currencies are ISO 4217 codes, listed by the list command. Amounts are exact
+decimals, sent and shown as they are without floating point rounding.`,
+		Args: clierrors.UsageArgs(cobra.NoArgs),
+		RunE: executeFinanceCurrencyConvert(iostreams, api.DefaultFactory),
+	}
+
+	cmd.Flags().String(FromFlag,
+		"",
+		"the currency of the amount, like EUR")
+	cmd.Flags().String(ToFlag,
+		"",
+		"the currency to convert the amount to, like USD")
+	cmd.Flags().String(AmountFlag,
+		"1",
+		"the amount to convert, like 12.5")
+
+	return cmd
+}
+
+// executeFinanceCurrencyConvert implements all the logic associated with this command.
+func executeFinanceCurrencyConvert(iostreams *iostreams.IOStreams, newClient api.Factory) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		from, err := currencyCode(cmd, FromFlag)
+		if err != nil {
+			return err
+		}
+		to, err := currencyCode(cmd, ToFlag)
+		if err != nil {
+			return err
+		}
+
+		// the amount is validated but sent as typed, keeping its precision
+		value, err := cmd.Flags().GetString(AmountFlag)
+		if err != nil {
+			return err
+		}
+		amount, err := decimal.Parse(value)
+		if err != nil {
+			return &clierrors.UsageError{Err: err}
+		}
+		if amount.Sign() < 0 {
+			return clierrors.Usagef("--%s cannot be negative", AmountFlag)
+		}
+
+		query := url.Values{}
+		query.Add("from", from)
+		query.Add("to", to)
+		query.Add("amount", amount.String())
+
+		conversion := CurrencyConversion{}
+		err = newClient().Do(cmd.Context(), api.Request{
+			Method: http.MethodGet,
+			Path:   "/finance/currency/convert",
+			Query:  query,
+		}, &conversion)
+		if err != nil {
+			return err
+		}
+
+		return output.Print(iostreams, conversion)
+	}
+}
@@ -0,0 +1,143 @@
+package finance
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/renato0307/learning-go-cli/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFinanceCurrencyConvertCmd(t *testing.T) {
+	// act
+	cmd := NewFinanceCurrencyConvertCmd(nil)
+
+	// assert
+	assert.Equal(t, "convert", cmd.Use)
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+	for _, flag := range []string{FromFlag, ToFlag, AmountFlag} {
+		assert.NotNil(t, cmd.Flags().Lookup(flag), flag)
+	}
+}
+
+func TestExecuteFinanceCurrencyConvert(t *testing.T) {
+	// arrange
+	iostreams, _, out, _ := iostreams.Test()
+	requester := &testhelpers.FakeRequester{
+		Response: `{
+			"from": "EUR",
+			"to": "USD",
+			"amount": 12345678901234567.5,
+			"rate": 1.0832,
+			"result": "13372839025857403.40"
+		}`,
+	}
+	cmd := NewFinanceCurrencyConvertCmd(iostreams)
+	cmd.RunE = executeFinanceCurrencyConvert(iostreams, requester.Factory())
+
+	// act
+	cmd.SetArgs([]string{"--from", "eur", "--to", "USD", "--amount", "12345678901234567.50"})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, `{
+  "from": "EUR",
+  "to": "USD",
+  "amount": 12345678901234567.5,
+  "rate": 1.0832,
+  "result": 13372839025857403.40
+}
+`, out.String())
+	assert.Len(t, requester.Requests, 1)
+	assert.Equal(t, "/finance/currency/convert", requester.Requests[0].Path)
+	assert.Equal(t, "EUR", requester.Requests[0].Query.Get("from"))
+	assert.Equal(t, "USD", requester.Requests[0].Query.Get("to"))
+	assert.Equal(t, "12345678901234567.50", requester.Requests[0].Query.Get("amount"),
+		"The amount must be sent without rounding")
+}
+
+func TestExecuteFinanceCurrencyConvertWithJqFilter(t *testing.T) {
+	// arrange
+	iostreams, _, out, _ := iostreams.Test()
+	requester := &testhelpers.FakeRequester{
+		Response: `{"from": "EUR", "to": "USD", "result": 13372839025857403.40}`,
+	}
+	config.Set(config.JqFlag, ".result")
+	defer config.Set(config.JqFlag, "")
+	cmd := NewFinanceCurrencyConvertCmd(iostreams)
+	cmd.RunE = executeFinanceCurrencyConvert(iostreams, requester.Factory())
+
+	// act
+	cmd.SetArgs([]string{"--from", "EUR", "--to", "USD", "--amount", "12345678901234567.50"})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "13372839025857403.40\n", out.String(),
+		"The result must be filtered without rounding")
+}
+
+func TestExecuteFinanceCurrencyConvertWithInvalidFlags(t *testing.T) {
+	testCases := []struct {
+		Purpose string
+		Args    []string
+		Error   string
+	}{
+		{
+			Purpose: "missing currency",
+			Args:    []string{"--from", "EUR"},
+			Error:   "--to is required",
+		},
+		{
+			Purpose: "invalid amount",
+			Args:    []string{"--from", "EUR", "--to", "USD", "--amount", "12,5"},
+			Error:   "\"12,5\" is not a decimal number",
+		},
+		{
+			Purpose: "negative amount",
+			Args:    []string{"--from", "EUR", "--to", "USD", "--amount", "-1"},
+			Error:   "--amount cannot be negative",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		iostreams, _, _, _ := iostreams.Test()
+		requester := &testhelpers.FakeRequester{}
+		cmd := NewFinanceCurrencyConvertCmd(iostreams)
+		cmd.RunE = executeFinanceCurrencyConvert(iostreams, requester.Factory())
+
+		// act
+		cmd.SetArgs(tc.Args)
+		err := cmd.Execute()
+
+		// assert
+		assert.EqualError(t, err, tc.Error, tc.Purpose)
+		var usageErr *clierrors.UsageError
+		assert.True(t, errors.As(err, &usageErr), tc.Purpose)
+		assert.Empty(t, requester.Requests, tc.Purpose)
+	}
+}
+
+func TestExecuteFinanceCurrencyConvertWithAPIError(t *testing.T) {
+	// arrange
+	iostreams, _, out, _ := iostreams.Test()
+	requester := &testhelpers.FakeRequester{Err: errors.New("unsupported currency")}
+	cmd := NewFinanceCurrencyConvertCmd(iostreams)
+	cmd.RunE = executeFinanceCurrencyConvert(iostreams, requester.Factory())
+
+	// act
+	cmd.SetArgs([]string{"--from", "EUR", "--to", "XXX"})
+	err := cmd.Execute()
+
+	// assert
+	assert.EqualError(t, err, "unsupported currency")
+	assert.Empty(t, out.String())
+	assert.Equal(t, "1", requester.Requests[0].Query.Get("amount"))
+}
@@ -0,0 +1,47 @@
+package finance
+
+import (
+	"net/http"
+
+	"github.com/renato0307/learning-go-cli/internal/api"
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/renato0307/learning-go-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// Currency represents a currency supported by the API
+type Currency struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// NewFinanceCurrencyListCmd represents the finance currency list command
+func NewFinanceCurrencyListCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Lists the supported currencies",
+		Long: `Lists the currencies supported by the convert command, with their ISO 4217
+code and name.`,
+		Args: clierrors.UsageArgs(cobra.NoArgs),
+		RunE: executeFinanceCurrencyList(iostreams, api.DefaultFactory),
+	}
+
+	return cmd
+}
+
+// executeFinanceCurrencyList implements all the logic associated with this command.
+func executeFinanceCurrencyList(iostreams *iostreams.IOStreams, newClient api.Factory) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		currencies := []Currency{}
+		err := newClient().Do(cmd.Context(), api.Request{
+			Method: http.MethodGet,
+			Path:   "/finance/currency",
+		}, &currencies)
+		if err != nil {
+			return err
+		}
+
+		return output.Print(iostreams, currencies)
+	}
+}
@@ -0,0 +1,83 @@
+package finance
+
+import (
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/renato0307/learning-go-cli/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFinanceCurrencyListCmd(t *testing.T) {
+	// act
+	cmd := NewFinanceCurrencyListCmd(nil)
+
+	// assert
+	assert.Equal(t, "list", cmd.Use)
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+}
+
+func TestExecuteFinanceCurrencyList(t *testing.T) {
+	// arrange
+	iostreams, _, out, _ := iostreams.Test()
+	requester := &testhelpers.FakeRequester{
+		Response: `[{"code": "EUR", "name": "Euro"}, {"code": "USD", "name": "US Dollar"}]`,
+	}
+	cmd := NewFinanceCurrencyListCmd(iostreams)
+	cmd.RunE = executeFinanceCurrencyList(iostreams, requester.Factory())
+
+	// act
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, `[
+  {
+    "code": "EUR",
+    "name": "Euro"
+  },
+  {
+    "code": "USD",
+    "name": "US Dollar"
+  }
+]
+`, out.String())
+	assert.Equal(t, "/finance/currency", requester.Requests[0].Path)
+}
+
+func TestExecuteFinanceCurrencyListWithJqFilter(t *testing.T) {
+	// arrange
+	iostreams, _, out, _ := iostreams.Test()
+	requester := &testhelpers.FakeRequester{
+		Response: `[{"code": "EUR", "name": "Euro"}, {"code": "USD", "name": "US Dollar"}]`,
+	}
+	config.Set(config.JqFlag, "length")
+	defer config.Set(config.JqFlag, "")
+	cmd := NewFinanceCurrencyListCmd(iostreams)
+	cmd.RunE = executeFinanceCurrencyList(iostreams, requester.Factory())
+
+	// act
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "2\n", out.String(), "The filter must run once on the whole list")
+}
+
+func TestExecuteFinanceCurrencyListWithArgs(t *testing.T) {
+	// arrange
+	iostreams, _, _, _ := iostreams.Test()
+	cmd := NewFinanceCurrencyListCmd(iostreams)
+
+	// act
+	cmd.SetArgs([]string{"EUR"})
+	err := cmd.Execute()
+
+	// assert
+	assert.Error(t, err)
+}
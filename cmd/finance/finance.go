@@ -0,0 +1,29 @@
+package finance
+
+import (
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/spf13/cobra"
+)
+
+// NewFinanceCmd represents the finance command
+func NewFinanceCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "finance",
+		Short: "Finance tools",
+		Long:  `Provides several finance tools like a currency converter, etc.`,
+		RunE:  executeFinance(),
+	}
+
+	cmd.AddCommand(NewFinanceCurrencyCmd(iostreams))
+
+	return cmd
+}
+
+// executeFinance implements all the logic associated with this command.
+// In this case as it is an aggregation command will return an error
+func executeFinance() func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		return clierrors.Usagef("must specify a subcommand")
+	}
+}
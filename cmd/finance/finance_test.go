@@ -0,0 +1,33 @@
+package finance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFinanceCmd(t *testing.T) {
+	// act
+	cmd := NewFinanceCmd(nil)
+
+	// assert
+	assert.Equal(t, "finance", cmd.Use)
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+
+	currencyCmd, _, err := cmd.Find([]string{"currency"})
+	assert.NoError(t, err)
+	assert.Equal(t, "currency", currencyCmd.Name())
+}
+
+func TestExecuteFinance(t *testing.T) {
+	// arrange
+	cmd := NewFinanceCmd(nil)
+
+	// act
+	err := cmd.Execute()
+
+	// assert
+	assert.EqualError(t, err, "must specify a subcommand")
+}
@@ -0,0 +1,170 @@
+package programming
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/renato0307/learning-go-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+const (
+	CountFlag       string = "count"
+	ConcurrencyFlag string = "concurrency"
+)
+
+// defaultConcurrency is the number of IDs generated in parallel by default
+const defaultConcurrency int = 4
+
+// generator generates an ID, returning the object to print
+type generator func(ctx context.Context) (interface{}, error)
+
+// addBatchFlags adds the flags to generate several IDs at once
+func addBatchFlags(cmd *cobra.Command) {
+	cmd.Flags().Int(CountFlag,
+		1,
+		"the number of IDs to generate, printed as JSON Lines with --output json")
+	cmd.Flags().Int(ConcurrencyFlag,
+		defaultConcurrency,
+		"the maximum number of IDs generated in parallel")
+}
+
+// batchFlags returns the number of IDs to generate and how many in parallel
+func batchFlags(cmd *cobra.Command) (int, int, error) {
+	count, err := cmd.Flags().GetInt(CountFlag)
+	if err != nil {
+		return 0, 0, err
+	}
+	if count < 1 {
+		return 0, 0, clierrors.Usagef("--%s must be greater than zero", CountFlag)
+	}
+	concurrency, err := cmd.Flags().GetInt(ConcurrencyFlag)
+	if err != nil {
+		return 0, 0, err
+	}
+	if concurrency < 1 {
+		return 0, 0, clierrors.Usagef("--%s must be greater than zero", ConcurrencyFlag)
+	}
+	return count, concurrency, nil
+}
+
+// printGenerated generates count IDs and prints them. Without --count a
+// single ID is printed as an object, otherwise the IDs are streamed, so the
+// format does not depend on the count.
+func printGenerated(cmd *cobra.Command, iostreams *iostreams.IOStreams, generate generator, name string, count int, concurrency int) error {
+	ctx := cmd.Context()
+	if !cmd.Flags().Changed(CountFlag) {
+		id, err := generate(ctx)
+		if err != nil {
+			return err
+		}
+		return output.Print(iostreams, id)
+	}
+	return generateBatch(ctx, iostreams, generate, name, count, concurrency)
+}
+
+// result is the outcome of generating the ID with the given index
+type result struct {
+	index int
+	id    interface{}
+	err   error
+}
+
+// generateBatch generates count IDs using a pool of concurrency workers.
+// The IDs are printed in order as soon as all the previous ones are done.
+// Failures are reported in the error output and returned together at the end.
+func generateBatch(ctx context.Context, iostreams *iostreams.IOStreams, generate generator, name string, count int, concurrency int) error {
+	stream, err := output.NewStream(iostreams)
+	if err != nil {
+		return err
+	}
+
+	indexes := make(chan int)
+	results := make(chan result)
+
+	// starts the workers
+	wg := sync.WaitGroup{}
+	for i := 0; i < concurrency && i < count; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				id, err := generate(ctx)
+				results <- result{index: index, id: id, err: err}
+			}
+		}()
+	}
+
+	// sends the work, stopping if the execution is cancelled
+	go func() {
+		defer close(indexes)
+		for index := 0; index < count; index++ {
+			select {
+			case indexes <- index:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// prints the results in order, keeping the ones arriving early
+	pending := map[int]result{}
+	next := 0
+	failed := 0
+	var firstErr error
+	for received := range results {
+		pending[received.index] = received
+		for {
+			current, found := pending[next]
+			if !found {
+				break
+			}
+			delete(pending, next)
+			next++
+
+			if current.err != nil {
+				failed++
+				if firstErr == nil {
+					firstErr = current.err
+				}
+				iostreams.Errorf("error generating %s %d of %d: %s\n",
+					name,
+					current.index+1,
+					count,
+					current.err)
+				continue
+			}
+			if err == nil {
+				err = stream.Print(current.id)
+			}
+		}
+	}
+
+	// the results printed so far are kept even if the execution is cancelled
+	closeErr := stream.Close()
+	if err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d %ss could not be generated: %w",
+			failed,
+			count,
+			name,
+			firstErr)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package programming
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchFlags(t *testing.T) {
+	testCases := []struct {
+		Args                []string
+		ExpectedCount       int
+		ExpectedConcurrency int
+		ErrorNil            bool
+		Purpose             string
+	}{
+		{
+			Args:                []string{},
+			ExpectedCount:       1,
+			ExpectedConcurrency: defaultConcurrency,
+			ErrorNil:            true,
+			Purpose:             "defaults",
+		},
+		{
+			Args:                []string{"--count", "10", "--concurrency", "2"},
+			ExpectedCount:       10,
+			ExpectedConcurrency: 2,
+			ErrorNil:            true,
+			Purpose:             "flags set",
+		},
+		{
+			Args:     []string{"--count", "-1"},
+			ErrorNil: false,
+			Purpose:  "negative count",
+		},
+		{
+			Args:     []string{"--concurrency", "0"},
+			ErrorNil: false,
+			Purpose:  "zero concurrency",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		cmd := &cobra.Command{}
+		addBatchFlags(cmd)
+		cmd.ParseFlags(tc.Args)
+
+		// act
+		count, concurrency, err := batchFlags(cmd)
+
+		// assert
+		if tc.ErrorNil {
+			assert.NoError(t, err, "error found for "+tc.Purpose)
+			assert.Equal(t, tc.ExpectedCount, count, "invalid count for "+tc.Purpose)
+			assert.Equal(t, tc.ExpectedConcurrency, concurrency, "invalid concurrency for "+tc.Purpose)
+		} else {
+			assert.Error(t, err, "error not found for "+tc.Purpose)
+		}
+	}
+}
+
+func TestGenerateBatchCancelled(t *testing.T) {
+	// arrange
+	iostreams, _, _, _ := iostreams.Test()
+	ctx, cancel := context.WithCancel(context.Background())
+	generate := func(ctx context.Context) (interface{}, error) {
+		cancel()
+		return nil, ctx.Err()
+	}
+
+	// act
+	err := generateBatch(ctx, iostreams, generate, "ID", 100, 1)
+
+	// assert
+	assert.True(t, errors.Is(err, context.Canceled))
+}
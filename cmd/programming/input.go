@@ -0,0 +1,45 @@
+package programming
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+)
+
+// eachInput calls the handler for each argument or, when there are none, for
+// each non-empty line of the input, so values can be piped to commands
+func eachInput(iostreams *iostreams.IOStreams, args []string, handler func(value string) error) error {
+	if len(args) > 0 {
+		for _, arg := range args {
+			err := handler(arg)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if iostreams.In == nil || iostreams.IsStdinTTY() {
+		return clierrors.Usagef("specify the values as arguments or through the input")
+	}
+
+	scanner := bufio.NewScanner(iostreams.In)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		err := handler(line)
+		if err != nil {
+			return err
+		}
+	}
+	err := scanner.Err()
+	if err != nil {
+		return fmt.Errorf("error reading the input: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package programming
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEachInput(t *testing.T) {
+	testCases := []struct {
+		Args     []string
+		Input    string
+		StdinTTY bool
+		Expected []string
+		ErrorNil bool
+		Purpose  string
+	}{
+		{
+			Args:     []string{"a", "b"},
+			Input:    "c\n",
+			Expected: []string{"a", "b"},
+			ErrorNil: true,
+			Purpose:  "arguments win over the input",
+		},
+		{
+			Input:    "a\n\n  b  \r\nc",
+			Expected: []string{"a", "b", "c"},
+			ErrorNil: true,
+			Purpose:  "lines of the input",
+		},
+		{
+			StdinTTY: true,
+			ErrorNil: false,
+			Purpose:  "no arguments on a terminal",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		iostreams, in, _, _ := iostreams.Test()
+		in.WriteString(tc.Input)
+		iostreams.SetStdinTTY(tc.StdinTTY)
+		values := []string{}
+
+		// act
+		err := eachInput(iostreams, tc.Args, func(value string) error {
+			values = append(values, value)
+			return nil
+		})
+
+		// assert
+		if tc.ErrorNil {
+			assert.NoError(t, err, "error found for "+tc.Purpose)
+			assert.Equal(t, tc.Expected, values, "invalid values for "+tc.Purpose)
+		} else {
+			assert.Equal(t, clierrors.ExitUsage, clierrors.ExitCode(err), "invalid error for "+tc.Purpose)
+		}
+	}
+}
+
+func TestEachInputStopsOnError(t *testing.T) {
+	// arrange
+	iostreams, in, _, _ := iostreams.Test()
+	in.WriteString("a\nb\n")
+	calls := 0
+
+	// act
+	err := eachInput(iostreams, []string{}, func(value string) error {
+		calls++
+		return errors.New("failure")
+	})
+
+	// assert
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
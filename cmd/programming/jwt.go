@@ -0,0 +1,32 @@
+package programming
+
+import (
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/spf13/cobra"
+)
+
+// NewProgrammingJwtCmd represents the programming jwt command
+func NewProgrammingJwtCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "jwt",
+		Short: "JWT debugger",
+		Long:  `Provides tools to debug JSON Web Tokens (JWT), like decoding and verifying them.`,
+		RunE:  executeProgrammingJwt(),
+	}
+
+	// the decode command checks the configuration only when calling the API
+	decodeCmd := NewProgrammingJwtDecodeCmd(iostreams)
+	decodeCmd.PreRunE = jwtDecodePreCheck
+	cmd.AddCommand(decodeCmd)
+
+	return cmd
+}
+
+// executeProgrammingJwt implements all the logic associated with this command.
+// In this case as it is an aggregation command will return an error
+func executeProgrammingJwt() func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		return clierrors.Usagef("must specify a subcommand")
+	}
+}
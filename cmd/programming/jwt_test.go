@@ -0,0 +1,33 @@
+package programming
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProgrammingJwtCmd(t *testing.T) {
+	// act
+	cmd := NewProgrammingJwtCmd(nil)
+
+	// assert
+	assert.Equal(t, "jwt", cmd.Use)
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+
+	decodeCmd, _, err := cmd.Find([]string{"decode"})
+	assert.NoError(t, err)
+	assert.Equal(t, "decode", decodeCmd.Name())
+}
+
+func TestExecuteProgrammingJwt(t *testing.T) {
+	// arrange
+	cmd := NewProgrammingJwtCmd(nil)
+
+	// act
+	err := cmd.Execute()
+
+	// assert
+	assert.EqualError(t, err, "must specify a subcommand")
+}
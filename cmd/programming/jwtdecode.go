@@ -0,0 +1,353 @@
+package programming
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/renato0307/learning-go-cli/internal/api"
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/renato0307/learning-go-cli/internal/jwt"
+	"github.com/renato0307/learning-go-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+const (
+	SecretFileFlag string = "secret-file"
+	KeyFlag        string = "key"
+	JwksFlag       string = "jwks"
+	APIFlag        string = "api"
+)
+
+// JwtSecretEnvVar is the environment variable with the secret to verify
+// tokens signed with HMAC, used when no key is given in the flags
+const JwtSecretEnvVar string = config.EnvPrefix + "_JWT_SECRET"
+
+// Results of the signature verification of a token
+const (
+	SignatureVerified    string = "verified"
+	SignatureNotVerified string = "not verified"
+	SignatureInvalid     string = "invalid"
+)
+
+// JwtInfo represents a decoded token
+type JwtInfo struct {
+	Header    map[string]interface{} `json:"header"`
+	Claims    map[string]interface{} `json:"claims"`
+	Dates     *JwtDates              `json:"dates,omitempty"`
+	Expired   bool                   `json:"expired"`
+	Signature string                 `json:"signature"`
+}
+
+// JwtDates represents the date claims of a token in a human readable form,
+// like "2022-02-22T19:22:22Z (in 2 hours)"
+type JwtDates struct {
+	ExpiresAt string `json:"expires_at,omitempty"`
+	IssuedAt  string `json:"issued_at,omitempty"`
+	NotBefore string `json:"not_before,omitempty"`
+}
+
+// JwtRequest represents the request of the jwt API
+type JwtRequest struct {
+	Jwt string `json:"jwt"`
+}
+
+// NewProgrammingJwtDecodeCmd represents the programming jwt decode command
+func NewProgrammingJwtDecodeCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decode [token...]",
+		Short: "Decodes JSON Web Tokens",
+		Long: `Decodes JSON Web Tokens (JWT), showing their header and claims. The exp,
+iat and nbf claims are also shown as dates, relative to now, and expired
+tokens are reported in the error output.
+
+Tokens are read from the arguments or, if there are none, from each line of
+the input. A leading "Bearer " is ignored, so Authorization headers can be
+decoded as they are.
+
+The signature is verified when a key is given with one of:
+  --secret-file  a file with the shared secret of tokens signed with HS256,
+                 HS384 or HS512, ignoring a trailing newline
+  --key          a PEM file with a public key or a certificate, for tokens
+                 signed with RS*, PS*, ES* or EdDSA
+  --jwks         a JSON Web Key Set file, where the key is found by the
+                 token kid
+Without any of them the shared secret is read from the
+LEARNING_GO_CLI_JWT_SECRET environment variable, if set. Secrets are never
+given in the arguments, which are kept in the shell history and seen by
+other users of the machine.
+
+Tokens are decoded locally, even when an API endpoint is configured, as
+they are credentials and decoding them does not need the API, so they are
+not sent anywhere by default. With --api they are decoded by the API
+instead, if it supports it, and the keys are never sent to the API.`,
+		RunE: executeProgrammingJwtDecode(iostreams, api.DefaultFactory, time.Now),
+	}
+
+	cmd.Flags().String(SecretFileFlag,
+		"",
+		fmt.Sprintf("the file with the secret to verify tokens signed with HMAC (env %s)", JwtSecretEnvVar))
+	cmd.Flags().String(KeyFlag,
+		"",
+		"the PEM file with the public key or certificate to verify the tokens")
+	cmd.Flags().String(JwksFlag,
+		"",
+		"the JWKS file with the public keys to verify the tokens")
+	cmd.Flags().Bool(APIFlag,
+		false,
+		"if set the tokens are sent to the API to be decoded")
+
+	return cmd
+}
+
+// jwtDecodePreCheck verifies the configuration needed to call the API, only
+// when the tokens are decoded by the API
+func jwtDecodePreCheck(cmd *cobra.Command, args []string) error {
+	useAPI, err := cmd.Flags().GetBool(APIFlag)
+	if err != nil || !useAPI {
+		return err
+	}
+	return config.ConfigPreCheck(cmd, args)
+}
+
+// executeProgrammingJwtDecode implements all the logic associated with this command.
+func executeProgrammingJwtDecode(iostreams *iostreams.IOStreams, newClient api.Factory, now func() time.Time) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		useAPI, err := cmd.Flags().GetBool(APIFlag)
+		if err != nil {
+			return err
+		}
+		verify, err := jwtVerifier(cmd, useAPI)
+		if err != nil {
+			return err
+		}
+		var client api.Requester
+		if useAPI {
+			client = newClient()
+		}
+
+		stream, err := output.NewStream(iostreams)
+		if err != nil {
+			return err
+		}
+
+		total := 0
+		invalid := 0
+		err = eachInput(iostreams, args, func(value string) error {
+			total++
+			token, err := jwt.Decode(value)
+			if err != nil {
+				invalid++
+				iostreams.Errorf("%s\n", err)
+				return nil
+			}
+
+			if client != nil {
+				response, err := decodeWithAPI(cmd, client, token)
+				if err == nil {
+					return stream.Print(response)
+				}
+				var apiErr *api.APIError
+				if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+					return err
+				}
+				iostreams.Errorf("warning: the API cannot decode tokens, decoding locally\n")
+				client = nil
+			}
+
+			info := jwtInfo(token, now())
+			if info.Expired {
+				expiresAt, _ := token.Time("exp")
+				iostreams.Errorf("warning: the token expired %s\n", relativeTime(expiresAt, now()))
+			}
+			if verify != nil {
+				info.Signature = SignatureVerified
+				err = verify(token)
+				if err != nil {
+					invalid++
+					info.Signature = SignatureInvalid
+					iostreams.Errorf("error verifying the token signature: %s\n", err)
+				}
+			}
+			return stream.Print(info)
+		})
+
+		closeErr := stream.Close()
+		if err != nil {
+			return err
+		}
+		if invalid > 0 {
+			return fmt.Errorf("%d of %d tokens are not valid", invalid, total)
+		}
+		return closeErr
+	}
+}
+
+// jwtVerifier returns the function verifying token signatures with the key
+// given in the "secret-file", "key" or "jwks" flags, or with the secret of
+// the environment when there is none. It returns nil without any key.
+// Tokens decoded by the API cannot be verified, so the secret of the
+// environment is ignored and the flags are rejected when useAPI is set.
+func jwtVerifier(cmd *cobra.Command, useAPI bool) (func(token *jwt.Token) error, error) {
+	secretFile, err := cmd.Flags().GetString(SecretFileFlag)
+	if err != nil {
+		return nil, err
+	}
+	keyFile, err := cmd.Flags().GetString(KeyFlag)
+	if err != nil {
+		return nil, err
+	}
+	jwksFile, err := cmd.Flags().GetString(JwksFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := 0
+	for _, value := range []string{secretFile, keyFile, jwksFile} {
+		if value != "" {
+			keys++
+		}
+	}
+	if keys > 1 {
+		return nil, clierrors.Usagef("only one of --%s, --%s and --%s can be used",
+			SecretFileFlag,
+			KeyFlag,
+			JwksFlag)
+	}
+	if keys > 0 && useAPI {
+		return nil, clierrors.Usagef("--%s cannot be used to verify signatures, the keys are never sent to the API",
+			APIFlag)
+	}
+
+	secret := ""
+	switch {
+	case secretFile != "":
+		content, err := ioutil.ReadFile(secretFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the secret: %w", err)
+		}
+		secret = strings.TrimRight(string(content), "\r\n")
+		if secret == "" {
+			return nil, fmt.Errorf("the secret file %s is empty", secretFile)
+		}
+	case keys == 0 && !useAPI:
+		secret = os.Getenv(JwtSecretEnvVar)
+	}
+
+	switch {
+	case secret != "":
+		return func(token *jwt.Token) error {
+			return token.VerifyHMAC([]byte(secret))
+		}, nil
+
+	case keyFile != "":
+		content, err := ioutil.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the key: %w", err)
+		}
+		key, err := jwt.ParsePublicKeyPEM(content)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the key %s: %w", keyFile, err)
+		}
+		return func(token *jwt.Token) error {
+			return token.VerifyPublicKey(key)
+		}, nil
+
+	case jwksFile != "":
+		content, err := ioutil.ReadFile(jwksFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the JWKS: %w", err)
+		}
+		jwks, err := jwt.ParseJWKS(content)
+		if err != nil {
+			return nil, err
+		}
+		return func(token *jwt.Token) error {
+			key, err := jwks.Key(token.KeyId())
+			if err != nil {
+				return err
+			}
+			return token.VerifyPublicKey(key)
+		}, nil
+	}
+	return nil, nil
+}
+
+// decodeWithAPI decodes the token calling the API, returning its response
+// as it is
+func decodeWithAPI(cmd *cobra.Command, client api.Requester, token *jwt.Token) (interface{}, error) {
+	// decoding a token has no side effects so it can be retried
+	var response interface{}
+	err := client.Do(cmd.Context(), api.Request{
+		Method:     http.MethodPost,
+		Path:       "/programming/jwt",
+		Body:       JwtRequest{Jwt: token.Raw},
+		Idempotent: true,
+	}, &response)
+	return response, err
+}
+
+// jwtInfo describes the token at the given moment, without verifying its
+// signature
+func jwtInfo(token *jwt.Token, now time.Time) JwtInfo {
+	info := JwtInfo{
+		Header:    token.Header,
+		Claims:    token.Claims,
+		Expired:   token.Expired(now),
+		Signature: SignatureNotVerified,
+	}
+
+	dates := JwtDates{}
+	found := false
+	for claim, value := range map[string]*string{
+		"exp": &dates.ExpiresAt,
+		"iat": &dates.IssuedAt,
+		"nbf": &dates.NotBefore,
+	} {
+		if date, ok := token.Time(claim); ok {
+			*value = fmt.Sprintf("%s (%s)", date.Format(time.RFC3339), relativeTime(date, now))
+			found = true
+		}
+	}
+	if found {
+		info.Dates = &dates
+	}
+	return info
+}
+
+// relativeTime describes when the date happens relative to now, like
+// "in 2 hours" or "3 days ago"
+func relativeTime(date time.Time, now time.Time) string {
+	difference := date.Sub(now).Round(time.Second)
+	if difference == 0 {
+		return "now"
+	}
+
+	elapsed := difference
+	if elapsed < 0 {
+		elapsed = -elapsed
+	}
+	amount, unit := int64(elapsed/time.Second), "second"
+	switch {
+	case elapsed >= 48*time.Hour:
+		amount, unit = int64(elapsed/(24*time.Hour)), "day"
+	case elapsed >= time.Hour:
+		amount, unit = int64(elapsed/time.Hour), "hour"
+	case elapsed >= time.Minute:
+		amount, unit = int64(elapsed/time.Minute), "minute"
+	}
+	if amount != 1 {
+		unit += "s"
+	}
+
+	if difference > 0 {
+		return fmt.Sprintf("in %d %s", amount, unit)
+	}
+	return fmt.Sprintf("%d %s ago", amount, unit)
+}
@@ -0,0 +1,308 @@
+package programming
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/renato0307/learning-go-cli/internal/api"
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/renato0307/learning-go-cli/internal/testhelpers"
+	"github.com/stretchr/testify/assert"
+)
+
+// jwtIoToken is the example token of jwt.io, signed with the secret
+// "your-256-bit-secret"
+const jwtIoToken string = "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9." +
+	"eyJzdWIiOiIxMjM0NTY3ODkwIiwibmFtZSI6IkpvaG4gRG9lIiwiaWF0IjoxNTE2MjM5MDIyfQ." +
+	"SflKxwRJSMeKKF2QT4fwpMeJf36POk6yJV_adQssw5c"
+
+// jwtNow is the moment used as now in the tests
+var jwtNow = time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)
+
+// unsignedToken creates a token with the claims, without signature
+func unsignedToken(claims string) string {
+	encode := base64.RawURLEncoding.EncodeToString
+	return encode([]byte(`{"alg":"none"}`)) + "." + encode([]byte(claims)) + "."
+}
+
+func TestNewProgrammingJwtDecodeCmd(t *testing.T) {
+	// act
+	cmd := NewProgrammingJwtDecodeCmd(nil)
+
+	// assert
+	assert.Equal(t, "decode", cmd.Name())
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+	for _, flag := range []string{SecretFileFlag, KeyFlag, JwksFlag, APIFlag} {
+		assert.NotNil(t, cmd.Flags().Lookup(flag), flag)
+	}
+}
+
+func TestExecuteProgrammingJwtDecode(t *testing.T) {
+	// arrange
+	iostreams, _, out, errOut := iostreams.Test()
+	cmd := NewProgrammingJwtDecodeCmd(iostreams)
+	requester := &testhelpers.FakeRequester{}
+	cmd.RunE = executeProgrammingJwtDecode(iostreams, requester.Factory(), func() time.Time { return jwtNow })
+
+	// act
+	cmd.SetArgs([]string{"Bearer " + jwtIoToken})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, `{"header":{"alg":"HS256","typ":"JWT"},`+
+		`"claims":{"iat":1516239022,"name":"John Doe","sub":"1234567890"},`+
+		`"dates":{"issued_at":"2018-01-18T01:30:22Z (1496 days ago)"},`+
+		`"expired":false,"signature":"not verified"}
+`, out.String())
+	assert.Empty(t, errOut.String())
+	assert.Empty(t, requester.Requests, "Tokens must be decoded locally")
+}
+
+func TestExecuteProgrammingJwtDecodeExpired(t *testing.T) {
+	// arrange
+	iostreams, in, out, errOut := iostreams.Test()
+	in.WriteString(unsignedToken(fmt.Sprintf(`{"exp":%d,"nbf":%d}`,
+		jwtNow.Add(-2*time.Hour).Unix(),
+		jwtNow.Add(-3*time.Hour).Unix())) + "\n")
+	cmd := NewProgrammingJwtDecodeCmd(iostreams)
+	cmd.RunE = executeProgrammingJwtDecode(iostreams, api.DefaultFactory, func() time.Time { return jwtNow })
+
+	// act
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), `"expires_at":"2022-02-22T17:22:22Z (2 hours ago)"`)
+	assert.Contains(t, out.String(), `"not_before":"2022-02-22T16:22:22Z (3 hours ago)"`)
+	assert.Contains(t, out.String(), `"expired":true`)
+	assert.Equal(t, "warning: the token expired 2 hours ago\n", errOut.String())
+}
+
+func TestExecuteProgrammingJwtDecodeWithSecret(t *testing.T) {
+	// arrange
+	writeSecret := func(secret string) string {
+		secretFile := filepath.Join(t.TempDir(), "secret")
+		err := os.WriteFile(secretFile, []byte(secret), 0600)
+		assert.NoError(t, err)
+		return secretFile
+	}
+
+	testCases := []struct {
+		Purpose   string
+		Args      []string
+		EnvSecret string
+		Signature string
+		Error     string
+	}{
+		{
+			Purpose:   "valid secret file",
+			Args:      []string{"--" + SecretFileFlag, writeSecret("your-256-bit-secret\n")},
+			Signature: SignatureVerified,
+		},
+		{
+			Purpose:   "invalid secret file",
+			Args:      []string{"--" + SecretFileFlag, writeSecret("another-secret")},
+			Signature: SignatureInvalid,
+			Error:     "1 of 1 tokens are not valid",
+		},
+		{
+			Purpose:   "secret in the environment",
+			EnvSecret: "your-256-bit-secret",
+			Signature: SignatureVerified,
+		},
+		{
+			Purpose:   "secret file over the environment",
+			Args:      []string{"--" + SecretFileFlag, writeSecret("your-256-bit-secret")},
+			EnvSecret: "another-secret",
+			Signature: SignatureVerified,
+		},
+		{
+			Purpose:   "no secret",
+			Signature: SignatureNotVerified,
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		t.Setenv(JwtSecretEnvVar, tc.EnvSecret)
+		iostreams, _, out, _ := iostreams.Test()
+		cmd := NewProgrammingJwtDecodeCmd(iostreams)
+
+		// act
+		cmd.SetArgs(append([]string{jwtIoToken}, tc.Args...))
+		err := cmd.Execute()
+
+		// assert
+		if tc.Error != "" {
+			assert.EqualError(t, err, tc.Error, tc.Purpose)
+		} else {
+			assert.NoError(t, err, tc.Purpose)
+		}
+		assert.Contains(t, out.String(), fmt.Sprintf(`"signature":"%s"`, tc.Signature), tc.Purpose)
+	}
+}
+
+func TestExecuteProgrammingJwtDecodeWithJwks(t *testing.T) {
+	// arrange
+	jwks := filepath.Join(t.TempDir(), "jwks.json")
+	err := os.WriteFile(jwks, []byte(`{"keys": []}`), 0600)
+	assert.NoError(t, err)
+
+	iostreams, _, _, errOut := iostreams.Test()
+	cmd := NewProgrammingJwtDecodeCmd(iostreams)
+
+	// act
+	cmd.SetArgs([]string{jwtIoToken, "--" + JwksFlag, jwks})
+	err = cmd.Execute()
+
+	// assert
+	assert.EqualError(t, err, "1 of 1 tokens are not valid")
+	assert.Equal(t,
+		"error verifying the token signature: the token has no key id and the JWKS has 0 keys\n",
+		errOut.String())
+}
+
+func TestExecuteProgrammingJwtDecodeWithInvalidFlags(t *testing.T) {
+	testCases := []struct {
+		Purpose string
+		Args    []string
+		Usage   bool
+	}{
+		{
+			Purpose: "several keys",
+			Args:    []string{jwtIoToken, "--secret-file", "secret", "--jwks", "jwks.json"},
+			Usage:   true,
+		},
+		{
+			Purpose: "keys sent to the API",
+			Args:    []string{jwtIoToken, "--secret-file", "secret", "--api"},
+			Usage:   true,
+		},
+		{
+			Purpose: "missing secret file",
+			Args:    []string{jwtIoToken, "--secret-file", filepath.Join(t.TempDir(), "missing")},
+		},
+		{
+			Purpose: "missing key file",
+			Args:    []string{jwtIoToken, "--key", filepath.Join(t.TempDir(), "missing.pem")},
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		iostreams, _, out, _ := iostreams.Test()
+		cmd := NewProgrammingJwtDecodeCmd(iostreams)
+
+		// act
+		cmd.SetArgs(tc.Args)
+		err := cmd.Execute()
+
+		// assert
+		assert.Error(t, err, tc.Purpose)
+		var usageErr *clierrors.UsageError
+		assert.Equal(t, tc.Usage, errors.As(err, &usageErr), tc.Purpose)
+		assert.Empty(t, out.String(), tc.Purpose)
+	}
+}
+
+func TestExecuteProgrammingJwtDecodeWithInvalidToken(t *testing.T) {
+	// arrange
+	iostreams, _, out, errOut := iostreams.Test()
+	cmd := NewProgrammingJwtDecodeCmd(iostreams)
+
+	// act
+	cmd.SetArgs([]string{"not-a-token", jwtIoToken})
+	err := cmd.Execute()
+
+	// assert
+	assert.EqualError(t, err, "1 of 2 tokens are not valid")
+	assert.Contains(t, out.String(), `"name":"John Doe"`)
+	assert.Equal(t, "invalid token: expected 3 parts separated by dots, found 1\n", errOut.String())
+}
+
+func TestExecuteProgrammingJwtDecodeWithAPI(t *testing.T) {
+	// arrange
+	t.Setenv(JwtSecretEnvVar, "the secret of the environment is ignored with the API")
+	iostreams, _, out, _ := iostreams.Test()
+	requester := &testhelpers.FakeRequester{
+		Response: `{"header": {"alg": "HS256"}}`,
+	}
+	cmd := NewProgrammingJwtDecodeCmd(iostreams)
+	cmd.RunE = executeProgrammingJwtDecode(iostreams, requester.Factory(), time.Now)
+
+	// act
+	cmd.SetArgs([]string{"--" + APIFlag, jwtIoToken})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"header\":{\"alg\":\"HS256\"}}\n", out.String())
+	assert.Len(t, requester.Requests, 1)
+	assert.Equal(t, "/programming/jwt", requester.Requests[0].Path)
+	assert.Equal(t, JwtRequest{Jwt: jwtIoToken}, requester.Requests[0].Body)
+}
+
+func TestExecuteProgrammingJwtDecodeWithAPINotAvailable(t *testing.T) {
+	// arrange
+	iostreams, _, out, errOut := iostreams.Test()
+	requester := &testhelpers.FakeRequester{
+		Err: &api.APIError{StatusCode: http.StatusNotFound, Message: "not found"},
+	}
+	cmd := NewProgrammingJwtDecodeCmd(iostreams)
+	cmd.RunE = executeProgrammingJwtDecode(iostreams, requester.Factory(), time.Now)
+
+	// act
+	cmd.SetArgs([]string{"--" + APIFlag, jwtIoToken, jwtIoToken})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), `"signature":"not verified"`)
+	assert.Equal(t, "warning: the API cannot decode tokens, decoding locally\n", errOut.String())
+	assert.Len(t, requester.Requests, 1, "The API must not be called again")
+}
+
+func TestJwtDecodePreCheck(t *testing.T) {
+	// arrange
+	cmd := NewProgrammingJwtDecodeCmd(nil)
+
+	// act
+	err := jwtDecodePreCheck(cmd, []string{})
+
+	// assert
+	assert.NoError(t, err, "Decoding tokens locally requires no configuration")
+}
+
+func TestRelativeTime(t *testing.T) {
+	testCases := []struct {
+		Purpose  string
+		Offset   time.Duration
+		Expected string
+	}{
+		{Purpose: "now", Offset: 0, Expected: "now"},
+		{Purpose: "one second", Offset: time.Second, Expected: "in 1 second"},
+		{Purpose: "seconds ago", Offset: -30 * time.Second, Expected: "30 seconds ago"},
+		{Purpose: "minutes", Offset: 90 * time.Second, Expected: "in 1 minute"},
+		{Purpose: "hours", Offset: 47 * time.Hour, Expected: "in 47 hours"},
+		{Purpose: "days ago", Offset: -72 * time.Hour, Expected: "3 days ago"},
+	}
+
+	for _, tc := range testCases {
+		// act
+		relative := relativeTime(jwtNow.Add(tc.Offset), jwtNow)
+
+		// assert
+		assert.Equal(t, tc.Expected, relative, tc.Purpose)
+	}
+}
@@ -0,0 +1,53 @@
+package programming
+
+import (
+	"context"
+
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/ids"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/spf13/cobra"
+)
+
+// KsuidResponse represents a generated KSUID, in the format of the uuid API
+type KsuidResponse struct {
+	Ksuid string `json:"ksuid"`
+}
+
+// NewProgrammingKsuidCmd represents the programming ksuid command
+func NewProgrammingKsuidCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ksuid",
+		Short: "Generates a KSUID",
+		Long: `Generates a KSUID (K-Sortable Unique IDentifier), like
+0ujtsYcgvSTl8PAuAdqWYSMnLOv, sortable by creation time with a precision of
+one second.
+
+KSUIDs are not supported by the API so they are generated locally.`,
+		Args: clierrors.UsageArgs(cobra.NoArgs),
+		RunE: executeProgrammingKsuid(iostreams),
+	}
+
+	addBatchFlags(cmd)
+
+	return cmd
+}
+
+// executeProgrammingKsuid implements all the logic associated with this command.
+func executeProgrammingKsuid(iostreams *iostreams.IOStreams) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		count, concurrency, err := batchFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		generate := func(ctx context.Context) (interface{}, error) {
+			ksuid, err := ids.NewKSUID()
+			if err != nil {
+				return KsuidResponse{}, err
+			}
+			return KsuidResponse{Ksuid: ksuid.String()}, nil
+		}
+		return printGenerated(cmd, iostreams, generate, "KSUID", count, concurrency)
+	}
+}
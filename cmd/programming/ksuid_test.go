@@ -0,0 +1,68 @@
+package programming
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProgrammingKsuidCmd(t *testing.T) {
+	// act
+	cmd := NewProgrammingKsuidCmd(nil)
+
+	// assert
+	assert.Equal(t, "ksuid", cmd.Use)
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+	assert.NotNil(t, cmd.Flags().Lookup(CountFlag))
+}
+
+func TestExecuteProgrammingKsuid(t *testing.T) {
+	// arrange
+	iostreams, _, out, _ := iostreams.Test()
+	cmd := NewProgrammingKsuidCmd(iostreams)
+
+	// act
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Regexp(t, "^\\{\n  \"ksuid\": \"[0-9A-Za-z]{27}\"\n\\}\n$", out.String())
+}
+
+func TestExecuteProgrammingKsuidWithArgs(t *testing.T) {
+	// arrange
+	iostreams, _, out, _ := iostreams.Test()
+	cmd := NewProgrammingKsuidCmd(iostreams)
+
+	// act
+	cmd.SetArgs([]string{"0ujtsYcgvSTl8PAuAdqWYSMnLOv"})
+	err := cmd.Execute()
+
+	// assert
+	assert.Equal(t, clierrors.ExitUsage, clierrors.ExitCode(err))
+	assert.Contains(t, err.Error(), `unknown command "0ujtsYcgvSTl8PAuAdqWYSMnLOv"`)
+	assert.Empty(t, out.String())
+}
+
+func TestExecuteProgrammingKsuidWithCount(t *testing.T) {
+	// arrange
+	iostreams, _, out, _ := iostreams.Test()
+	cmd := NewProgrammingKsuidCmd(iostreams)
+	config.Set(config.OutputFlag, config.OutputValue)
+	defer config.Set(config.OutputFlag, config.OutputJSON)
+
+	// act
+	cmd.SetArgs([]string{"--count", "3"})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(out.String()), "\n"), 3)
+}
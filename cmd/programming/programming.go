@@ -1,9 +1,7 @@
 package programming
 
 import (
-	"fmt"
-
-	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
 	"github.com/renato0307/learning-go-cli/internal/iostreams"
 	"github.com/spf13/cobra"
 )
@@ -13,11 +11,18 @@ func NewProgrammingCmd(iostreams *iostreams.IOStreams) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "programming",
 		Short: "Programming tools",
-		Long:  `Provides several programming tools like uuid generation, etc.`,
+		Long:  `Provides several programming tools like uuid, ulid and ksuid generation, a JWT debugger, etc.`,
 		RunE:  executeProgramming(),
 	}
 
-	config.AddCommandWithConfigPreCheck(cmd, NewProgrammingUuidCmd(iostreams))
+	// the uuid command checks the configuration only when calling the API
+	uuidCmd := NewProgrammingUuidCmd(iostreams)
+	uuidCmd.PreRunE = uuidPreCheck
+	cmd.AddCommand(uuidCmd)
+
+	cmd.AddCommand(NewProgrammingUlidCmd(iostreams))
+	cmd.AddCommand(NewProgrammingKsuidCmd(iostreams))
+	cmd.AddCommand(NewProgrammingJwtCmd(iostreams))
 
 	return cmd
 }
@@ -26,6 +31,6 @@ func NewProgrammingCmd(iostreams *iostreams.IOStreams) *cobra.Command {
 // In this case as it is an aggregation command will return an error
 func executeProgramming() func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
-		return fmt.Errorf("must specify a subcommand")
+		return clierrors.Usagef("must specify a subcommand")
 	}
 }
@@ -15,6 +15,20 @@ func TestNewProgrammingCmd(t *testing.T) {
 	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
 	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
 	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+
+	uuidCmd, _, err := cmd.Find([]string{"uuid"})
+	assert.NoError(t, err)
+	assert.NotNil(t, uuidCmd.PreRunE, "The uuid command must check the configuration")
+
+	decodeCmd, _, err := cmd.Find([]string{"jwt", "decode"})
+	assert.NoError(t, err)
+	assert.NotNil(t, decodeCmd.PreRunE, "The jwt decode command must check the configuration")
+
+	for _, name := range []string{"ulid", "ksuid", "jwt"} {
+		subCmd, _, err := cmd.Find([]string{name})
+		assert.NoError(t, err)
+		assert.Equal(t, name, subCmd.Name())
+	}
 }
 
 func TestExecute(t *testing.T) {
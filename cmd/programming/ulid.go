@@ -0,0 +1,52 @@
+package programming
+
+import (
+	"context"
+
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/ids"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/spf13/cobra"
+)
+
+// UlidResponse represents a generated ULID, in the format of the uuid API
+type UlidResponse struct {
+	Ulid string `json:"ulid"`
+}
+
+// NewProgrammingUlidCmd represents the programming ulid command
+func NewProgrammingUlidCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ulid",
+		Short: "Generates an ULID",
+		Long: `Generates an ULID (Universally Unique Lexicographically Sortable
+Identifier), like 01ARZ3NDEKTSV4RRFFQ69G5FAV, sortable by creation time.
+
+ULIDs are not supported by the API so they are generated locally.`,
+		Args: clierrors.UsageArgs(cobra.NoArgs),
+		RunE: executeProgrammingUlid(iostreams),
+	}
+
+	addBatchFlags(cmd)
+
+	return cmd
+}
+
+// executeProgrammingUlid implements all the logic associated with this command.
+func executeProgrammingUlid(iostreams *iostreams.IOStreams) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		count, concurrency, err := batchFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		generate := func(ctx context.Context) (interface{}, error) {
+			ulid, err := ids.NewULID()
+			if err != nil {
+				return UlidResponse{}, err
+			}
+			return UlidResponse{Ulid: ulid.String()}, nil
+		}
+		return printGenerated(cmd, iostreams, generate, "ULID", count, concurrency)
+	}
+}
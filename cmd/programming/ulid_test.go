@@ -0,0 +1,106 @@
+package programming
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProgrammingUlidCmd(t *testing.T) {
+	// act
+	cmd := NewProgrammingUlidCmd(nil)
+
+	// assert
+	assert.Equal(t, "ulid", cmd.Use)
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+	assert.NotNil(t, cmd.Flags().Lookup(CountFlag))
+}
+
+func TestExecuteProgrammingUlid(t *testing.T) {
+	// arrange
+	iostreams, _, out, _ := iostreams.Test()
+	cmd := NewProgrammingUlidCmd(iostreams)
+
+	// act
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Regexp(t, "^\\{\n  \"ulid\": \"[0-9A-HJKMNP-TV-Z]{26}\"\n\\}\n$", out.String())
+}
+
+func TestExecuteProgrammingUlidWithArgs(t *testing.T) {
+	// arrange
+	iostreams, _, out, _ := iostreams.Test()
+	cmd := NewProgrammingUlidCmd(iostreams)
+
+	// act
+	cmd.SetArgs([]string{"01ARZ3NDEKTSV4RRFFQ69G5FAV"})
+	err := cmd.Execute()
+
+	// assert
+	assert.Equal(t, clierrors.ExitUsage, clierrors.ExitCode(err))
+	assert.Contains(t, err.Error(), `unknown command "01ARZ3NDEKTSV4RRFFQ69G5FAV"`)
+	assert.Empty(t, out.String())
+}
+
+func TestExecuteProgrammingUlidWithCount(t *testing.T) {
+	// arrange
+	iostreams, _, out, _ := iostreams.Test()
+	cmd := NewProgrammingUlidCmd(iostreams)
+	config.Set(config.OutputFlag, config.OutputValue)
+	defer config.Set(config.OutputFlag, config.OutputJSON)
+
+	// act
+	cmd.SetArgs([]string{"--count", "3"})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(out.String()), "\n"), 3)
+}
+
+func TestExecuteProgrammingUlidWithCountFormat(t *testing.T) {
+	testCases := []struct {
+		Args     []string
+		Expected string
+		Purpose  string
+	}{
+		{
+			Args:     []string{},
+			Expected: "^\\{\n  \"ulid\": \"[0-9A-Z]{26}\"\n\\}\n$",
+			Purpose:  "without count a single object is printed",
+		},
+		{
+			Args:     []string{"--count", "1"},
+			Expected: "^\\{\"ulid\":\"[0-9A-Z]{26}\"\\}\n$",
+			Purpose:  "a count of one is streamed",
+		},
+		{
+			Args:     []string{"--count", "2"},
+			Expected: "^(\\{\"ulid\":\"[0-9A-Z]{26}\"\\}\n){2}$",
+			Purpose:  "a count of two is streamed",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		iostreams, _, out, _ := iostreams.Test()
+		cmd := NewProgrammingUlidCmd(iostreams)
+
+		// act
+		cmd.SetArgs(tc.Args)
+		err := cmd.Execute()
+
+		// assert
+		assert.NoError(t, err, tc.Purpose)
+		assert.Regexp(t, tc.Expected, out.String(), tc.Purpose)
+	}
+}
@@ -1,104 +1,263 @@
 package programming
 
 import (
-	"encoding/json"
-	"errors"
+	"context"
 	"fmt"
-	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strings"
 
-	"github.com/renato0307/learning-go-cli/internal/auth"
+	"github.com/renato0307/learning-go-cli/internal/api"
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
 	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/ids"
 	"github.com/renato0307/learning-go-cli/internal/iostreams"
 	"github.com/spf13/cobra"
 )
 
-const NoHyphensFlag string = "no-hyphens"
+const (
+	NoHyphensFlag string = "no-hyphens"
+	LocalFlag     string = "local"
+	VersionFlag   string = "version"
+	NamespaceFlag string = "namespace"
+	NameFlag      string = "name"
+)
+
+// UuidScope is the scope the access tokens need to generate UUIDs with the API
+const UuidScope string = "uuid:write"
+
+// apiUuidVersion is the version of the UUIDs generated by the API, other
+// versions are always generated locally
+const apiUuidVersion int = 4
+
+// UuidResponse represents the response of the uuid API
+type UuidResponse struct {
+	Uuid string `json:"uuid"`
+}
 
 // NewProgrammingCmd represents the programming command
 func NewProgrammingUuidCmd(iostreams *iostreams.IOStreams) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "uuid",
 		Short: "Generates an UUID",
-		Long:  `Generates an UUID, with or without hyphens.`,
-		RunE:  executeProgrammingUuid(iostreams),
+		Long: `Generates an UUID, with or without hyphens.
+
+The version is selected with --version:
+  v1  time-based, with a random node instead of the MAC address
+  v4  random, the default
+  v5  name-based, from --namespace (dns, url, oid, x500 or an UUID) and --name
+  v7  time-ordered, sortable by creation time
+
+Random UUIDs (version 4) are generated by the API. With --local, or the
+local-uuid setting, they are generated without calling the API, so no
+configuration is required. Other versions are always generated locally.
+Calling the API requires the uuid:write scope, which is requested in the
+access tokens together with the configured scopes.
+
+Several UUIDs can be generated at once with --count, fetched in parallel and
+printed in order as they arrive, one JSON object per line with --output json
+(JSON Lines), even with --count 1. UUIDs which could not be generated are reported in the error
+output without discarding the others.
+
+Existing UUIDs can be inspected with the parse, validate and convert
+subcommands.`,
+		Args: clierrors.UsageArgs(cobra.NoArgs),
+		RunE: executeProgrammingUuid(iostreams, api.DefaultFactory),
 	}
 
 	cmd.Flags().Bool(NoHyphensFlag,
 		false,
 		"if set the UUID generated will not contains hyphens")
+	cmd.Flags().String(VersionFlag,
+		"v4",
+		"the UUID version: v1, v4, v5 or v7")
+	cmd.Flags().String(NamespaceFlag,
+		"",
+		"the namespace of v5 UUIDs: dns, url, oid, x500 or an UUID")
+	cmd.Flags().String(NameFlag,
+		"",
+		"the name of v5 UUIDs")
+	addBatchFlags(cmd)
+	cmd.Flags().Bool(LocalFlag,
+		false,
+		fmt.Sprintf("if set the UUIDs are generated locally, overriding the %s setting",
+			config.LocalUuidFlag))
+
+	config.RequireScopes(cmd, UuidScope)
+
+	cmd.AddCommand(NewProgrammingUuidParseCmd(iostreams))
+	cmd.AddCommand(NewProgrammingUuidValidateCmd(iostreams))
+	cmd.AddCommand(NewProgrammingUuidConvertCmd(iostreams))
 
 	return cmd
 }
 
+// uuidPreCheck verifies the configuration needed to call the API, unless the
+// UUIDs are generated locally
+func uuidPreCheck(cmd *cobra.Command, args []string) error {
+	local, err := useLocal(cmd)
+	if err != nil || local {
+		return err
+	}
+	version, err := uuidVersion(cmd)
+	if err != nil || version != apiUuidVersion {
+		return err
+	}
+	return config.ConfigPreCheck(cmd, args)
+}
+
+// uuidVersion returns the version selected with the "version" flag, like v7
+// or 7
+func uuidVersion(cmd *cobra.Command) (int, error) {
+	value, err := cmd.Flags().GetString(VersionFlag)
+	if err != nil {
+		return 0, err
+	}
+	switch strings.TrimPrefix(strings.ToLower(value), "v") {
+	case "1":
+		return 1, nil
+	case "4":
+		return 4, nil
+	case "5":
+		return 5, nil
+	case "7":
+		return 7, nil
+	}
+	return 0, clierrors.Usagef("unsupported UUID version %q, valid versions are: v1, v4, v5, v7", value)
+}
+
+// useLocal checks if the UUIDs are generated locally, using the "local"
+// flag if set or the setting otherwise
+func useLocal(cmd *cobra.Command) (bool, error) {
+	if cmd.Flags().Changed(LocalFlag) {
+		return cmd.Flags().GetBool(LocalFlag)
+	}
+	return config.GetBool(config.LocalUuidFlag), nil
+}
+
 // executeProgrammingUuid implements all the logic associated with this command.
-// In this case as it is an aggregation command will return an error
-func executeProgrammingUuid(iostreams *iostreams.IOStreams) func(cmd *cobra.Command, args []string) error {
+func executeProgrammingUuid(iostreams *iostreams.IOStreams, newClient api.Factory) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 
-		// creates the base request
-		apiEndpoint := config.GetString(config.APIEndpointFlag)
-		realUrl := fmt.Sprintf("%s/programming/uuid", apiEndpoint)
-		request, err := http.NewRequest("POST", realUrl, nil)
-		if err != nil {
-			return fmt.Errorf("error creating the request to call the API: %w", err)
-		}
-
 		// handles the "no-hyphens" flag
 		noHyphens, err := cmd.Flags().GetBool(NoHyphensFlag)
 		if err != nil {
 			return err
 		}
+		query := url.Values{}
 		if noHyphens {
-			q := request.URL.Query()
-			q.Add("no-hyphens", "true")
-			request.URL.RawQuery = q.Encode()
+			query.Add("no-hyphens", "true")
+		}
+
+		// handles the "count" and "concurrency" flags
+		count, concurrency, err := batchFlags(cmd)
+		if err != nil {
+			return err
 		}
 
-		// adds authentication
-		token, err := auth.NewAccessToken()
+		// handles the "version", "namespace" and "name" flags
+		version, err := uuidVersion(cmd)
 		if err != nil {
-			return fmt.Errorf("error getting the JWT to call the API: %w", err)
+			return err
 		}
-		request.Header = map[string][]string{
-			"Authentication": {token.AccessToken},
+		namespace, err := cmd.Flags().GetString(NamespaceFlag)
+		if err != nil {
+			return err
+		}
+		name, err := cmd.Flags().GetString(NameFlag)
+		if err != nil {
+			return err
 		}
 
-		// calls API and reads response
-		response, err := http.DefaultClient.Do(request)
+		local, err := useLocal(cmd)
 		if err != nil {
-			return fmt.Errorf("error calling the API: %w", err)
+			return err
 		}
-		defer response.Body.Close()
 
-		if response.StatusCode != http.StatusOK {
-			apiError, err := ioutil.ReadAll(response.Body)
+		var generate generator
+		switch {
+		case version == 5:
+			generate, err = v5UuidGenerator(namespace, name, count, noHyphens)
 			if err != nil {
-				return fmt.Errorf("error parsing API error: %w", err)
+				return err
 			}
-
-			err = errors.New(string(apiError))
-			return fmt.Errorf("error calling the API: %w", err)
+		case namespace != "" || name != "":
+			return clierrors.Usagef("--%s and --%s can only be used with v5 UUIDs",
+				NamespaceFlag,
+				NameFlag)
+		case version == apiUuidVersion && !local:
+			generate = apiUuidGenerator(newClient(), query)
+		default:
+			generate = localUuidGenerator(version, noHyphens)
 		}
 
-		uuid, err := ioutil.ReadAll(response.Body)
-		if err != nil {
-			return fmt.Errorf("error reading the UUID: %w", err)
-		}
+		return printGenerated(cmd, iostreams, generate, "UUID", count, concurrency)
+	}
+}
 
-		// parse and print response as indented JSON
-		var anyJson map[string]interface{}
-		err = json.Unmarshal(uuid, &anyJson)
-		if err != nil {
-			return fmt.Errorf("parsing API response: %w", err)
-		}
-		output, _ := json.MarshalIndent(anyJson, "", "  ")
+// apiUuidGenerator generates UUIDs calling the API with the client, sharing
+// its token
+func apiUuidGenerator(client api.Requester, query url.Values) generator {
+	return func(ctx context.Context) (interface{}, error) {
+		// generating an UUID has no side effects so it can be retried
+		uuid := UuidResponse{}
+		err := client.Do(ctx, api.Request{
+			Method:     http.MethodPost,
+			Path:       "/programming/uuid",
+			Query:      query,
+			Idempotent: true,
+		}, &uuid)
+		return uuid, err
+	}
+}
 
-		_, err = fmt.Fprintln(iostreams.Out, string(output))
+// localUuidGenerator generates UUIDs of the version in the same format
+// returned by the API
+func localUuidGenerator(version int, noHyphens bool) generator {
+	newUuid := ids.NewV4
+	switch version {
+	case 1:
+		newUuid = ids.NewV1
+	case 7:
+		newUuid = ids.NewV7
+	}
+
+	return func(ctx context.Context) (interface{}, error) {
+		uuid, err := newUuid()
 		if err != nil {
-			return fmt.Errorf("error writing to the output: %w", err)
+			return UuidResponse{}, err
 		}
+		return uuidResponse(uuid, noHyphens), nil
+	}
+}
+
+// v5UuidGenerator generates the name-based UUID of the namespace and name.
+// As it is always the same, only one can be generated.
+func v5UuidGenerator(namespace string, name string, count int, noHyphens bool) (generator, error) {
+	if namespace == "" || name == "" {
+		return nil, clierrors.Usagef("v5 UUIDs require --%s and --%s",
+			NamespaceFlag,
+			NameFlag)
+	}
+	if count > 1 {
+		return nil, clierrors.Usagef("v5 UUIDs are always the same for a name, "+
+			"--%s cannot be used", CountFlag)
+	}
+	namespaceUuid, err := ids.Namespace(namespace)
+	if err != nil {
+		return nil, &clierrors.UsageError{Err: err}
+	}
+
+	uuid := ids.NewV5(namespaceUuid, name)
+	return func(ctx context.Context) (interface{}, error) {
+		return uuidResponse(uuid, noHyphens), nil
+	}, nil
+}
 
-		return nil
+// uuidResponse formats the UUID like the API
+func uuidResponse(uuid ids.UUID, noHyphens bool) UuidResponse {
+	if noHyphens {
+		return UuidResponse{Uuid: uuid.Hex()}
 	}
+	return UuidResponse{Uuid: uuid.String()}
 }
@@ -2,13 +2,18 @@ package programming
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 
+	"github.com/renato0307/learning-go-cli/internal/api"
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
 	"github.com/renato0307/learning-go-cli/internal/config"
 	"github.com/renato0307/learning-go-cli/internal/iostreams"
 	"github.com/renato0307/learning-go-cli/internal/testhelpers"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -26,6 +31,15 @@ func TestNewProgrammingUuidCmd(t *testing.T) {
 	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
 	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
 	assert.NotNil(t, cmd.Flags().Lookup(NoHyphensFlag))
+	assert.NotNil(t, cmd.Flags().Lookup(CountFlag))
+	assert.Equal(t, []string{UuidScope}, config.RequiredScopes(cmd))
+	assert.NotNil(t, cmd.Flags().Lookup(ConcurrencyFlag))
+	assert.NotNil(t, cmd.Flags().Lookup(LocalFlag))
+	for _, name := range []string{"parse", "validate", "convert"} {
+		subCmd, _, err := cmd.Find([]string{name})
+		assert.NoError(t, err)
+		assert.Equal(t, name, subCmd.Name())
+	}
 }
 
 func TestExecuteProgrammingUuid(t *testing.T) {
@@ -108,3 +122,337 @@ func TestExecuteProgrammingUuid(t *testing.T) {
 		}
 	}
 }
+
+func TestExecuteProgrammingUuidWithFakeRequester(t *testing.T) {
+	// arrange
+	buffer := &bytes.Buffer{}
+	iostreams := &iostreams.IOStreams{Out: buffer}
+	requester := &testhelpers.FakeRequester{
+		Response: "{\"uuid\": \"da308fbdcba9485ab4c16677aaa732a4\"}",
+	}
+	cmd := NewProgrammingUuidCmd(iostreams)
+	cmd.RunE = executeProgrammingUuid(iostreams, requester.Factory())
+
+	// act
+	cmd.SetArgs([]string{fmt.Sprintf("--%s", NoHyphensFlag)})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"{\n  \"uuid\": \"da308fbdcba9485ab4c16677aaa732a4\"\n}\n",
+		buffer.String())
+	assert.Len(t, requester.Requests, 1)
+	assert.Equal(t, "/programming/uuid", requester.Requests[0].Path)
+	assert.Equal(t, "true", requester.Requests[0].Query.Get(NoHyphensFlag))
+}
+
+func TestExecuteProgrammingUuidWithValueOutput(t *testing.T) {
+	// arrange
+	buffer := &bytes.Buffer{}
+	iostreams := &iostreams.IOStreams{Out: buffer}
+	requester := &testhelpers.FakeRequester{
+		Response: "{\"uuid\": \"da308fbd-cba9-485a-b4c1-6677aaa732a4\"}",
+	}
+	cmd := NewProgrammingUuidCmd(iostreams)
+	cmd.RunE = executeProgrammingUuid(iostreams, requester.Factory())
+	config.Set(config.OutputFlag, config.OutputValue)
+	defer config.Set(config.OutputFlag, config.OutputJSON)
+
+	// act
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "da308fbd-cba9-485a-b4c1-6677aaa732a4\n", buffer.String())
+}
+
+func TestExecuteProgrammingUuidWithCount(t *testing.T) {
+	// arrange
+	iostreams, _, out, _ := iostreams.Test()
+	requester := &testhelpers.FakeRequester{
+		Response: "{\"uuid\": \"da308fbd-cba9-485a-b4c1-6677aaa732a4\"}",
+	}
+	cmd := NewProgrammingUuidCmd(iostreams)
+	cmd.RunE = executeProgrammingUuid(iostreams, requester.Factory())
+	config.Set(config.OutputFlag, config.OutputValue)
+	defer config.Set(config.OutputFlag, config.OutputJSON)
+
+	// act
+	cmd.SetArgs([]string{"--count", "50", "--concurrency", "8"})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, requester.Requests, 50)
+	assert.Equal(t,
+		strings.Repeat("da308fbd-cba9-485a-b4c1-6677aaa732a4\n", 50),
+		out.String())
+}
+
+func TestExecuteProgrammingUuidWithCountKeepsOrder(t *testing.T) {
+	// arrange
+	iostreams, _, out, errOut := iostreams.Test()
+	calls := 0
+	requester := &testhelpers.FakeRequester{
+		Handler: func(request api.Request) (string, error) {
+			calls++
+			if calls == 2 {
+				return "", errors.New("request is malformed")
+			}
+			return fmt.Sprintf("{\"uuid\": \"uuid-%d\"}", calls), nil
+		},
+	}
+	cmd := NewProgrammingUuidCmd(iostreams)
+	cmd.RunE = executeProgrammingUuid(iostreams, requester.Factory())
+	config.Set(config.OutputFlag, config.OutputValue)
+	defer config.Set(config.OutputFlag, config.OutputJSON)
+
+	// act
+	cmd.SetArgs([]string{"--count", "3", "--concurrency", "1"})
+	err := cmd.Execute()
+
+	// assert
+	assert.EqualError(t, err, "1 of 3 UUIDs could not be generated: request is malformed")
+	assert.Equal(t, "uuid-1\nuuid-3\n", out.String())
+	assert.Equal(t, "error generating UUID 2 of 3: request is malformed\n", errOut.String())
+}
+
+func TestExecuteProgrammingUuidWithInvalidCount(t *testing.T) {
+	testCases := []struct {
+		Args    []string
+		Purpose string
+	}{
+		{
+			Args:    []string{"--count", "0"},
+			Purpose: "count is zero",
+		},
+		{
+			Args:    []string{"--count", "2", "--concurrency", "0"},
+			Purpose: "concurrency is zero",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		iostreams, _, _, _ := iostreams.Test()
+		requester := &testhelpers.FakeRequester{}
+		cmd := NewProgrammingUuidCmd(iostreams)
+		cmd.RunE = executeProgrammingUuid(iostreams, requester.Factory())
+
+		// act
+		cmd.SetArgs(tc.Args)
+		err := cmd.Execute()
+
+		// assert
+		assert.Equal(t, clierrors.ExitUsage, clierrors.ExitCode(err), "invalid error for "+tc.Purpose)
+		assert.Empty(t, requester.Requests, "no requests expected for "+tc.Purpose)
+	}
+}
+
+func TestExecuteProgrammingUuidWithUnknownSubcommand(t *testing.T) {
+	// arrange
+	iostreams, _, out, _ := iostreams.Test()
+	requester := &testhelpers.FakeRequester{}
+	cmd := NewProgrammingUuidCmd(iostreams)
+	cmd.RunE = executeProgrammingUuid(iostreams, requester.Factory())
+
+	// act
+	cmd.SetArgs([]string{"prase", "da308fbd-cba9-485a-b4c1-6677aaa732a4"})
+	err := cmd.Execute()
+
+	// assert
+	assert.Equal(t, clierrors.ExitUsage, clierrors.ExitCode(err))
+	assert.Contains(t, err.Error(), `unknown command "prase"`)
+	assert.Empty(t, requester.Requests, "no requests expected")
+	assert.Empty(t, out.String())
+}
+
+func TestExecuteProgrammingUuidLocal(t *testing.T) {
+	testCases := []struct {
+		Args    []string
+		Setting string
+		Pattern string
+		Calls   int
+		Purpose string
+	}{
+		{
+			Args:    []string{"--local"},
+			Pattern: "^\\{\n  \"uuid\": \"[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}\"\n\\}\n$",
+			Purpose: "local flag",
+		},
+		{
+			Args:    []string{"--local", "--no-hyphens"},
+			Pattern: "^\\{\n  \"uuid\": \"[0-9a-f]{12}4[0-9a-f]{3}[89ab][0-9a-f]{15}\"\n\\}\n$",
+			Purpose: "local flag without hyphens",
+		},
+		{
+			Args:    []string{},
+			Setting: "true",
+			Pattern: "^\\{\n  \"uuid\": \"[0-9a-f-]{36}\"\n\\}\n$",
+			Purpose: "local setting",
+		},
+		{
+			Args:    []string{"--local=false"},
+			Setting: "true",
+			Pattern: "da308fbd-cba9-485a-b4c1-6677aaa732a4",
+			Calls:   1,
+			Purpose: "flag overrides the setting",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		iostreams, _, out, _ := iostreams.Test()
+		requester := &testhelpers.FakeRequester{
+			Response: "{\"uuid\": \"da308fbd-cba9-485a-b4c1-6677aaa732a4\"}",
+		}
+		cmd := NewProgrammingUuidCmd(iostreams)
+		cmd.RunE = executeProgrammingUuid(iostreams, requester.Factory())
+		config.Set(config.LocalUuidFlag, tc.Setting)
+
+		// act
+		cmd.SetArgs(tc.Args)
+		err := cmd.Execute()
+
+		// assert
+		assert.NoError(t, err, "error found for "+tc.Purpose)
+		assert.Regexp(t, tc.Pattern, out.String(), "invalid output for "+tc.Purpose)
+		assert.Len(t, requester.Requests, tc.Calls, "invalid API calls for "+tc.Purpose)
+	}
+	config.Set(config.LocalUuidFlag, "")
+}
+
+func TestExecuteProgrammingUuidLocalWithCount(t *testing.T) {
+	// arrange
+	iostreams, _, out, _ := iostreams.Test()
+	cmd := NewProgrammingUuidCmd(iostreams)
+	config.Set(config.OutputFlag, config.OutputValue)
+	defer config.Set(config.OutputFlag, config.OutputJSON)
+
+	// act
+	cmd.SetArgs([]string{"--local", "--count", "100"})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	uuids := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, uuids, 100)
+	unique := map[string]bool{}
+	for _, uuid := range uuids {
+		unique[uuid] = true
+	}
+	assert.Len(t, unique, 100, "UUIDs must be unique")
+}
+
+func TestUuidPreCheck(t *testing.T) {
+	// arrange
+	viper.Reset()
+	defer viper.Reset()
+	cmd := NewProgrammingUuidCmd(nil)
+
+	// act & assert
+	assert.Error(t, uuidPreCheck(cmd, []string{}), "the API requires configuration")
+
+	cmd.Flags().Set(LocalFlag, "true")
+	assert.NoError(t, uuidPreCheck(cmd, []string{}), "local UUIDs require no configuration")
+}
+
+func TestExecuteProgrammingUuidVersions(t *testing.T) {
+	testCases := []struct {
+		Args     []string
+		Pattern  string
+		Calls    int
+		ErrorNil bool
+		Purpose  string
+	}{
+		{
+			Args:     []string{"--version", "v1"},
+			Pattern:  "\"[0-9a-f]{8}-[0-9a-f]{4}-1[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}\"",
+			ErrorNil: true,
+			Purpose:  "v1 generated locally",
+		},
+		{
+			Args:     []string{"--version", "4"},
+			Pattern:  "da308fbd-cba9-485a-b4c1-6677aaa732a4",
+			Calls:    1,
+			ErrorNil: true,
+			Purpose:  "v4 generated by the API",
+		},
+		{
+			Args:     []string{"--version", "v5", "--namespace", "dns", "--name", "www.example.com"},
+			Pattern:  "\"2ed6657d-e927-568b-95e1-2665a8aea6a2\"",
+			ErrorNil: true,
+			Purpose:  "v5 generated locally",
+		},
+		{
+			Args:     []string{"--version", "V7", "--no-hyphens"},
+			Pattern:  "\"[0-9a-f]{12}7[0-9a-f]{3}[89ab][0-9a-f]{15}\"",
+			ErrorNil: true,
+			Purpose:  "v7 generated locally",
+		},
+		{
+			Args:     []string{"--version", "v3"},
+			ErrorNil: false,
+			Purpose:  "unsupported version",
+		},
+		{
+			Args:     []string{"--version", "v5", "--name", "www.example.com"},
+			ErrorNil: false,
+			Purpose:  "v5 without namespace",
+		},
+		{
+			Args:     []string{"--version", "v5", "--namespace", "unknown", "--name", "x"},
+			ErrorNil: false,
+			Purpose:  "v5 with invalid namespace",
+		},
+		{
+			Args:     []string{"--version", "v5", "--namespace", "dns", "--name", "x", "--count", "2"},
+			ErrorNil: false,
+			Purpose:  "v5 with count",
+		},
+		{
+			Args:     []string{"--version", "v7", "--name", "x"},
+			ErrorNil: false,
+			Purpose:  "name without v5",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		iostreams, _, out, _ := iostreams.Test()
+		requester := &testhelpers.FakeRequester{
+			Response: "{\"uuid\": \"da308fbd-cba9-485a-b4c1-6677aaa732a4\"}",
+		}
+		cmd := NewProgrammingUuidCmd(iostreams)
+		cmd.RunE = executeProgrammingUuid(iostreams, requester.Factory())
+
+		// act
+		cmd.SetArgs(tc.Args)
+		err := cmd.Execute()
+
+		// assert
+		if tc.ErrorNil {
+			assert.NoError(t, err, "error found for "+tc.Purpose)
+			assert.Regexp(t, tc.Pattern, out.String(), "invalid output for "+tc.Purpose)
+			assert.Len(t, requester.Requests, tc.Calls, "invalid API calls for "+tc.Purpose)
+		} else {
+			assert.Equal(t, clierrors.ExitUsage, clierrors.ExitCode(err), "invalid error for "+tc.Purpose)
+		}
+	}
+}
+
+func TestUuidPreCheckForLocalVersions(t *testing.T) {
+	// arrange
+	viper.Reset()
+	defer viper.Reset()
+	cmd := NewProgrammingUuidCmd(nil)
+
+	// act
+	cmd.Flags().Set(VersionFlag, "v7")
+	err := uuidPreCheck(cmd, []string{})
+
+	// assert
+	assert.NoError(t, err, "UUIDs generated locally require no configuration")
+}
@@ -0,0 +1,84 @@
+package programming
+
+import (
+	"strings"
+
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/ids"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/renato0307/learning-go-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// ToFlag selects the format UUIDs are converted to
+const ToFlag string = "to"
+
+// UUID formats
+const (
+	UuidFormatCanonical string = "canonical"
+	UuidFormatHex       string = "hex"
+	UuidFormatBraced    string = "braced"
+	UuidFormatUrn       string = "urn"
+)
+
+// uuidFormats writes UUIDs in each format
+var uuidFormats = map[string]func(uuid ids.UUID) string{
+	UuidFormatCanonical: ids.UUID.String,
+	UuidFormatHex:       ids.UUID.Hex,
+	UuidFormatBraced:    ids.UUID.Braced,
+	UuidFormatUrn:       ids.UUID.URN,
+}
+
+// NewProgrammingUuidConvertCmd represents the programming uuid convert command
+func NewProgrammingUuidConvertCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "convert [uuid...]",
+		Short: "Converts UUIDs between formats",
+		Long: `Converts UUIDs written with hyphens, without hyphens, between braces or as
+URNs to the format selected with --to:
+  canonical  da308fbd-cba9-485a-b4c1-6677aaa732a4
+  hex        da308fbdcba9485ab4c16677aaa732a4
+  braced     {da308fbd-cba9-485a-b4c1-6677aaa732a4}
+  urn        urn:uuid:da308fbd-cba9-485a-b4c1-6677aaa732a4
+
+UUIDs are read from the arguments or, if there are none, from each line of
+the input.`,
+		RunE: executeProgrammingUuidConvert(iostreams),
+	}
+
+	cmd.Flags().String(ToFlag,
+		UuidFormatCanonical,
+		"the format to convert to: canonical, hex, braced or urn")
+
+	return cmd
+}
+
+// executeProgrammingUuidConvert implements all the logic associated with this command.
+func executeProgrammingUuidConvert(iostreams *iostreams.IOStreams) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		to, err := cmd.Flags().GetString(ToFlag)
+		if err != nil {
+			return err
+		}
+		format, found := uuidFormats[strings.ToLower(to)]
+		if !found {
+			return clierrors.Usagef("unknown UUID format %q, valid formats are: "+
+				"canonical, hex, braced, urn", to)
+		}
+
+		stream, err := output.NewStream(iostreams)
+		if err != nil {
+			return err
+		}
+
+		err = eachUuid(iostreams, args, func(uuid ids.UUID) error {
+			return stream.Print(UuidResponse{Uuid: format(uuid)})
+		})
+
+		closeErr := stream.Close()
+		if err != nil {
+			return err
+		}
+		return closeErr
+	}
+}
@@ -0,0 +1,85 @@
+package programming
+
+import (
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProgrammingUuidConvertCmd(t *testing.T) {
+	// act
+	cmd := NewProgrammingUuidConvertCmd(nil)
+
+	// assert
+	assert.Equal(t, "convert", cmd.Name())
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+	assert.NotNil(t, cmd.Flags().Lookup(ToFlag))
+}
+
+func TestExecuteProgrammingUuidConvert(t *testing.T) {
+	testCases := []struct {
+		Args     []string
+		Expected string
+		ErrorNil bool
+		Purpose  string
+	}{
+		{
+			Args:     []string{"DA308FBDCBA9485AB4C16677AAA732A4"},
+			Expected: "da308fbd-cba9-485a-b4c1-6677aaa732a4\n",
+			ErrorNil: true,
+			Purpose:  "canonical by default",
+		},
+		{
+			Args:     []string{"--to", "hex", "da308fbd-cba9-485a-b4c1-6677aaa732a4"},
+			Expected: "da308fbdcba9485ab4c16677aaa732a4\n",
+			ErrorNil: true,
+			Purpose:  "to hex",
+		},
+		{
+			Args:     []string{"--to", "braced", "urn:uuid:da308fbd-cba9-485a-b4c1-6677aaa732a4"},
+			Expected: "{da308fbd-cba9-485a-b4c1-6677aaa732a4}\n",
+			ErrorNil: true,
+			Purpose:  "to braced",
+		},
+		{
+			Args:     []string{"--to", "urn", "{da308fbd-cba9-485a-b4c1-6677aaa732a4}"},
+			Expected: "urn:uuid:da308fbd-cba9-485a-b4c1-6677aaa732a4\n",
+			ErrorNil: true,
+			Purpose:  "to urn",
+		},
+		{
+			Args:     []string{"--to", "base64", "da308fbd-cba9-485a-b4c1-6677aaa732a4"},
+			ErrorNil: false,
+			Purpose:  "unknown format",
+		},
+		{
+			Args:     []string{"not-an-uuid"},
+			ErrorNil: false,
+			Purpose:  "invalid UUID",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		iostreams, _, out, _ := iostreams.Test()
+		cmd := NewProgrammingUuidConvertCmd(iostreams)
+		config.Set(config.OutputFlag, config.OutputValue)
+
+		// act
+		cmd.SetArgs(tc.Args)
+		err := cmd.Execute()
+
+		// assert
+		if tc.ErrorNil {
+			assert.NoError(t, err, "error found for "+tc.Purpose)
+			assert.Equal(t, tc.Expected, out.String(), "invalid output for "+tc.Purpose)
+		} else {
+			assert.Error(t, err, "error not found for "+tc.Purpose)
+		}
+	}
+	config.Set(config.OutputFlag, config.OutputJSON)
+}
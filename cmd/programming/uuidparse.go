@@ -0,0 +1,98 @@
+package programming
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/renato0307/learning-go-cli/internal/ids"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/renato0307/learning-go-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// UuidInfo represents what is known about an UUID
+type UuidInfo struct {
+	Uuid      string `json:"uuid"`
+	Version   int    `json:"version"`
+	Variant   string `json:"variant"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Hex       string `json:"hex"`
+	Braced    string `json:"braced"`
+	Urn       string `json:"urn"`
+}
+
+// NewProgrammingUuidParseCmd represents the programming uuid parse command
+func NewProgrammingUuidParseCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "parse [uuid...]",
+		Short: "Parses UUIDs",
+		Long: `Parses UUIDs, reporting their version and variant, when time-based UUIDs
+(versions 1 and 7) were generated and the UUIDs in all the supported formats.
+
+UUIDs can be written with hyphens, without hyphens, between braces or as URNs.
+They are read from the arguments or, if there are none, from each line of the
+input, like:
+
+  grep -o 'request [0-9a-f-]*' app.log | cut -d' ' -f2 | learning-go-cli programming uuid parse`,
+		RunE: executeProgrammingUuidParse(iostreams),
+	}
+
+	return cmd
+}
+
+// executeProgrammingUuidParse implements all the logic associated with this command.
+func executeProgrammingUuidParse(iostreams *iostreams.IOStreams) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		stream, err := output.NewStream(iostreams)
+		if err != nil {
+			return err
+		}
+
+		err = eachUuid(iostreams, args, func(uuid ids.UUID) error {
+			info := UuidInfo{
+				Uuid:    uuid.String(),
+				Version: uuid.Version(),
+				Variant: uuid.Variant(),
+				Hex:     uuid.Hex(),
+				Braced:  uuid.Braced(),
+				Urn:     uuid.URN(),
+			}
+			if timestamp, found := uuid.Time(); found {
+				info.Timestamp = timestamp.Format(time.RFC3339Nano)
+			}
+			return stream.Print(info)
+		})
+
+		closeErr := stream.Close()
+		if err != nil {
+			return err
+		}
+		return closeErr
+	}
+}
+
+// eachUuid calls the handler for each UUID in the arguments or the input.
+// Invalid UUIDs are reported in the error output and returned together at the
+// end.
+func eachUuid(iostreams *iostreams.IOStreams, args []string, handler func(uuid ids.UUID) error) error {
+	total := 0
+	invalid := 0
+	err := eachInput(iostreams, args, func(value string) error {
+		total++
+		uuid, err := ids.Parse(value)
+		if err != nil {
+			invalid++
+			iostreams.Errorf("%s\n", err)
+			return nil
+		}
+		return handler(uuid)
+	})
+	if err != nil {
+		return err
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d values are not valid UUIDs", invalid, total)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package programming
+
+import (
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProgrammingUuidParseCmd(t *testing.T) {
+	// act
+	cmd := NewProgrammingUuidParseCmd(nil)
+
+	// assert
+	assert.Equal(t, "parse", cmd.Name())
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+}
+
+func TestExecuteProgrammingUuidParse(t *testing.T) {
+	// arrange
+	iostreams, _, out, errOut := iostreams.Test()
+	cmd := NewProgrammingUuidParseCmd(iostreams)
+
+	// act
+	cmd.SetArgs([]string{
+		"urn:uuid:017f22e2-79b0-7fff-bfff-ffffffffffff",
+		"not-an-uuid",
+		"{DA308FBD-CBA9-485A-B4C1-6677AAA732A4}",
+	})
+	err := cmd.Execute()
+
+	// assert
+	assert.EqualError(t, err, "1 of 3 values are not valid UUIDs")
+	assert.Equal(t, `{"uuid":"017f22e2-79b0-7fff-bfff-ffffffffffff","version":7,"variant":"RFC 4122",`+
+		`"timestamp":"2022-02-22T19:22:22Z","hex":"017f22e279b07fffbfffffffffffffff",`+
+		`"braced":"{017f22e2-79b0-7fff-bfff-ffffffffffff}","urn":"urn:uuid:017f22e2-79b0-7fff-bfff-ffffffffffff"}
+{"uuid":"da308fbd-cba9-485a-b4c1-6677aaa732a4","version":4,"variant":"RFC 4122",`+
+		`"hex":"da308fbdcba9485ab4c16677aaa732a4","braced":"{da308fbd-cba9-485a-b4c1-6677aaa732a4}",`+
+		`"urn":"urn:uuid:da308fbd-cba9-485a-b4c1-6677aaa732a4"}
+`, out.String())
+	assert.Equal(t, "\"not-an-uuid\" is not an UUID\n", errOut.String())
+}
+
+func TestExecuteProgrammingUuidParseFromInput(t *testing.T) {
+	// arrange
+	iostreams, in, out, _ := iostreams.Test()
+	in.WriteString("da308fbdcba9485ab4c16677aaa732a4\n")
+	cmd := NewProgrammingUuidParseCmd(iostreams)
+
+	// act
+	cmd.SetArgs([]string{})
+	err := cmd.Execute()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "\"uuid\":\"da308fbd-cba9-485a-b4c1-6677aaa732a4\"")
+}
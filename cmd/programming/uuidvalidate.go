@@ -0,0 +1,65 @@
+package programming
+
+import (
+	"fmt"
+
+	"github.com/renato0307/learning-go-cli/internal/ids"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/renato0307/learning-go-cli/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// UuidValidation represents the result of validating a value
+type UuidValidation struct {
+	Input string `json:"input"`
+	Valid bool   `json:"valid"`
+}
+
+// NewProgrammingUuidValidateCmd represents the programming uuid validate command
+func NewProgrammingUuidValidateCmd(iostreams *iostreams.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate [uuid...]",
+		Short: "Validates UUIDs",
+		Long: `Validates UUIDs written with hyphens, without hyphens, between braces or
+as URNs, failing if any of them is invalid.
+
+UUIDs are read from the arguments or, if there are none, from each line of
+the input.`,
+		RunE: executeProgrammingUuidValidate(iostreams),
+	}
+
+	return cmd
+}
+
+// executeProgrammingUuidValidate implements all the logic associated with this command.
+func executeProgrammingUuidValidate(iostreams *iostreams.IOStreams) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		stream, err := output.NewStream(iostreams)
+		if err != nil {
+			return err
+		}
+
+		total := 0
+		invalid := 0
+		err = eachInput(iostreams, args, func(value string) error {
+			total++
+			_, err := ids.Parse(value)
+			if err != nil {
+				invalid++
+			}
+			return stream.Print(UuidValidation{Input: value, Valid: err == nil})
+		})
+
+		closeErr := stream.Close()
+		if err != nil {
+			return err
+		}
+		if closeErr != nil {
+			return closeErr
+		}
+		if invalid > 0 {
+			return fmt.Errorf("%d of %d values are not valid UUIDs", invalid, total)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,67 @@
+package programming
+
+import (
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProgrammingUuidValidateCmd(t *testing.T) {
+	// act
+	cmd := NewProgrammingUuidValidateCmd(nil)
+
+	// assert
+	assert.Equal(t, "validate", cmd.Name())
+	assert.NotEmpty(t, cmd.Short, "Short description cannot be empty")
+	assert.NotEmpty(t, cmd.Long, "Long description cannot be empty")
+	assert.NotNil(t, cmd.RunE, "The RunE function must be defined")
+}
+
+func TestExecuteProgrammingUuidValidate(t *testing.T) {
+	testCases := []struct {
+		Input          string
+		ExpectedOutput string
+		ErrorNil       bool
+		Purpose        string
+	}{
+		{
+			Input: "da308fbd-cba9-485a-b4c1-6677aaa732a4\nda308fbdcba9485ab4c16677aaa732a4\n",
+			ExpectedOutput: "INPUT                                 VALID\n" +
+				"da308fbd-cba9-485a-b4c1-6677aaa732a4  true\n" +
+				"da308fbdcba9485ab4c16677aaa732a4      true\n",
+			ErrorNil: true,
+			Purpose:  "valid UUIDs",
+		},
+		{
+			Input: "da308fbd-cba9-485a-b4c1-6677aaa732a4\nnot-an-uuid\n",
+			ExpectedOutput: "INPUT                                 VALID\n" +
+				"da308fbd-cba9-485a-b4c1-6677aaa732a4  true\n" +
+				"not-an-uuid                           false\n",
+			ErrorNil: false,
+			Purpose:  "invalid UUID",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		iostreams, in, out, _ := iostreams.Test()
+		in.WriteString(tc.Input)
+		cmd := NewProgrammingUuidValidateCmd(iostreams)
+		config.Set(config.OutputFlag, config.OutputTable)
+
+		// act
+		cmd.SetArgs([]string{})
+		err := cmd.Execute()
+
+		// assert
+		if tc.ErrorNil {
+			assert.NoError(t, err, "error found for "+tc.Purpose)
+		} else {
+			assert.Error(t, err, "error not found for "+tc.Purpose)
+		}
+		assert.Equal(t, tc.ExpectedOutput, out.String(), "invalid output for "+tc.Purpose)
+	}
+	config.Set(config.OutputFlag, config.OutputJSON)
+}
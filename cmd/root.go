@@ -1,11 +1,24 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
+	"github.com/renato0307/learning-go-cli/cmd/apicmd"
+	"github.com/renato0307/learning-go-cli/cmd/authcmd"
+	"github.com/renato0307/learning-go-cli/cmd/configcmd"
+	"github.com/renato0307/learning-go-cli/cmd/finance"
 	"github.com/renato0307/learning-go-cli/cmd/programming"
+	"github.com/renato0307/learning-go-cli/internal/auth"
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
 	"github.com/renato0307/learning-go-cli/internal/config"
 	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/spf13/cast"
 	"github.com/spf13/cobra"
 )
 
@@ -14,24 +27,148 @@ var rootCmd = &cobra.Command{
 	Use:   "learning-go-cli",
 	Short: "CLI for the learning-go-api",
 	Long: `The learning-go-api provides with utility functions like UUID
-generation, a currency converter, a JWT debugger, etc.`,
+generation, a currency converter, a JWT debugger, etc.
+
+Every setting of the config file can be overridden with an environment
+variable named after it, like LEARNING_GO_CLI_CLIENT_SECRET for
+client-secret. Only the settings often changed for a single command have
+global flags: client-id, api-endpoint, token-endpoint, output and timeout.
+The other settings are left out on purpose:
+  - secrets, like client-secret and refresh-token, so they never show in
+    the shell history or the process list
+  - the authentication settings, like auth-method, scopes, private-key or
+    the login endpoints, which describe how a profile logs in and are set
+    with configure or config set
+  - the retry, token, secret store and header settings, which rarely
+    change between commands; --no-retry disables the retries
+  - default-profile and local-uuid, already covered by the --profile flag
+    and the --local flag of programming uuid
+Use the environment variables to override them for a single command.`,
 	Version: "0.0.1",
+
+	// errors are printed by Execute
+	SilenceErrors: true,
+	SilenceUsage:  true,
+
+	PersistentPreRunE: validateProfile,
 }
 
 // Execute adds all child commands to the root command and sets flags
 // appropriately. This is called by main.main(). It only needs to happen once
 // to the rootCmd.
+// The commands are executed with a context cancelled on SIGINT and SIGTERM,
+// aborting in-flight requests, and requesting the scopes of the command.
+// Errors are printed to the error output, as JSON if the JSON output was
+// explicitly selected, and the CLI exits with the code matching the kind of
+// the error, as documented in the README.
 func Execute() {
-	cobra.CheckErr(rootCmd.Execute())
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	cmd, err := rootCmd.ExecuteContextC(auth.WithScopes(ctx, commandScopes(os.Args[1:])))
+
+	// stop cancels the context so it must be checked before
+	if cancelled(ctx, err) {
+		err = clierrors.ErrCancelled
+	}
+	stop()
+
+	if err == nil {
+		return
+	}
+	err = usageError(err)
+
+	asJSON := jsonErrors()
+	clierrors.Print(rootCmd.ErrOrStderr(), err, asJSON)
+	if !asJSON && clierrors.ExitCode(err) == clierrors.ExitUsage {
+		fmt.Fprintf(rootCmd.ErrOrStderr(), "Run '%s --help' for usage.\n", cmd.CommandPath())
+	}
+	os.Exit(clierrors.ExitCode(err))
+}
+
+// commandScopes returns the scopes required by the command of the arguments,
+// found as cobra finds the command to execute, so they are requested in the
+// access tokens
+func commandScopes(args []string) []string {
+	cmd, _, err := rootCmd.Find(args)
+	if err != nil {
+		return nil
+	}
+	return config.RequiredScopes(cmd)
+}
+
+// validateProfile checks the name of the selected profile before any command
+// reads or writes its settings. An invalid name is a usage error when given
+// with --profile and a configuration error otherwise.
+func validateProfile(cmd *cobra.Command, args []string) error {
+	profile := config.Profile()
+	err := config.ValidateProfileName(profile)
+	if err == nil {
+		return nil
+	}
+	if cmd.Flags().Changed(config.ProfileFlag) {
+		return &clierrors.UsageError{Err: err}
+	}
+	return &config.InvalidConfigError{Profile: profile, Err: err}
+}
+
+// cancelled checks if the execution failed because the context was cancelled
+func cancelled(ctx context.Context, err error) bool {
+	return err != nil && (ctx.Err() != nil || errors.Is(err, context.Canceled))
+}
+
+// usageError turns the errors of unknown commands, which cobra does not type,
+// into usage errors
+func usageError(err error) error {
+	if strings.HasPrefix(err.Error(), "unknown command") {
+		return &clierrors.UsageError{Err: err}
+	}
+	return err
+}
+
+// jsonErrors checks if errors must be printed as JSON, which happens when the
+// JSON output is explicitly selected and not just the default
+func jsonErrors() bool {
+	format, source := config.Lookup(config.OutputFlag)
+	return source != config.SourceDefault && cast.ToString(format) == config.OutputJSON
 }
 
 func init() {
 	cobra.OnInitialize(config.InitConfig)
+	rootCmd.SetFlagErrorFunc(clierrors.UsageFlagErrorFunc)
 
-	iostreams := &iostreams.IOStreams{Out: os.Stdout}
+	rootCmd.PersistentFlags().String(config.ProfileFlag,
+		"",
+		fmt.Sprintf("the configuration profile to use (env %s)", config.ProfileEnvVar))
+	config.BindFlag(config.ProfileFlag,
+		rootCmd.PersistentFlags().Lookup(config.ProfileFlag))
+	config.AddFlags(rootCmd.PersistentFlags())
+
+	rootCmd.PersistentFlags().String(config.JqFlag,
+		"",
+		"a jq filter applied to the output, like .uuid")
+	config.BindFlag(config.JqFlag,
+		rootCmd.PersistentFlags().Lookup(config.JqFlag))
+
+	rootCmd.PersistentFlags().Bool(config.NoRetryFlag,
+		false,
+		"if set failed requests are not retried")
+	config.BindFlag(config.NoRetryFlag,
+		rootCmd.PersistentFlags().Lookup(config.NoRetryFlag))
+
+	iostreams := iostreams.System()
+	rootCmd.SetIn(iostreams.In)
+	rootCmd.SetOut(iostreams.Out)
+	rootCmd.SetErr(iostreams.ErrOut)
 
 	rootCmd.AddCommand(NewConfigureCommand(iostreams))
+	rootCmd.AddCommand(configcmd.NewConfigCmd(iostreams))
+	rootCmd.AddCommand(authcmd.NewAuthCmd(iostreams))
 
 	programmingCmd := programming.NewProgrammingCmd(iostreams)
 	config.AddCommandWithConfigPreCheck(rootCmd, programmingCmd)
+
+	financeCmd := finance.NewFinanceCmd(iostreams)
+	config.AddCommandWithConfigPreCheck(rootCmd, financeCmd)
+
+	apiCmd := apicmd.NewApiCmd(iostreams)
+	config.AddCommandWithConfigPreCheck(rootCmd, apiCmd)
 }
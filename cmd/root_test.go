@@ -1,10 +1,153 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/clierrors"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestExecute(t *testing.T) {
 	// act
 	Execute() // this is only for coverage purposes, executing will exit(1)
 }
+
+func TestRootCmdFlags(t *testing.T) {
+	// assert
+	assert.NotNil(t, rootCmd.PersistentFlags().Lookup(config.ProfileFlag))
+	assert.NotNil(t, rootCmd.PersistentFlags().Lookup(config.APIEndpointFlag))
+	assert.NotNil(t, rootCmd.PersistentFlags().Lookup(config.TokenEndpointFlag))
+}
+
+func TestRootCmdRetryFlag(t *testing.T) {
+	// assert
+	assert.NotNil(t, rootCmd.PersistentFlags().Lookup(config.NoRetryFlag))
+}
+
+func TestRootCmdHasNoSecretFlags(t *testing.T) {
+	for _, setting := range config.Settings {
+		if setting.Secret {
+			assert.Nil(t, rootCmd.PersistentFlags().Lookup(setting.Key),
+				"secret "+setting.Key+" must not have a flag")
+		}
+	}
+}
+
+func TestCancelled(t *testing.T) {
+	// arrange
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// assert
+	assert.False(t, cancelled(context.Background(), nil))
+	assert.False(t, cancelled(context.Background(), errors.New("failure")))
+	assert.True(t, cancelled(cancelledCtx, errors.New("failure")))
+	assert.True(t, cancelled(context.Background(), fmt.Errorf("wrapped: %w", context.Canceled)))
+}
+
+func TestCommandScopes(t *testing.T) {
+	// arrange
+	scoped := &cobra.Command{Use: "scoped", RunE: func(cmd *cobra.Command, args []string) error { return nil }}
+	config.RequireScopes(scoped, "read")
+	rootCmd.AddCommand(scoped)
+	defer rootCmd.RemoveCommand(scoped)
+
+	// act & assert
+	assert.Equal(t, []string{"read"}, commandScopes([]string{"--profile", "test", "scoped", "--no-retry"}))
+	assert.Empty(t, commandScopes([]string{"configure"}))
+	assert.Empty(t, commandScopes([]string{"unknown"}))
+}
+
+func TestValidateProfile(t *testing.T) {
+	testCases := []struct {
+		Args     []string
+		Env      string
+		ExitCode int
+		Purpose  string
+	}{
+		{
+			Args:     []string{"config", "set", "--profile", "a.b", config.ClientIdFlag, "value"},
+			ExitCode: clierrors.ExitUsage,
+			Purpose:  "invalid profile flag",
+		},
+		{
+			Args:     []string{"config", "set", config.ClientIdFlag, "value"},
+			Env:      "a.b",
+			ExitCode: clierrors.ExitConfig,
+			Purpose:  "invalid profile environment variable",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		t.Setenv("HOME", t.TempDir())
+		t.Setenv(config.ProfileEnvVar, tc.Env)
+		flag := rootCmd.PersistentFlags().Lookup(config.ProfileFlag)
+
+		// act
+		rootCmd.SetArgs(tc.Args)
+		err := rootCmd.Execute()
+		flag.Changed = false
+		flag.Value.Set("")
+
+		// assert
+		assert.EqualError(t, err,
+			`invalid profile name "a.b": profile names cannot be empty or contain "."`,
+			tc.Purpose)
+		assert.Equal(t, tc.ExitCode, clierrors.ExitCode(err), tc.Purpose)
+		content, _ := os.ReadFile(filepath.Join(os.Getenv("HOME"), ".learning-go-cli.yaml"))
+		assert.NotContains(t, string(content), "value", "the setting must not be written for "+tc.Purpose)
+	}
+}
+
+func TestRootCmdTimeoutFlag(t *testing.T) {
+	// assert
+	assert.NotNil(t, rootCmd.PersistentFlags().Lookup(config.TimeoutFlag))
+}
+
+func TestRootCmdOutputFlag(t *testing.T) {
+	// act
+	flag := rootCmd.PersistentFlags().ShorthandLookup("o")
+
+	// assert
+	assert.NotNil(t, flag)
+	assert.Equal(t, config.OutputFlag, flag.Name)
+}
+
+func TestRootCmdJqFlag(t *testing.T) {
+	// assert
+	assert.NotNil(t, rootCmd.PersistentFlags().Lookup(config.JqFlag))
+}
+
+func TestUsageError(t *testing.T) {
+	// act
+	unknownCommand := usageError(errors.New(`unknown command "x" for "learning-go-cli"`))
+	other := usageError(errors.New("failure"))
+
+	// assert
+	assert.Equal(t, clierrors.ExitUsage, clierrors.ExitCode(unknownCommand))
+	assert.Equal(t, clierrors.ExitError, clierrors.ExitCode(other))
+}
+
+func TestJsonErrors(t *testing.T) {
+	// arrange
+	viper.Reset()
+	defer viper.Reset()
+
+	// act & assert
+	assert.False(t, jsonErrors(), "default output must not print errors as json")
+
+	config.Set(config.OutputFlag, config.OutputJSON)
+	assert.True(t, jsonErrors(), "selected json output must print errors as json")
+
+	config.Set(config.OutputFlag, config.OutputYAML)
+	assert.False(t, jsonErrors(), "other outputs must not print errors as json")
+}
@@ -0,0 +1,269 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/renato0307/learning-go-cli/internal/auth"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/retry"
+)
+
+// userAgent identifies the CLI in the API calls
+const userAgent string = "learning-go-cli"
+
+// Request describes a call to the API. A Header with the authentication
+// header of the client is sent instead of the access token. The Body is sent
+// as JSON, unless it is a []byte or an io.Reader, which are sent as they are
+// without a default Content-Type.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   interface{}
+
+	// Idempotent marks requests that can be retried even if their method
+	// is not idempotent, like a POST without side effects
+	Idempotent bool
+}
+
+// Requester is used by commands to call the API, allowing them to be tested
+// without a running server
+type Requester interface {
+	Do(ctx context.Context, request Request, response interface{}) error
+}
+
+// RawRequester is a Requester also returning the HTTP responses as they are,
+// for commands printing responses that are not JSON
+type RawRequester interface {
+	Requester
+	DoRaw(ctx context.Context, request Request) (*http.Response, error)
+}
+
+// Factory creates the Requester used by a command
+type Factory func() Requester
+
+// DefaultFactory creates a client using the configuration
+func DefaultFactory() Requester {
+	return NewClientFromConfig()
+}
+
+// TokenSource returns the access token used to authenticate API calls
+type TokenSource func(ctx context.Context) (auth.AccessToken, error)
+
+// ReuseToken returns a TokenSource keeping the token returned by the source
+// while it is not expired, so concurrent calls share a single token. Tokens
+// without an expiration are kept for the life of the process, like the
+// commands processing many inputs.
+func ReuseToken(source TokenSource) TokenSource {
+	mu := sync.Mutex{}
+	fetched := false
+	cached := auth.CachedToken{}
+	return func(ctx context.Context) (auth.AccessToken, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if fetched && (cached.ExpiresIn <= 0 || !cached.Expired(time.Now(), auth.ExpirySkew())) {
+			return cached.AccessToken, nil
+		}
+
+		issuedAt := time.Now()
+		token, err := source(ctx)
+		if err != nil {
+			return token, err
+		}
+		fetched = true
+		cached = auth.CachedToken{AccessToken: token, IssuedAt: issuedAt}
+		return token, nil
+	}
+}
+
+// Client calls the learning-go-api, handling authentication, JSON encoding
+// and decoding and errors
+type Client struct {
+	BaseURL     string
+	HTTPClient  *http.Client
+	TokenSource TokenSource
+	Header      http.Header
+
+	// AuthHeader is the header sending the access token and AuthScheme the
+	// scheme before it, the token type if empty or config.AuthSchemeNone to
+	// send the token alone, for deployments not using Authorization: Bearer
+	AuthHeader string
+	AuthScheme string
+}
+
+// NewClient creates a client for the API with the given base URL
+func NewClient(baseURL string, tokenSource TokenSource) *Client {
+	return &Client{
+		BaseURL:     strings.TrimSuffix(baseURL, "/"),
+		HTTPClient:  http.DefaultClient,
+		TokenSource: tokenSource,
+		Header: http.Header{
+			"User-Agent": {userAgent},
+			"Accept":     {"application/json"},
+		},
+		AuthHeader: config.DefaultAuthHeader,
+	}
+}
+
+// NewClientFromConfig creates a client for the configured API endpoint,
+// authenticated with the cached access tokens, reused by all the calls of the
+// client, and retrying failed requests with the configured policy
+func NewClientFromConfig() *Client {
+	client := NewClient(
+		config.GetString(config.APIEndpointFlag),
+		ReuseToken(auth.GetAccessToken))
+	client.HTTPClient = retry.NewClient(
+		retry.PolicyFromConfig(),
+		config.GetDuration(config.TimeoutFlag))
+	client.AuthHeader = config.GetString(config.AuthHeaderFlag)
+	client.AuthScheme = config.GetString(config.AuthSchemeFlag)
+	return client
+}
+
+// Do calls the API and decodes the JSON response into the response argument,
+// unless it is nil. Responses with a status code other than 2xx are returned
+// as an *APIError. The call is aborted when the context is done.
+func (c *Client) Do(ctx context.Context, request Request, response interface{}) error {
+	httpResponse, err := c.DoRaw(ctx, request)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	content, err := ioutil.ReadAll(httpResponse.Body)
+	if err != nil {
+		return fmt.Errorf("error reading the API response: %w", err)
+	}
+
+	if httpResponse.StatusCode < 200 || httpResponse.StatusCode > 299 {
+		return DecodeError(httpResponse.StatusCode, httpResponse.Header, content)
+	}
+
+	if response == nil {
+		return nil
+	}
+	err = json.Unmarshal(content, response)
+	if err != nil {
+		return fmt.Errorf("error parsing the API response: %w", err)
+	}
+	return nil
+}
+
+// DoRaw calls the API returning the HTTP response as is. The caller must
+// close the response body.
+func (c *Client) DoRaw(ctx context.Context, request Request) (*http.Response, error) {
+	httpRequest, err := c.newHTTPRequest(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	httpResponse, err := c.HTTPClient.Do(httpRequest)
+	if err != nil {
+		return nil, fmt.Errorf("error calling the API: %w", err)
+	}
+	return httpResponse, nil
+}
+
+// newHTTPRequest creates the HTTP request for an API call, adding the body,
+// the headers and the authentication
+func (c *Client) newHTTPRequest(ctx context.Context, request Request) (*http.Request, error) {
+	requestUrl := fmt.Sprintf("%s/%s", c.BaseURL, strings.TrimPrefix(request.Path, "/"))
+	if len(request.Query) > 0 {
+		requestUrl = fmt.Sprintf("%s?%s", requestUrl, request.Query.Encode())
+	}
+
+	body, err := encodeBody(request.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, request.Method, requestUrl, body)
+	if err != nil {
+		return nil, fmt.Errorf("error creating the request to call the API: %w", err)
+	}
+
+	for name, values := range c.Header {
+		httpRequest.Header[name] = values
+	}
+	switch request.Body.(type) {
+	case nil, []byte, io.Reader:
+	default:
+		httpRequest.Header.Set("Content-Type", "application/json")
+	}
+	for name, values := range request.Header {
+		httpRequest.Header[http.CanonicalHeaderKey(name)] = values
+	}
+
+	if request.Idempotent {
+		httpRequest = retry.MarkIdempotent(httpRequest)
+	}
+
+	// a token given in the request replaces the one of the CLI
+	if c.TokenSource != nil && httpRequest.Header.Get(c.authHeader()) == "" {
+		token, err := c.TokenSource(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error getting the JWT to call the API: %w", err)
+		}
+		c.authenticate(httpRequest, token)
+	}
+
+	return httpRequest, nil
+}
+
+// authenticate adds the access token to the request, in the Authorization
+// header with the token type as scheme unless configured otherwise
+func (c *Client) authenticate(httpRequest *http.Request, token auth.AccessToken) {
+	header := c.authHeader()
+	scheme := c.AuthScheme
+	if scheme == "" {
+		scheme = token.TokenType
+	}
+	switch {
+	case strings.EqualFold(scheme, config.AuthSchemeNone):
+		httpRequest.Header.Set(header, token.AccessToken)
+		return
+	case scheme == "" || strings.EqualFold(scheme, "bearer"):
+		// token endpoints often return the type in lower case
+		scheme = "Bearer"
+	}
+	httpRequest.Header.Set(header, scheme+" "+token.AccessToken)
+}
+
+// authHeader returns the header sending the access token
+func (c *Client) authHeader() string {
+	if c.AuthHeader == "" {
+		return config.DefaultAuthHeader
+	}
+	return c.AuthHeader
+}
+
+// encodeBody returns a reader for the request body. Readers and byte slices
+// are sent as is while other values are encoded as JSON.
+func encodeBody(body interface{}) (io.Reader, error) {
+	switch value := body.(type) {
+	case nil:
+		return nil, nil
+	case io.Reader:
+		return value, nil
+	case []byte:
+		return bytes.NewReader(value), nil
+	}
+
+	content, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding the request body: %w", err)
+	}
+	return bytes.NewReader(content), nil
+}
@@ -0,0 +1,378 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/auth"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func fakeTokenSource(ctx context.Context) (auth.AccessToken, error) {
+	return auth.AccessToken{AccessToken: "token", TokenType: "Bearer"}, nil
+}
+
+func TestNewClientFromConfig(t *testing.T) {
+	// arrange
+	config.Set(config.APIEndpointFlag, "https://api.example.com/")
+
+	// act
+	client := NewClientFromConfig()
+
+	// assert
+	assert.Equal(t, "https://api.example.com", client.BaseURL)
+	assert.NotNil(t, client.TokenSource)
+	assert.NotNil(t, client.HTTPClient)
+	assert.Equal(t, config.DefaultAuthHeader, client.AuthHeader)
+	assert.Empty(t, client.AuthScheme)
+}
+
+func TestClientDo(t *testing.T) {
+	type response struct {
+		Value string `json:"value"`
+	}
+
+	testCases := []struct {
+		Request         Request
+		TokenSource     TokenSource
+		StatusCode      int
+		Body            string
+		ExpectedBody    string
+		ExpectedQuery   string
+		ExpectedMethod  string
+		ExpectedPath    string
+		Response        response
+		ErrorContains   string
+		Purpose         string
+		ExpectedHeaders map[string]string
+	}{
+		{
+			Request: Request{
+				Method: http.MethodPost,
+				Path:   "/programming/uuid",
+				Query:  url.Values{"no-hyphens": {"true"}},
+			},
+			TokenSource:    fakeTokenSource,
+			StatusCode:     http.StatusOK,
+			Body:           `{"value": "ok"}`,
+			ExpectedQuery:  "no-hyphens=true",
+			ExpectedMethod: http.MethodPost,
+			ExpectedPath:   "/programming/uuid",
+			Response:       response{Value: "ok"},
+			ExpectedHeaders: map[string]string{
+				"Authorization": "Bearer token",
+				"User-Agent":    userAgent,
+			},
+			Purpose: "success case",
+		},
+		{
+			Request: Request{
+				Method: http.MethodPut,
+				Path:   "items",
+				Header: http.Header{"x-custom": {"custom"}},
+				Body:   map[string]string{"key": "value"},
+			},
+			StatusCode:     http.StatusOK,
+			Body:           `{"value": "ok"}`,
+			ExpectedBody:   `{"key":"value"}`,
+			ExpectedMethod: http.MethodPut,
+			ExpectedPath:   "/items",
+			Response:       response{Value: "ok"},
+			ExpectedHeaders: map[string]string{
+				"Content-Type": "application/json",
+				"X-Custom":     "custom",
+			},
+			Purpose: "success case with body and headers",
+		},
+		{
+			Request: Request{
+				Method: http.MethodPost,
+				Path:   "items",
+				Body:   []byte("plain body"),
+			},
+			StatusCode:     http.StatusOK,
+			Body:           `{"value": "ok"}`,
+			ExpectedBody:   "plain body",
+			ExpectedMethod: http.MethodPost,
+			ExpectedPath:   "/items",
+			Response:       response{Value: "ok"},
+			ExpectedHeaders: map[string]string{
+				"Content-Type": "",
+			},
+			Purpose: "raw body sent without content type",
+		},
+		{
+			Request:       Request{Method: http.MethodGet, Path: "/items"},
+			StatusCode:    http.StatusBadRequest,
+			Body:          `{"message": "request is malformed"}`,
+			ErrorContains: "request is malformed",
+			Purpose:       "api returns error",
+		},
+		{
+			Request:       Request{Method: http.MethodGet, Path: "/items"},
+			StatusCode:    http.StatusInternalServerError,
+			Body:          "internal error",
+			ErrorContains: "internal error",
+			Purpose:       "api returns error without json",
+		},
+		{
+			Request:       Request{Method: http.MethodGet, Path: "/items"},
+			StatusCode:    http.StatusOK,
+			Body:          "something that is not a valid json",
+			ErrorContains: "error parsing the API response",
+			Purpose:       "invalid json",
+		},
+		{
+			Request: Request{Method: http.MethodGet, Path: "/items"},
+			TokenSource: func(ctx context.Context) (auth.AccessToken, error) {
+				return auth.AccessToken{}, errors.New("no token")
+			},
+			ErrorContains: "no token",
+			Purpose:       "token error",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		var received *http.Request
+		var receivedBody []byte
+		srv := httptest.NewServer(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+				receivedBody, _ = ioutil.ReadAll(r.Body)
+				w.WriteHeader(tc.StatusCode)
+				w.Write([]byte(tc.Body))
+			}))
+		defer srv.Close()
+
+		client := NewClient(srv.URL, tc.TokenSource)
+
+		// act
+		result := response{}
+		err := client.Do(context.Background(), tc.Request, &result)
+
+		// assert
+		if tc.ErrorContains != "" {
+			assert.Error(t, err, "error not found for "+tc.Purpose)
+			assert.Contains(t, err.Error(), tc.ErrorContains, "invalid error for "+tc.Purpose)
+			continue
+		}
+		assert.NoError(t, err, "error found for "+tc.Purpose)
+		assert.Equal(t, tc.Response, result, "invalid response for "+tc.Purpose)
+		assert.Equal(t, tc.ExpectedMethod, received.Method, "invalid method for "+tc.Purpose)
+		assert.Equal(t, tc.ExpectedPath, received.URL.Path, "invalid path for "+tc.Purpose)
+		assert.Equal(t, tc.ExpectedQuery, received.URL.RawQuery, "invalid query for "+tc.Purpose)
+		assert.Equal(t, tc.ExpectedBody, string(receivedBody), "invalid body for "+tc.Purpose)
+		for name, value := range tc.ExpectedHeaders {
+			assert.Equal(t, value, received.Header.Get(name), "invalid header for "+tc.Purpose)
+		}
+	}
+}
+
+func TestClientAuthHeader(t *testing.T) {
+	testCases := []struct {
+		Purpose        string
+		TokenType      string
+		AuthHeader     string
+		AuthScheme     string
+		ExpectedHeader string
+		ExpectedValue  string
+	}{
+		{
+			Purpose:        "bearer token type in lower case",
+			TokenType:      "bearer",
+			ExpectedHeader: "Authorization",
+			ExpectedValue:  "Bearer token",
+		},
+		{
+			Purpose:        "missing token type",
+			ExpectedHeader: "Authorization",
+			ExpectedValue:  "Bearer token",
+		},
+		{
+			Purpose:        "other token type",
+			TokenType:      "DPoP",
+			ExpectedHeader: "Authorization",
+			ExpectedValue:  "DPoP token",
+		},
+		{
+			Purpose:        "configured scheme",
+			TokenType:      "Bearer",
+			AuthScheme:     "Token",
+			ExpectedHeader: "Authorization",
+			ExpectedValue:  "Token token",
+		},
+		{
+			Purpose:        "legacy header without scheme",
+			TokenType:      "Bearer",
+			AuthHeader:     "Authentication",
+			AuthScheme:     "none",
+			ExpectedHeader: "Authentication",
+			ExpectedValue:  "token",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		var received *http.Request
+		srv := httptest.NewServer(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				received = r
+				w.WriteHeader(http.StatusNoContent)
+			}))
+		client := NewClient(srv.URL, func(ctx context.Context) (auth.AccessToken, error) {
+			return auth.AccessToken{AccessToken: "token", TokenType: tc.TokenType}, nil
+		})
+		if tc.AuthHeader != "" {
+			client.AuthHeader = tc.AuthHeader
+		}
+		client.AuthScheme = tc.AuthScheme
+
+		// act
+		err := client.Do(context.Background(), Request{Method: http.MethodGet, Path: "/items"}, nil)
+		srv.Close()
+
+		// assert
+		assert.NoError(t, err, tc.Purpose)
+		assert.Equal(t, tc.ExpectedValue, received.Header.Get(tc.ExpectedHeader), tc.Purpose)
+		if tc.ExpectedHeader != "Authorization" {
+			assert.Empty(t, received.Header.Get("Authorization"), tc.Purpose)
+		}
+	}
+}
+
+func TestClientWithAuthHeaderInRequest(t *testing.T) {
+	// arrange
+	var received *http.Request
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			w.WriteHeader(http.StatusNoContent)
+		}))
+	defer srv.Close()
+	client := NewClient(srv.URL, func(ctx context.Context) (auth.AccessToken, error) {
+		t.Error("The token must not be fetched")
+		return auth.AccessToken{}, errors.New("no token")
+	})
+	request := Request{
+		Method: http.MethodGet,
+		Path:   "/items",
+		Header: http.Header{"authorization": {"Bearer other"}},
+	}
+
+	// act
+	err := client.Do(context.Background(), request, nil)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Bearer other"}, received.Header.Values("Authorization"))
+}
+
+func TestClientDoWithoutResponse(t *testing.T) {
+	// arrange
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+	defer srv.Close()
+	client := NewClient(srv.URL, nil)
+
+	// act
+	err := client.Do(context.Background(), Request{Method: http.MethodDelete, Path: "/items/1"}, nil)
+
+	// assert
+	assert.NoError(t, err)
+}
+
+func TestClientDoCancelled(t *testing.T) {
+	// arrange
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("{}"))
+		}))
+	defer srv.Close()
+	client := NewClient(srv.URL, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// act
+	err := client.Do(ctx, Request{Method: http.MethodGet, Path: "/items"}, nil)
+
+	// assert
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestReuseToken(t *testing.T) {
+	// arrange
+	calls := 0
+	source := ReuseToken(func(ctx context.Context) (auth.AccessToken, error) {
+		calls++
+		return auth.AccessToken{AccessToken: "token", ExpiresIn: 3600}, nil
+	})
+
+	// act
+	for i := 0; i < 3; i++ {
+		token, err := source(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "token", token.AccessToken)
+	}
+
+	// assert
+	assert.Equal(t, 1, calls, "the token must be fetched only once")
+}
+
+func TestReuseTokenFetchesExpiredTokens(t *testing.T) {
+	// arrange
+	calls := 0
+	source := ReuseToken(func(ctx context.Context) (auth.AccessToken, error) {
+		calls++
+		if calls == 1 {
+			return auth.AccessToken{}, errors.New("no token")
+		}
+		return auth.AccessToken{AccessToken: "token", ExpiresIn: 10}, nil
+	})
+
+	// act
+	_, err := source(context.Background())
+	assert.Error(t, err)
+	source(context.Background())
+	source(context.Background())
+
+	// assert
+	assert.Equal(t, 3, calls, "failed and expired tokens must be fetched again")
+}
+
+func TestReuseTokenWithoutExpiration(t *testing.T) {
+	// arrange
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte(`{"access_token": "token", "token_type": "Bearer"}`))
+	}))
+	defer srv.Close()
+
+	viper.SetConfigFile(filepath.Join(t.TempDir(), "config.yaml"))
+	config.Set(config.SecretStoreFlag, config.SecretStorePlaintext)
+	config.Set(config.TokenEndpointFlag, srv.URL)
+	config.Set(config.ClientIdFlag, "client_id")
+	assert.NoError(t, config.SetSecret(config.ClientSecretFlag, "client_secret"))
+	source := ReuseToken(auth.NewAccessToken)
+
+	// act
+	for i := 0; i < 5; i++ {
+		token, err := source(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, "token", token.AccessToken)
+	}
+
+	// assert
+	assert.Equal(t, 1, hits, "tokens without expiration must be fetched only once")
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// requestIdHeader is the header where the API returns the id of the request,
+// used when the error body does not contain it
+const requestIdHeader string = "X-Request-Id"
+
+// APIError represents a response of the API with a status code other than
+// 2xx, parsed from the error body
+type APIError struct {
+	StatusCode int      `json:"status"`
+	Message    string   `json:"message"`
+	RequestId  string   `json:"request_id,omitempty"`
+	Details    []string `json:"details,omitempty"`
+}
+
+// Error renders the error for people, like:
+// request is malformed (HTTP 400 Bad Request, request id 1234)
+func (e *APIError) Error() string {
+	builder := strings.Builder{}
+	builder.WriteString(e.Message)
+
+	builder.WriteString(fmt.Sprintf(" (HTTP %d %s", e.StatusCode, http.StatusText(e.StatusCode)))
+	if e.RequestId != "" {
+		builder.WriteString(fmt.Sprintf(", request id %s", e.RequestId))
+	}
+	builder.WriteString(")")
+
+	for _, detail := range e.Details {
+		builder.WriteString(fmt.Sprintf("\n  - %s", detail))
+	}
+	return builder.String()
+}
+
+// ClientError checks if the error was caused by the request (4xx)
+func (e *APIError) ClientError() bool {
+	return e.StatusCode >= 400 && e.StatusCode <= 499
+}
+
+// errorBody is the body returned by the API on errors
+type errorBody struct {
+	Message   string            `json:"message"`
+	RequestId string            `json:"request_id"`
+	Details   []json.RawMessage `json:"details"`
+}
+
+// DecodeError creates an APIError from an error response. The message, request
+// id and details are read from the body when it is JSON, otherwise the whole
+// body is the message.
+func DecodeError(statusCode int, header http.Header, content []byte) *APIError {
+	apiError := &APIError{
+		StatusCode: statusCode,
+		RequestId:  header.Get(requestIdHeader),
+	}
+
+	body := errorBody{}
+	err := json.Unmarshal(content, &body)
+	if err != nil {
+		apiError.Message = strings.TrimSpace(string(content))
+	} else {
+		apiError.Message = body.Message
+		if body.RequestId != "" {
+			apiError.RequestId = body.RequestId
+		}
+		for _, detail := range body.Details {
+			apiError.Details = append(apiError.Details, detailText(detail))
+		}
+	}
+
+	if apiError.Message == "" {
+		apiError.Message = "error calling the API"
+	}
+	return apiError
+}
+
+// detailText returns the text of an error detail: strings are used as they
+// are while other values are kept as JSON
+func detailText(detail json.RawMessage) string {
+	text := ""
+	if json.Unmarshal(detail, &text) == nil {
+		return text
+	}
+	return string(detail)
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeError(t *testing.T) {
+	testCases := []struct {
+		StatusCode int
+		Header     http.Header
+		Body       string
+		Expected   *APIError
+		Purpose    string
+	}{
+		{
+			StatusCode: http.StatusBadRequest,
+			Header:     http.Header{},
+			Body:       `{"message": "request is malformed"}`,
+			Expected: &APIError{
+				StatusCode: http.StatusBadRequest,
+				Message:    "request is malformed",
+			},
+			Purpose: "message only",
+		},
+		{
+			StatusCode: http.StatusBadRequest,
+			Header:     http.Header{},
+			Body: `{"message": "invalid currency", "request_id": "1234",
+				"details": ["from is invalid", {"field": "to"}]}`,
+			Expected: &APIError{
+				StatusCode: http.StatusBadRequest,
+				Message:    "invalid currency",
+				RequestId:  "1234",
+				Details:    []string{"from is invalid", `{"field": "to"}`},
+			},
+			Purpose: "request id and details",
+		},
+		{
+			StatusCode: http.StatusBadGateway,
+			Header:     http.Header{"X-Request-Id": {"5678"}},
+			Body:       "bad gateway\n",
+			Expected: &APIError{
+				StatusCode: http.StatusBadGateway,
+				Message:    "bad gateway",
+				RequestId:  "5678",
+			},
+			Purpose: "body without json and request id in the header",
+		},
+		{
+			StatusCode: http.StatusInternalServerError,
+			Header:     http.Header{},
+			Body:       "",
+			Expected: &APIError{
+				StatusCode: http.StatusInternalServerError,
+				Message:    "error calling the API",
+			},
+			Purpose: "empty body",
+		},
+	}
+
+	for _, tc := range testCases {
+		// act
+		apiError := DecodeError(tc.StatusCode, tc.Header, []byte(tc.Body))
+
+		// assert
+		assert.Equal(t, tc.Expected, apiError, "invalid error for "+tc.Purpose)
+	}
+}
+
+func TestAPIErrorError(t *testing.T) {
+	// arrange
+	apiError := &APIError{
+		StatusCode: http.StatusBadRequest,
+		Message:    "invalid currency",
+		RequestId:  "1234",
+		Details:    []string{"from is invalid"},
+	}
+
+	// act
+	text := apiError.Error()
+
+	// assert
+	assert.Equal(t,
+		"invalid currency (HTTP 400 Bad Request, request id 1234)\n  - from is invalid",
+		text)
+	assert.True(t, apiError.ClientError())
+	assert.False(t, (&APIError{StatusCode: http.StatusBadGateway}).ClientError())
+}
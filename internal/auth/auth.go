@@ -1,70 +1,274 @@
 package auth
 
 import (
-	"encoding/base64"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/retry"
 )
 
 // AccessToken represents an OAuth2 access token obtained using the client
-// credentials flow
+// credentials flow or an interactive login
 type AccessToken struct {
 	AccessToken string `json:"access_token"`
 	ExpiresIn   int    `json:"expires_in"`
 	TokenType   string `json:"token_type"`
+	Scope       string `json:"scope,omitempty"`
+
+	// RefreshToken is only returned by interactive logins
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// Credentials identify the client requesting access tokens
+type Credentials struct {
+	// Profile is the configuration profile of the credentials, keeping the
+	// cached tokens of each profile apart
+	Profile string
+
+	ClientId      string
+	ClientSecret  string
+	TokenEndpoint string
+
+	// Method is how the client authenticates, client_secret_basic if empty,
+	// using the private key with private_key_jwt or the client certificate
+	// with tls_client_auth
+	Method         string
+	PrivateKeyFile string
+	PrivateKeyId   string
+	ClientCertFile string
+	ClientKeyFile  string
+	CACertFile     string
+
+	// Scopes, Audience and Resource define the access requested for the
+	// tokens, omitted from the requests when empty
+	Scopes   []string
+	Audience string
+	Resource string
+}
+
+// scopesKey is the key of the scopes required by the command in the context
+type scopesKey struct{}
+
+// WithScopes returns a context requesting the scopes, besides the configured
+// ones, in the access tokens got with it
+func WithScopes(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, scopesKey{}, scopes)
+}
+
+// CredentialsFromConfig returns the credentials of the selected profile
+func CredentialsFromConfig() Credentials {
+	return Credentials{
+		Profile:        config.Profile(),
+		ClientId:       config.GetString(config.ClientIdFlag),
+		ClientSecret:   config.GetString(config.ClientSecretFlag),
+		TokenEndpoint:  config.GetString(config.TokenEndpointFlag),
+		Method:         config.GetString(config.AuthMethodFlag),
+		PrivateKeyFile: config.GetString(config.PrivateKeyFlag),
+		PrivateKeyId:   config.GetString(config.PrivateKeyIdFlag),
+		ClientCertFile: config.GetString(config.ClientCertFlag),
+		ClientKeyFile:  config.GetString(config.ClientKeyFlag),
+		CACertFile:     config.GetString(config.CACertFlag),
+		Scopes:         strings.Fields(config.GetString(config.ScopesFlag)),
+		Audience:       config.GetString(config.AudienceFlag),
+		Resource:       config.GetString(config.ResourceFlag),
+	}
+}
+
+// CredentialsFor returns the credentials of the selected profile, requesting
+// the scopes of the context too
+func CredentialsFor(ctx context.Context) Credentials {
+	credentials := CredentialsFromConfig()
+	scopes, _ := ctx.Value(scopesKey{}).([]string)
+	credentials.Scopes = MergeScopes(credentials.Scopes, scopes)
+	return credentials
 }
 
-// NewAccessToken fetches a new access token from the OAuth2 server
-func NewAccessToken() (AccessToken, error) {
+// MergeScopes returns the scopes of both lists, without repetitions
+func MergeScopes(scopes []string, others []string) []string {
+	merged := []string{}
+	seen := map[string]bool{}
+	for _, scope := range append(append([]string{}, scopes...), others...) {
+		if !seen[scope] {
+			seen[scope] = true
+			merged = append(merged, scope)
+		}
+	}
+	return merged
+}
+
+// addAccess adds the access requested for the tokens to the parameters of
+// a request to the OAuth2 server
+func addAccess(values url.Values, credentials Credentials) {
+	if len(credentials.Scopes) > 0 {
+		values.Set("scope", strings.Join(credentials.Scopes, " "))
+	}
+	if credentials.Audience != "" {
+		values.Set("audience", credentials.Audience)
+	}
+	if credentials.Resource != "" {
+		values.Set("resource", credentials.Resource)
+	}
+}
+
+// NewAccessToken fetches a new access token from the OAuth2 server with the
+// configured credentials and the scopes of the context, using the refresh
+// token of an interactive login when there is one and the client credentials
+// flow otherwise
+func NewAccessToken(ctx context.Context) (AccessToken, error) {
+	credentials := CredentialsFor(ctx)
+	refreshToken := config.GetString(config.RefreshTokenFlag)
+	if refreshToken == "" {
+		return RequestToken(ctx, credentials)
+	}
+
+	token, err := RefreshToken(ctx, credentials, refreshToken)
+	tokenError := &TokenError{}
+	if errors.As(err, &tokenError) && tokenError.Code == "invalid_grant" {
+		tokenError.Message = "the login expired, please log in again " +
+			"with `learning-go-cli auth login --device` or `--web`"
+		return token, tokenError
+	}
+	if err != nil {
+		return token, err
+	}
+
+	// servers rotating refresh tokens invalidate the used one
+	if token.RefreshToken != "" && token.RefreshToken != refreshToken {
+		err = config.SetSecret(config.RefreshTokenFlag, token.RefreshToken)
+		if err != nil {
+			return token, fmt.Errorf("error storing the refresh token: %w", err)
+		}
+	}
+	return token, nil
+}
+
+// RequestToken fetches a new access token from the OAuth2 server with the
+// given credentials, using the client credentials flow
+func RequestToken(ctx context.Context, credentials Credentials) (AccessToken, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	addAccess(form, credentials)
+
+	// the request can be retried as it has no side effects
 	accessToken := AccessToken{}
+	err := postForm(ctx, credentials, credentials.TokenEndpoint, form, true, &accessToken)
+	return accessToken, err
+}
+
+// RefreshToken fetches a new access token from the OAuth2 server using the
+// refresh token of an interactive login. The response can include a new
+// refresh token replacing the used one.
+func RefreshToken(ctx context.Context, credentials Credentials, refreshToken string) (AccessToken, error) {
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", refreshToken)
+	addAccess(form, credentials)
 
-	// get configurations
-	clientId := config.GetString(config.ClientIdFlag)
-	clientSecret := config.GetString(config.ClientSecretFlag)
-	tokenEndpoint := config.GetString(config.TokenEndpointFlag)
+	// retrying could reuse a refresh token already replaced by the server
+	accessToken := AccessToken{}
+	err := postForm(ctx, credentials, credentials.TokenEndpoint, form, false, &accessToken)
+	return accessToken, err
+}
 
-	// prepare request body
-	bodyContent := fmt.Sprintf(
-		"grant_type=client_credentials&client_id=%s&scope=",
-		clientId)
-	body := strings.NewReader(bodyContent)
+// postForm sends the form, with the client authentication of the
+// credentials, to an endpoint of the OAuth2 server and decodes the JSON
+// response. Error responses are returned as a *TokenError.
+func postForm(ctx context.Context, credentials Credentials, endpoint string, form url.Values, idempotent bool, response interface{}) error {
+	// prepare the body and the headers, with the client authentication
+	form.Set("client_id", credentials.ClientId)
+	header := http.Header{}
+	header.Set("Content-Type", "application/x-www-form-urlencoded")
+	err := authenticateClient(credentials, header, form, time.Now())
+	if err != nil {
+		return err
+	}
 
 	// create base request
-	request, err := http.NewRequest("POST", tokenEndpoint, body)
+	body := strings.NewReader(form.Encode())
+	request, err := http.NewRequestWithContext(ctx, "POST", endpoint, body)
 	if err != nil {
-		return accessToken, err
+		return err
 	}
-
-	// set the headers
-	clientIdAndSecret := fmt.Sprintf("%s:%s", clientId, clientSecret)
-	credentials := base64.StdEncoding.EncodeToString([]byte(clientIdAndSecret))
-	authHeader := fmt.Sprintf("Basic %s", credentials)
-	request.Header = map[string][]string{
-		"Authorization": {authHeader},
-		"Content-Type":  {"application/x-www-form-urlencoded"},
+	request.Header = header
+	if idempotent {
+		request = retry.MarkIdempotent(request)
 	}
 
 	// execute the request
-	response, err := http.DefaultClient.Do(request)
+	transport, err := tokenTransport(credentials)
 	if err != nil {
-		return accessToken, err
+		return err
+	}
+	client := &http.Client{
+		Transport: retry.NewTransport(transport, retry.PolicyFromConfig()),
+		Timeout:   config.GetDuration(config.TimeoutFlag),
+	}
+	httpResponse, err := client.Do(request)
+	if err != nil {
+		return err
 	}
 
 	// read and unmarshal the body
-	responseContent, err := ioutil.ReadAll(response.Body)
+	responseContent, err := ioutil.ReadAll(httpResponse.Body)
 	if err != nil {
-		return accessToken, err
+		return err
 	}
-	defer response.Body.Close()
-	if response.StatusCode != 200 {
-		return accessToken, fmt.Errorf("error getting token: %s", responseContent)
+	defer httpResponse.Body.Close()
+	if httpResponse.StatusCode != 200 {
+		return decodeTokenError(httpResponse.StatusCode, responseContent)
+	}
+	err = json.Unmarshal(responseContent, response)
+	if err != nil {
+		return &TokenError{
+			StatusCode: httpResponse.StatusCode,
+			Message:    fmt.Sprintf("invalid token response: %s", err),
+		}
 	}
-	err = json.Unmarshal(responseContent, &accessToken)
 
-	return accessToken, err
+	return nil
+}
+
+// TokenError represents a failure of the OAuth2 server to issue a token,
+// usually caused by invalid credentials
+type TokenError struct {
+	StatusCode int
+	Message    string
+
+	// Code is the error code defined in RFC 6749, like invalid_grant
+	Code string
+}
+
+// Error renders the error for people
+func (e *TokenError) Error() string {
+	return fmt.Sprintf("error getting token: %s (HTTP %d %s)",
+		e.Message,
+		e.StatusCode,
+		http.StatusText(e.StatusCode))
+}
+
+// decodeTokenError creates a TokenError from an error response of the OAuth2
+// server, using the error description defined in RFC 6749 when available
+func decodeTokenError(statusCode int, content []byte) *TokenError {
+	body := struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}{}
+	message := strings.TrimSpace(string(content))
+	if json.Unmarshal(content, &body) == nil {
+		switch {
+		case body.ErrorDescription != "":
+			message = body.ErrorDescription
+		case body.Error != "":
+			message = body.Error
+		}
+	}
+	return &TokenError{StatusCode: statusCode, Message: message, Code: body.Error}
 }
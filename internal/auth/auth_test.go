@@ -1,12 +1,15 @@
 package auth
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"testing"
 
 	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -69,7 +72,7 @@ func TestNewAccessToken(t *testing.T) {
 		config.Set(config.TokenEndpointFlag, srv.URL)
 
 		// act
-		token, err := NewAccessToken()
+		token, err := NewAccessToken(context.Background())
 
 		// assert
 		if tc.ErrorNil {
@@ -80,3 +83,113 @@ func TestNewAccessToken(t *testing.T) {
 		assert.Equal(t, tc.Token, token, "invalid token for "+tc.Purpose)
 	}
 }
+
+func TestNewAccessTokenWithRefreshToken(t *testing.T) {
+	testCases := []struct {
+		Purpose              string
+		StatusCode           int
+		Body                 string
+		ExpectedRefreshToken string
+		Error                string
+	}{
+		{
+			Purpose:              "refresh token rotated",
+			StatusCode:           http.StatusOK,
+			Body:                 `{"access_token": "token", "expires_in": 3600, "refresh_token": "new_refresh_token"}`,
+			ExpectedRefreshToken: "new_refresh_token",
+		},
+		{
+			Purpose:              "refresh token kept",
+			StatusCode:           http.StatusOK,
+			Body:                 `{"access_token": "token", "expires_in": 3600}`,
+			ExpectedRefreshToken: "refresh_token",
+		},
+		{
+			Purpose:              "login expired",
+			StatusCode:           http.StatusBadRequest,
+			Body:                 `{"error": "invalid_grant"}`,
+			ExpectedRefreshToken: "refresh_token",
+			Error: "error getting token: the login expired, please log in again " +
+				"with `learning-go-cli auth login --device` or `--web` (HTTP 400 Bad Request)",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		srv := httptest.NewServer(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "refresh_token", r.FormValue("grant_type"), tc.Purpose)
+				assert.Equal(t, "refresh_token", r.FormValue("refresh_token"), tc.Purpose)
+				w.WriteHeader(tc.StatusCode)
+				w.Write([]byte(tc.Body))
+			}))
+
+		viper.SetConfigFile(filepath.Join(t.TempDir(), "config.yaml"))
+		config.Set(config.SecretStoreFlag, config.SecretStorePlaintext)
+		config.Set(config.TokenEndpointFlag, srv.URL)
+		config.Set(config.AuthMethodFlag, config.AuthMethodNone)
+		assert.NoError(t, config.SetSecret(config.RefreshTokenFlag, "refresh_token"))
+
+		// act
+		token, err := NewAccessToken(context.Background())
+		srv.Close()
+
+		// assert
+		if tc.Error != "" {
+			assert.EqualError(t, err, tc.Error, tc.Purpose)
+		} else {
+			assert.NoError(t, err, tc.Purpose)
+			assert.Equal(t, "token", token.AccessToken, tc.Purpose)
+		}
+		assert.Equal(t, tc.ExpectedRefreshToken, config.GetString(config.RefreshTokenFlag), tc.Purpose)
+		viper.Reset()
+	}
+}
+
+func TestDecodeTokenError(t *testing.T) {
+	testCases := []struct {
+		Body     string
+		Expected string
+		Code     string
+		Purpose  string
+	}{
+		{
+			Body:     `{"error": "invalid_client", "error_description": "client authentication failed"}`,
+			Expected: "client authentication failed",
+			Code:     "invalid_client",
+			Purpose:  "error with description",
+		},
+		{
+			Body:     `{"error": "invalid_client"}`,
+			Expected: "invalid_client",
+			Code:     "invalid_client",
+			Purpose:  "error without description",
+		},
+		{
+			Body:     "Unauthorized\n",
+			Expected: "Unauthorized",
+			Purpose:  "body without json",
+		},
+	}
+
+	for _, tc := range testCases {
+		// act
+		tokenError := decodeTokenError(http.StatusUnauthorized, []byte(tc.Body))
+
+		// assert
+		assert.Equal(t, http.StatusUnauthorized, tokenError.StatusCode, "invalid status for "+tc.Purpose)
+		assert.Equal(t, tc.Expected, tokenError.Message, "invalid message for "+tc.Purpose)
+		assert.Equal(t, tc.Code, tokenError.Code, "invalid code for "+tc.Purpose)
+	}
+}
+
+func TestTokenErrorError(t *testing.T) {
+	// arrange
+	tokenError := &TokenError{StatusCode: http.StatusUnauthorized, Message: "invalid_client"}
+
+	// act
+	text := tokenError.Error()
+
+	// assert
+	assert.Equal(t, "error getting token: invalid_client (HTTP 401 Unauthorized)", text)
+}
@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// callbackPath is the path of the loopback redirect URI
+const callbackPath string = "/callback"
+
+// callbackPage is shown in the browser once the login is received
+const callbackPage string = `<html><body><p>%s</p><p>You can close this window and return to the terminal.</p></body></html>`
+
+// LoginWithBrowser gets an access token for a user with the authorization
+// code flow and PKCE (RFC 7636). The user logs in with the browser, opened
+// with the open function, and the authorization code is received by a
+// listener in the loopback interface, on the given port or a random one if 0.
+func LoginWithBrowser(
+	ctx context.Context,
+	credentials Credentials,
+	authorizationEndpoint string,
+	port int,
+	open func(authorizationURL string) error) (AccessToken, error) {
+
+	verifier, err := randomString()
+	if err != nil {
+		return AccessToken{}, err
+	}
+	state, err := randomString()
+	if err != nil {
+		return AccessToken{}, err
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		return AccessToken{}, fmt.Errorf("error listening for the login: %w", err)
+	}
+	defer listener.Close()
+	redirectURI := fmt.Sprintf("http://%s%s", listener.Addr().String(), callbackPath)
+
+	authorizationURL, err := url.Parse(authorizationEndpoint)
+	if err != nil {
+		return AccessToken{}, fmt.Errorf("invalid authorization endpoint: %w", err)
+	}
+	query := authorizationURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", credentials.ClientId)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("state", state)
+	query.Set("code_challenge", codeChallenge(verifier))
+	query.Set("code_challenge_method", "S256")
+	addAccess(query, credentials)
+	authorizationURL.RawQuery = query.Encode()
+
+	codes := make(chan string, 1)
+	failures := make(chan error, 1)
+	server := &http.Server{Handler: callbackHandler(state, codes, failures)}
+	go server.Serve(listener)
+	defer server.Close()
+
+	err = open(authorizationURL.String())
+	if err != nil {
+		return AccessToken{}, err
+	}
+
+	var code string
+	select {
+	case <-ctx.Done():
+		return AccessToken{}, ctx.Err()
+	case err := <-failures:
+		return AccessToken{}, err
+	case code = <-codes:
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURI)
+	form.Set("code_verifier", verifier)
+
+	// codes can only be used once so the request is never retried
+	token := AccessToken{}
+	err = postForm(ctx, credentials, credentials.TokenEndpoint, form, false, &token)
+	return token, err
+}
+
+// callbackHandler receives the redirect of the browser with the
+// authorization code, ignoring other requests like the ones for icons
+func callbackHandler(state string, codes chan<- string, failures chan<- error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != callbackPath {
+			http.NotFound(w, r)
+			return
+		}
+
+		query := r.URL.Query()
+		var failure error
+		switch {
+		case query.Get("state") != state:
+			// a request not started by this login, which must be ignored
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, callbackPage, "The login is invalid, please try again.")
+			return
+		case query.Get("error") != "":
+			message := query.Get("error_description")
+			if message == "" {
+				message = query.Get("error")
+			}
+			failure = fmt.Errorf("the login failed: %s", message)
+		case query.Get("code") == "":
+			failure = errors.New("the login failed: no authorization code was received")
+		}
+
+		if failure != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, callbackPage, "The login failed.")
+			select {
+			case failures <- failure:
+			default:
+			}
+			return
+		}
+
+		fmt.Fprintf(w, callbackPage, "You are logged in.")
+		select {
+		case codes <- query.Get("code"):
+		default:
+		}
+	})
+}
+
+// randomString returns a random string with 256 bits, safe to use in URLs
+func randomString() (string, error) {
+	content := make([]byte, 32)
+	_, err := rand.Read(content)
+	if err != nil {
+		return "", fmt.Errorf("error creating a random value: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(content), nil
+}
+
+// codeChallenge returns the S256 code challenge of the code verifier
+func codeChallenge(verifier string) string {
+	digest := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// newCodeServer creates a token server exchanging the code, checking the
+// code verifier matches the challenge sent to the authorization endpoint
+func newCodeServer(t *testing.T, challenge *string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "authorization_code", r.FormValue("grant_type"))
+		assert.Equal(t, "code", r.FormValue("code"))
+		assert.Equal(t, *challenge, codeChallenge(r.FormValue("code_verifier")))
+		json.NewEncoder(w).Encode(AccessToken{
+			AccessToken:  "token",
+			RefreshToken: "refresh_token",
+			ExpiresIn:    3600,
+		})
+	}))
+}
+
+// browse simulates the browser logging in, redirecting to the redirect URI
+// with the query parameters returned by the redirect function
+func browse(t *testing.T, challenge *string, redirect func(authorization url.Values) url.Values) func(string) error {
+	return func(authorizationURL string) error {
+		parsed, err := url.Parse(authorizationURL)
+		assert.NoError(t, err)
+		authorization := parsed.Query()
+		assert.Equal(t, "code", authorization.Get("response_type"))
+		assert.Equal(t, "client_id", authorization.Get("client_id"))
+		assert.Equal(t, "S256", authorization.Get("code_challenge_method"))
+		*challenge = authorization.Get("code_challenge")
+
+		// the browser also asks for other resources, which are ignored
+		http.Get(authorization.Get("redirect_uri") + "/../favicon.ico")
+
+		go func() {
+			response, err := http.Get(authorization.Get("redirect_uri") + "?" + redirect(authorization).Encode())
+			if err == nil {
+				response.Body.Close()
+			}
+		}()
+		return nil
+	}
+}
+
+func TestLoginWithBrowser(t *testing.T) {
+	// arrange
+	challenge := ""
+	srv := newCodeServer(t, &challenge)
+	defer srv.Close()
+	credentials := Credentials{
+		ClientId:      "client_id",
+		TokenEndpoint: srv.URL,
+		Method:        config.AuthMethodNone,
+	}
+	open := browse(t, &challenge, func(authorization url.Values) url.Values {
+		return url.Values{"code": {"code"}, "state": {authorization.Get("state")}}
+	})
+
+	// act
+	token, err := LoginWithBrowser(context.Background(), credentials, "https://auth.example.com/authorize?audience=api", 0, open)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "token", token.AccessToken)
+	assert.Equal(t, "refresh_token", token.RefreshToken)
+}
+
+func TestLoginWithBrowserFailures(t *testing.T) {
+	testCases := []struct {
+		Purpose  string
+		Redirect func(authorization url.Values) url.Values
+		Open     error
+		Error    string
+	}{
+		{
+			Purpose: "login denied",
+			Redirect: func(authorization url.Values) url.Values {
+				return url.Values{
+					"error":             {"access_denied"},
+					"error_description": {"the user denied the access"},
+					"state":             {authorization.Get("state")},
+				}
+			},
+			Error: "the login failed: the user denied the access",
+		},
+		{
+			Purpose: "missing code",
+			Redirect: func(authorization url.Values) url.Values {
+				return url.Values{"state": {authorization.Get("state")}}
+			},
+			Error: "the login failed: no authorization code was received",
+		},
+		{
+			Purpose: "browser not opened",
+			Open:    errors.New("no browser"),
+			Error:   "no browser",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		challenge := ""
+		credentials := Credentials{ClientId: "client_id", Method: config.AuthMethodNone}
+		open := func(authorizationURL string) error { return tc.Open }
+		if tc.Redirect != nil {
+			open = browse(t, &challenge, tc.Redirect)
+		}
+
+		// act
+		_, err := LoginWithBrowser(context.Background(), credentials, "https://auth.example.com/authorize", 0, open)
+
+		// assert
+		assert.EqualError(t, err, tc.Error, tc.Purpose)
+	}
+}
+
+func TestLoginWithBrowserIgnoresOtherStates(t *testing.T) {
+	// arrange
+	challenge := ""
+	credentials := Credentials{ClientId: "client_id", Method: config.AuthMethodNone}
+	ctx, cancel := context.WithCancel(context.Background())
+	open := browse(t, &challenge, func(authorization url.Values) url.Values {
+		defer cancel()
+		return url.Values{"code": {"code"}, "state": {"forged"}}
+	})
+
+	// act
+	_, err := LoginWithBrowser(ctx, credentials, "https://auth.example.com/authorize", 0, open)
+
+	// assert
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCodeChallenge(t *testing.T) {
+	// the base64url SHA-256 digest of the verifier, without padding
+	assert.Equal(t,
+		"PO37Out-BLTN_47Y9Ws9dhssbRwK-Gn1k7pASfUMHyU",
+		codeChallenge("dBjftJeZ4CVP-mB92K1uhbMXXs03c9G4QcUJ4Un0EXQ"))
+}
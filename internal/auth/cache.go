@@ -0,0 +1,297 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/spf13/cast"
+)
+
+// DefaultExpirySkew is the safety margin used when the configuration does not
+// define one: tokens expiring within this margin are considered expired
+const DefaultExpirySkew time.Duration = 30 * time.Second
+
+// now returns the current time, replaced in tests to control expiration
+var now = time.Now
+
+// CachedToken represents an access token stored in the token cache together
+// with the moment it was issued
+type CachedToken struct {
+	AccessToken
+	IssuedAt time.Time `json:"issued_at"`
+}
+
+// ExpiresAt returns the moment the token expires
+func (t CachedToken) ExpiresAt() time.Time {
+	return t.IssuedAt.Add(time.Duration(t.ExpiresIn) * time.Second)
+}
+
+// Expired checks if the token is expired at the given moment, considering
+// it expired if it expires within the skew. Tokens without an expiration are
+// always considered expired as there is no way to know if they are valid.
+func (t CachedToken) Expired(at time.Time, skew time.Duration) bool {
+	if t.ExpiresIn <= 0 {
+		return true
+	}
+	return !at.Add(skew).Before(t.ExpiresAt())
+}
+
+// TokenCache stores access tokens in a JSON file so they can be reused across
+// invocations of the CLI
+type TokenCache struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// NewTokenCache creates a token cache persisted in the file with the given path
+func NewTokenCache(path string) *TokenCache {
+	return &TokenCache{Path: path}
+}
+
+// Grants getting new access tokens, cached apart
+const (
+	GrantClientCredentials string = "client_credentials"
+	GrantRefreshToken      string = "refresh_token"
+)
+
+// CacheKey returns the key identifying the tokens of a client in the cache.
+// Tokens of other profiles, authentication methods, grants, scopes, audience
+// or resource are cached apart.
+func CacheKey(credentials Credentials, grant string) string {
+	scopes := append([]string{}, credentials.Scopes...)
+	sort.Strings(scopes)
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%s",
+		clientCacheKey(credentials),
+		credentials.Method,
+		grant,
+		strings.Join(scopes, " "),
+		credentials.Audience,
+		credentials.Resource)
+}
+
+// clientCacheKey returns the beginning of the keys of all the tokens of a
+// client of a profile in the cache
+func clientCacheKey(credentials Credentials) string {
+	return fmt.Sprintf("%s|%s|%s",
+		credentials.Profile,
+		credentials.ClientId,
+		credentials.TokenEndpoint)
+}
+
+// cacheKeyFor returns the key of the tokens of the selected profile with the
+// scopes of the context, used both to store and to look up tokens. Tokens of
+// interactive logins, refreshed with the refresh token, are kept apart from
+// the ones of the client credentials.
+func cacheKeyFor(ctx context.Context) string {
+	grant := GrantClientCredentials
+	if config.GetString(config.RefreshTokenFlag) != "" {
+		grant = GrantRefreshToken
+	}
+	return CacheKey(CredentialsFor(ctx), grant)
+}
+
+// Get returns the token stored for the key, if any
+func (c *TokenCache) Get(key string) (CachedToken, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tokens, err := c.read()
+	if err != nil {
+		return CachedToken{}, false, err
+	}
+	token, found := tokens[key]
+	return token, found, nil
+}
+
+// Put stores the token for the key, replacing any existing one
+func (c *TokenCache) Put(key string, token CachedToken) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tokens, err := c.read()
+	if err != nil {
+		return err
+	}
+	tokens[key] = token
+	return c.write(tokens)
+}
+
+// Delete removes the token stored for the key
+func (c *TokenCache) Delete(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tokens, err := c.read()
+	if err != nil {
+		return err
+	}
+	delete(tokens, key)
+	return c.write(tokens)
+}
+
+// DeleteClient removes all the tokens of the client of the credentials,
+// whatever their scopes
+func (c *TokenCache) DeleteClient(credentials Credentials) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tokens, err := c.read()
+	if err != nil {
+		return err
+	}
+	prefix := clientCacheKey(credentials)
+	for key := range tokens {
+		if key == prefix || strings.HasPrefix(key, prefix+"|") {
+			delete(tokens, key)
+		}
+	}
+	return c.write(tokens)
+}
+
+// read loads all the tokens from the cache file. A missing file is an empty
+// cache, and so is a file that cannot be parsed, which is overwritten by the
+// next write instead of disabling the cache for good.
+func (c *TokenCache) read() (map[string]CachedToken, error) {
+	tokens := map[string]CachedToken{}
+
+	content, err := ioutil.ReadFile(c.Path)
+	if os.IsNotExist(err) {
+		return tokens, nil
+	}
+	if err != nil {
+		return tokens, fmt.Errorf("error reading the token cache: %w", err)
+	}
+
+	err = json.Unmarshal(content, &tokens)
+	if err != nil {
+		return map[string]CachedToken{}, nil
+	}
+	return tokens, nil
+}
+
+// write persists all the tokens in the cache file, readable only by the
+// current user as it contains credentials. The tokens are written to a
+// temporary file renamed over the cache file, so CLIs running in parallel
+// never read a half-written file.
+func (c *TokenCache) write(tokens map[string]CachedToken) error {
+	content, err := json.Marshal(tokens)
+	if err != nil {
+		return fmt.Errorf("error serializing the token cache: %w", err)
+	}
+
+	file, err := ioutil.TempFile(filepath.Dir(c.Path), filepath.Base(c.Path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error writing the token cache: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), c.Path)
+	}
+	if err != nil {
+		return fmt.Errorf("error writing the token cache: %w", err)
+	}
+	return nil
+}
+
+// GetAccessToken returns an access token, reusing the cached one if it is
+// not expired and fetching and caching a new one otherwise.
+// Failures reading or writing the cache are ignored as the cache is only an
+// optimization.
+func GetAccessToken(ctx context.Context) (AccessToken, error) {
+	cached, err := GetCachedToken(ctx)
+	return cached.AccessToken, err
+}
+
+// GetCachedToken works like GetAccessToken but also returns the moment the
+// token was issued, to know when it expires
+func GetCachedToken(ctx context.Context) (CachedToken, error) {
+	cacheFile, err := config.TokenCacheFile()
+	if err != nil {
+		issuedAt := now()
+		token, err := NewAccessToken(ctx)
+		return CachedToken{AccessToken: token, IssuedAt: issuedAt}, err
+	}
+	cache := NewTokenCache(cacheFile)
+	key := cacheKeyFor(ctx)
+	skew := ExpirySkew()
+
+	cached, found, err := cache.Get(key)
+	if err == nil && found && !cached.Expired(now(), skew) {
+		return cached, nil
+	}
+
+	issuedAt := now()
+	token, err := NewAccessToken(ctx)
+	if err != nil {
+		return CachedToken{}, err
+	}
+
+	// refresh tokens are only kept in the secret store
+	cached = CachedToken{AccessToken: token, IssuedAt: issuedAt}
+	cached.RefreshToken = ""
+	if !cached.Expired(issuedAt, skew) {
+		cache.Put(key, cached)
+	}
+
+	return cached, nil
+}
+
+// ExpirySkew returns the margin before expiration to consider tokens
+// expired, from the token-expiry-skew setting. An explicit 0 disables the
+// margin while invalid values use the default one.
+func ExpirySkew() time.Duration {
+	value, _ := config.Lookup(config.TokenSkewFlag)
+	skew, err := cast.ToDurationE(value)
+	if err != nil || skew < 0 {
+		return DefaultExpirySkew
+	}
+	return skew
+}
+
+// StoreLogin stores the tokens of an interactive login for the selected
+// profile: the access token in the token cache, for the scopes of the
+// context, and the refresh token, used to get new access tokens once it
+// expires, in the secret store
+func StoreLogin(ctx context.Context, token AccessToken, issuedAt time.Time) error {
+	var err error
+	if token.RefreshToken != "" {
+		err = config.SetSecret(config.RefreshTokenFlag, token.RefreshToken)
+	} else {
+		err = config.DeleteSecret(config.RefreshTokenFlag)
+	}
+	if err != nil {
+		return fmt.Errorf("error storing the refresh token: %w", err)
+	}
+
+	cacheFile, err := config.TokenCacheFile()
+	if err != nil {
+		return err
+	}
+	cached := CachedToken{AccessToken: token, IssuedAt: issuedAt}
+	cached.RefreshToken = ""
+	return NewTokenCache(cacheFile).Put(cacheKeyFor(ctx), cached)
+}
+
+// RemoveCachedToken removes the cached tokens of the configured client, for
+// all the scopes, so the next call fetches a new one
+func RemoveCachedToken() error {
+	cacheFile, err := config.TokenCacheFile()
+	if err != nil {
+		return err
+	}
+	return NewTokenCache(cacheFile).DeleteClient(CredentialsFromConfig())
+}
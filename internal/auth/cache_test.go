@@ -0,0 +1,401 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedTokenExpired(t *testing.T) {
+	issuedAt := time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		ExpiresIn int
+		At        time.Time
+		Expired   bool
+		Purpose   string
+	}{
+		{
+			ExpiresIn: 3600,
+			At:        issuedAt.Add(10 * time.Minute),
+			Expired:   false,
+			Purpose:   "token still valid",
+		},
+		{
+			ExpiresIn: 3600,
+			At:        issuedAt.Add(2 * time.Hour),
+			Expired:   true,
+			Purpose:   "token expired",
+		},
+		{
+			ExpiresIn: 3600,
+			At:        issuedAt.Add(time.Hour - 10*time.Second),
+			Expired:   true,
+			Purpose:   "token expiring within the skew",
+		},
+		{
+			ExpiresIn: 0,
+			At:        issuedAt,
+			Expired:   true,
+			Purpose:   "token without expiration",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		token := CachedToken{
+			AccessToken: AccessToken{ExpiresIn: tc.ExpiresIn},
+			IssuedAt:    issuedAt,
+		}
+
+		// act
+		expired := token.Expired(tc.At, DefaultExpirySkew)
+
+		// assert
+		assert.Equal(t, tc.Expired, expired, "invalid expiration for "+tc.Purpose)
+	}
+}
+
+func TestTokenCache(t *testing.T) {
+	// arrange
+	cache := NewTokenCache(filepath.Join(t.TempDir(), "tokens.json"))
+	token := CachedToken{
+		AccessToken: AccessToken{AccessToken: "token", ExpiresIn: 100},
+		IssuedAt:    time.Now().UTC().Truncate(time.Second),
+	}
+
+	// act & assert
+	_, found, err := cache.Get("key")
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	err = cache.Put("key", token)
+	assert.NoError(t, err)
+
+	cached, found, err := cache.Get("key")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, token, cached)
+
+	info, err := os.Stat(cache.Path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	err = cache.Delete("key")
+	assert.NoError(t, err)
+	_, found, _ = cache.Get("key")
+	assert.False(t, found)
+}
+
+func TestCacheKey(t *testing.T) {
+	// arrange
+	client := Credentials{
+		Profile:       "default",
+		ClientId:      "client",
+		TokenEndpoint: "https://auth.example.com/token",
+		Method:        "client_secret_basic",
+	}
+	scoped := client
+	scoped.Scopes = []string{"write", "read"}
+	reordered := client
+	reordered.Scopes = []string{"read", "write"}
+	audience := client
+	audience.Audience = "api"
+	staging := client
+	staging.Profile = "staging"
+	privateKey := client
+	privateKey.Method = "private_key_jwt"
+
+	// act & assert
+	assert.Equal(t, "default|client|https://auth.example.com/token|client_secret_basic|client_credentials|||",
+		CacheKey(client, GrantClientCredentials))
+	assert.Equal(t, "default|client|https://auth.example.com/token|client_secret_basic|client_credentials|read write||",
+		CacheKey(scoped, GrantClientCredentials))
+	assert.Equal(t, CacheKey(scoped, GrantClientCredentials), CacheKey(reordered, GrantClientCredentials),
+		"The order of the scopes must not matter")
+	assert.Equal(t, "default|client|https://auth.example.com/token|client_secret_basic|client_credentials||api|",
+		CacheKey(audience, GrantClientCredentials))
+	assert.NotEqual(t, CacheKey(client, GrantClientCredentials), CacheKey(staging, GrantClientCredentials),
+		"The tokens of other profiles must be cached apart")
+	assert.NotEqual(t, CacheKey(client, GrantClientCredentials), CacheKey(privateKey, GrantClientCredentials),
+		"The tokens of other authentication methods must be cached apart")
+	assert.NotEqual(t, CacheKey(client, GrantClientCredentials), CacheKey(client, GrantRefreshToken),
+		"The tokens of interactive logins must be cached apart")
+}
+
+func TestTokenCacheDeleteClient(t *testing.T) {
+	// arrange
+	cache := NewTokenCache(filepath.Join(t.TempDir(), "tokens.json"))
+	client := Credentials{Profile: "default", ClientId: "client", TokenEndpoint: "https://auth.example.com/token"}
+	scoped := client
+	scoped.Scopes = []string{"read"}
+	other := Credentials{Profile: "default", ClientId: "client", TokenEndpoint: "https://auth.example.com/token2"}
+	staging := client
+	staging.Profile = "staging"
+	for _, credentials := range []Credentials{client, scoped, other, staging} {
+		err := cache.Put(CacheKey(credentials, GrantClientCredentials), CachedToken{})
+		assert.NoError(t, err)
+	}
+	err := cache.Put(CacheKey(client, GrantRefreshToken), CachedToken{})
+	assert.NoError(t, err)
+
+	// act
+	err = cache.DeleteClient(client)
+
+	// assert
+	assert.NoError(t, err)
+	_, found, _ := cache.Get(CacheKey(client, GrantClientCredentials))
+	assert.False(t, found)
+	_, found, _ = cache.Get(CacheKey(scoped, GrantClientCredentials))
+	assert.False(t, found, "The tokens with scopes must be removed")
+	_, found, _ = cache.Get(CacheKey(client, GrantRefreshToken))
+	assert.False(t, found, "The tokens of interactive logins must be removed")
+	_, found, _ = cache.Get(CacheKey(other, GrantClientCredentials))
+	assert.True(t, found, "The tokens of other clients must be kept")
+	_, found, _ = cache.Get(CacheKey(staging, GrantClientCredentials))
+	assert.True(t, found, "The tokens of other profiles must be kept")
+}
+
+func TestGetAccessTokenWithScopes(t *testing.T) {
+	// arrange
+	requestedScopes := []string{}
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestedScopes = append(requestedScopes, r.FormValue("scope"))
+			assert.Equal(t, "api", r.FormValue("audience"))
+			body, _ := json.Marshal(AccessToken{AccessToken: "token", ExpiresIn: 3600})
+			w.Write(body)
+		}))
+	defer srv.Close()
+
+	viper.SetConfigFile(filepath.Join(t.TempDir(), "config.yaml"))
+	defer viper.Reset()
+	config.Set(config.TokenEndpointFlag, srv.URL)
+	config.Set(config.ScopesFlag, "read")
+	config.Set(config.AudienceFlag, "api")
+
+	// act
+	_, err := GetAccessToken(context.Background())
+	assert.NoError(t, err)
+	_, err = GetAccessToken(WithScopes(context.Background(), []string{"read", "write"}))
+	assert.NoError(t, err)
+	_, err = GetAccessToken(WithScopes(context.Background(), []string{"write"}))
+	assert.NoError(t, err)
+
+	// assert
+	assert.Equal(t, []string{"read", "read write"}, requestedScopes,
+		"Tokens must be requested and cached per scope set")
+}
+
+func TestTokenCacheInvalidFile(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	os.WriteFile(path, []byte(`{"key": {"access_token": "tok`), 0600)
+	cache := NewTokenCache(path)
+
+	// act
+	_, found, err := cache.Get("key")
+	assert.NoError(t, err, "A corrupted cache must be read as an empty one")
+	assert.False(t, found)
+	err = cache.Put("key", CachedToken{AccessToken: AccessToken{AccessToken: "token"}})
+
+	// assert
+	assert.NoError(t, err, "A corrupted cache must be overwritten")
+	token, found, err := cache.Get("key")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "token", token.AccessToken.AccessToken)
+	files, _ := filepath.Glob(filepath.Join(filepath.Dir(path), "*"))
+	assert.Equal(t, []string{path}, files, "No temporary files must be left")
+	info, _ := os.Stat(path)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestGetAccessToken(t *testing.T) {
+	// arrange
+	calls := 0
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			body, _ := json.Marshal(AccessToken{
+				AccessToken: "token",
+				ExpiresIn:   3600,
+				TokenType:   "Bearer",
+			})
+			w.Write(body)
+		}))
+	defer srv.Close()
+
+	viper.SetConfigFile(filepath.Join(t.TempDir(), "config.yaml"))
+	defer viper.Reset()
+	config.Set(config.TokenEndpointFlag, srv.URL)
+
+	currentTime := time.Now()
+	now = func() time.Time { return currentTime }
+	defer func() { now = time.Now }()
+
+	// act & assert
+	token, err := GetAccessToken(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "token", token.AccessToken)
+	assert.Equal(t, 1, calls, "first call must fetch a new token")
+
+	_, err = GetAccessToken(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls, "second call must reuse the cached token")
+
+	currentTime = currentTime.Add(2 * time.Hour)
+	_, err = GetAccessToken(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls, "expired tokens must be fetched again")
+}
+
+func TestGetCachedToken(t *testing.T) {
+	// arrange
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := json.Marshal(AccessToken{AccessToken: "token", ExpiresIn: 3600})
+			w.Write(body)
+		}))
+	defer srv.Close()
+
+	viper.SetConfigFile(filepath.Join(t.TempDir(), "config.yaml"))
+	defer viper.Reset()
+	config.Set(config.TokenEndpointFlag, srv.URL)
+
+	issuedAt := time.Now().Truncate(time.Second)
+	currentTime := issuedAt
+	now = func() time.Time { return currentTime }
+	defer func() { now = time.Now }()
+
+	// act
+	_, err := GetCachedToken(context.Background())
+	assert.NoError(t, err)
+	currentTime = currentTime.Add(time.Minute)
+	cached, err := GetCachedToken(context.Background())
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "token", cached.AccessToken.AccessToken)
+	assert.True(t, issuedAt.Equal(cached.IssuedAt),
+		"the cached token must keep the moment it was issued")
+	assert.True(t, issuedAt.Add(time.Hour).Equal(cached.ExpiresAt()))
+}
+
+func TestExpirySkew(t *testing.T) {
+	// arrange
+	viper.SetConfigFile(filepath.Join(t.TempDir(), "config.yaml"))
+	defer viper.Reset()
+
+	// act & assert
+	assert.Equal(t, DefaultExpirySkew, ExpirySkew())
+	config.Set(config.TokenSkewFlag, "2m")
+	assert.Equal(t, 2*time.Minute, ExpirySkew())
+	config.Set(config.TokenSkewFlag, "0s")
+	assert.Equal(t, time.Duration(0), ExpirySkew(), "An explicit 0 must disable the margin")
+	config.Set(config.TokenSkewFlag, "0")
+	assert.Equal(t, time.Duration(0), ExpirySkew(), "An explicit 0 must disable the margin")
+	config.Set(config.TokenSkewFlag, "soon")
+	assert.Equal(t, DefaultExpirySkew, ExpirySkew(), "Invalid values must use the default")
+}
+
+func TestRemoveCachedToken(t *testing.T) {
+	// arrange
+	calls := 0
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			body, _ := json.Marshal(AccessToken{
+				AccessToken: "token",
+				ExpiresIn:   3600,
+				TokenType:   "Bearer",
+			})
+			w.Write(body)
+		}))
+	defer srv.Close()
+
+	viper.SetConfigFile(filepath.Join(t.TempDir(), "config.yaml"))
+	defer viper.Reset()
+	config.Set(config.TokenEndpointFlag, srv.URL)
+
+	_, err := GetAccessToken(context.Background())
+	assert.NoError(t, err)
+
+	// act
+	err = RemoveCachedToken()
+
+	// assert
+	assert.NoError(t, err)
+	_, err = GetAccessToken(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls, "removed tokens must be fetched again")
+}
+
+func TestStoreLogin(t *testing.T) {
+	// arrange
+	viper.SetConfigFile(filepath.Join(t.TempDir(), "config.yaml"))
+	defer viper.Reset()
+	config.Set(config.SecretStoreFlag, config.SecretStorePlaintext)
+	config.Set(config.TokenEndpointFlag, "https://auth.example.com/token")
+	token := AccessToken{AccessToken: "token", ExpiresIn: 3600, RefreshToken: "refresh_token"}
+
+	// act
+	err := StoreLogin(context.Background(), token, time.Now())
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "refresh_token", config.GetString(config.RefreshTokenFlag))
+	cached, err := GetAccessToken(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "token", cached.AccessToken, "The access token must be cached")
+	assert.Empty(t, cached.RefreshToken, "The refresh token must not be cached")
+
+	// act
+	err = StoreLogin(context.Background(), AccessToken{AccessToken: "token", ExpiresIn: 3600}, time.Now())
+
+	// assert
+	assert.NoError(t, err)
+	assert.Empty(t, config.GetString(config.RefreshTokenFlag), "Old refresh tokens must be removed")
+}
+
+func TestStoreLoginWithCommandScopes(t *testing.T) {
+	// arrange
+	calls := 0
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			body, _ := json.Marshal(AccessToken{AccessToken: "client_token", ExpiresIn: 3600})
+			w.Write(body)
+		}))
+	defer srv.Close()
+
+	viper.SetConfigFile(filepath.Join(t.TempDir(), "config.yaml"))
+	defer viper.Reset()
+	config.Set(config.SecretStoreFlag, config.SecretStorePlaintext)
+	config.Set(config.TokenEndpointFlag, srv.URL)
+	config.Set(config.ScopesFlag, "read")
+	ctx := WithScopes(context.Background(), []string{"write"})
+	_, err := GetAccessToken(ctx)
+	assert.NoError(t, err)
+	token := AccessToken{AccessToken: "user_token", ExpiresIn: 3600, RefreshToken: "refresh_token"}
+
+	// act
+	err = StoreLogin(ctx, token, time.Now())
+
+	// assert
+	assert.NoError(t, err)
+	cached, err := GetAccessToken(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "user_token", cached.AccessToken,
+		"The token of the login must be found with the same scopes")
+	assert.Equal(t, 1, calls, "The token of the login must not be fetched again")
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/jwt"
+)
+
+// clientAssertionType is the type of the client assertions sent with
+// private_key_jwt (RFC 7523)
+const clientAssertionType string = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// clientAssertionLifetime is how long client assertions are valid, only
+// enough to send them
+const clientAssertionLifetime time.Duration = 5 * time.Minute
+
+// authenticateClient adds the client authentication of the credentials
+// method to the headers or the form of a token request
+func authenticateClient(credentials Credentials, header http.Header, form url.Values, now time.Time) error {
+	switch credentials.Method {
+	case "", config.AuthMethodClientSecret:
+		clientIdAndSecret := fmt.Sprintf("%s:%s", credentials.ClientId, credentials.ClientSecret)
+		basicCredentials := base64.StdEncoding.EncodeToString([]byte(clientIdAndSecret))
+		header.Set("Authorization", fmt.Sprintf("Basic %s", basicCredentials))
+	case config.AuthMethodPrivateKeyJwt:
+		assertion, err := clientAssertion(credentials, now)
+		if err != nil {
+			return err
+		}
+		form.Set("client_assertion_type", clientAssertionType)
+		form.Set("client_assertion", assertion)
+	case config.AuthMethodTLSClientAuth:
+		// the client certificate authenticates the client in the TLS handshake
+	case config.AuthMethodNone:
+		// public clients are only identified by the client id in the form
+	default:
+		return fmt.Errorf("unsupported authentication method %q", credentials.Method)
+	}
+	return nil
+}
+
+// clientAssertion creates the JWT authenticating the client with
+// private_key_jwt, signed with the private key of the credentials
+func clientAssertion(credentials Credentials, now time.Time) (string, error) {
+	content, err := ioutil.ReadFile(credentials.PrivateKeyFile)
+	if err != nil {
+		return "", fmt.Errorf("error reading the private key: %w", err)
+	}
+	key, err := jwt.ParsePrivateKeyPEM(content)
+	if err != nil {
+		return "", fmt.Errorf("invalid private key %q: %w", credentials.PrivateKeyFile, err)
+	}
+
+	// the token endpoint rejects assertions with an id it has seen before
+	id := make([]byte, 16)
+	_, err = rand.Read(id)
+	if err != nil {
+		return "", fmt.Errorf("error creating the client assertion id: %w", err)
+	}
+
+	claims := map[string]interface{}{
+		"iss": credentials.ClientId,
+		"sub": credentials.ClientId,
+		"aud": credentials.TokenEndpoint,
+		"jti": hex.EncodeToString(id),
+		"iat": now.Unix(),
+		"exp": now.Add(clientAssertionLifetime).Unix(),
+	}
+	return jwt.Sign(claims, key, credentials.PrivateKeyId)
+}
+
+// tokenTransport returns the transport calling the token endpoint, which
+// presents the client certificate of tls_client_auth and trusts the
+// configured CA certificates
+func tokenTransport(credentials Credentials) (http.RoundTripper, error) {
+	mutualTLS := credentials.Method == config.AuthMethodTLSClientAuth
+	if !mutualTLS && credentials.CACertFile == "" {
+		return http.DefaultTransport, nil
+	}
+
+	tlsConfig := &tls.Config{}
+	if mutualTLS {
+		certificate, err := tls.LoadX509KeyPair(credentials.ClientCertFile, credentials.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading the client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	if credentials.CACertFile != "" {
+		content, err := ioutil.ReadFile(credentials.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the CA certificates: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(content) {
+			return nil, fmt.Errorf("no CA certificates found in %q", credentials.CACertFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
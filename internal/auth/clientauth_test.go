@@ -0,0 +1,204 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+// writePEM writes a PEM block to a file in the directory, returning its path
+func writePEM(t *testing.T, dir string, name string, blockType string, content []byte) string {
+	path := filepath.Join(dir, name)
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: content}), 0600)
+	assert.NoError(t, err)
+	return path
+}
+
+// writeTokenResponse answers a token request with a fixed token
+func writeTokenResponse(w http.ResponseWriter) {
+	body, _ := json.Marshal(AccessToken{AccessToken: "token", ExpiresIn: 3600, TokenType: "Bearer"})
+	w.Write(body)
+}
+
+func TestRequestTokenWithPrivateKeyJwt(t *testing.T) {
+	// arrange
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	sec1, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	keyFile := writePEM(t, t.TempDir(), "client.pem", "EC PRIVATE KEY", sec1)
+
+	var assertion *jwt.Token
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"), "The secret must not be sent")
+		assert.Equal(t, clientAssertionType, r.FormValue("client_assertion_type"))
+		assert.Equal(t, "client_id", r.FormValue("client_id"))
+		assertion, err = jwt.Decode(r.FormValue("client_assertion"))
+		assert.NoError(t, err)
+		writeTokenResponse(w)
+	}))
+	defer srv.Close()
+
+	credentials := Credentials{
+		ClientId:       "client_id",
+		TokenEndpoint:  srv.URL,
+		Method:         config.AuthMethodPrivateKeyJwt,
+		PrivateKeyFile: keyFile,
+		PrivateKeyId:   "key-1",
+	}
+
+	// act
+	token, err := RequestToken(context.Background(), credentials)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "token", token.AccessToken)
+	assert.NoError(t, assertion.VerifyPublicKey(&key.PublicKey))
+	assert.Equal(t, "ES256", assertion.Algorithm())
+	assert.Equal(t, "key-1", assertion.KeyId())
+	assert.Equal(t, "client_id", assertion.Claims["iss"])
+	assert.Equal(t, "client_id", assertion.Claims["sub"])
+	assert.Equal(t, srv.URL, assertion.Claims["aud"])
+	assert.NotEmpty(t, assertion.Claims["jti"])
+	assert.False(t, assertion.Expired(time.Now()))
+}
+
+func TestRequestTokenWithTLSClientAuth(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "client_id"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientCert, err := x509.CreateCertificate(rand.Reader, template, template, &clientKey.PublicKey, clientKey)
+	assert.NoError(t, err)
+	sec1, err := x509.MarshalECPrivateKey(clientKey)
+	assert.NoError(t, err)
+	certFile := writePEM(t, dir, "client.crt", "CERTIFICATE", clientCert)
+	keyFile := writePEM(t, dir, "client.key", "EC PRIVATE KEY", sec1)
+
+	parsedCert, err := x509.ParseCertificate(clientCert)
+	assert.NoError(t, err)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(parsedCert)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"), "The secret must not be sent")
+		assert.Equal(t, "client_id", r.TLS.PeerCertificates[0].Subject.CommonName)
+		writeTokenResponse(w)
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	srv.Config.ErrorLog = log.New(ioutil.Discard, "", 0)
+	srv.StartTLS()
+	defer srv.Close()
+	caFile := writePEM(t, dir, "ca.crt", "CERTIFICATE", srv.Certificate().Raw)
+
+	credentials := Credentials{
+		ClientId:       "client_id",
+		TokenEndpoint:  srv.URL,
+		Method:         config.AuthMethodTLSClientAuth,
+		ClientCertFile: certFile,
+		ClientKeyFile:  keyFile,
+		CACertFile:     caFile,
+	}
+
+	// act
+	token, err := RequestToken(context.Background(), credentials)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "token", token.AccessToken)
+
+	// act
+	credentials.Method = config.AuthMethodClientSecret
+	_, err = RequestToken(context.Background(), credentials)
+
+	// assert
+	assert.Error(t, err, "The server must require the client certificate")
+}
+
+func TestRequestTokenWithInvalidCredentials(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "not.pem")
+	err := os.WriteFile(notPEM, []byte("not a key"), 0600)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		Purpose       string
+		Credentials   Credentials
+		ErrorContains string
+	}{
+		{
+			Purpose:       "unknown method",
+			Credentials:   Credentials{Method: "client_secret_jwt"},
+			ErrorContains: "unsupported authentication method \"client_secret_jwt\"",
+		},
+		{
+			Purpose: "missing private key",
+			Credentials: Credentials{
+				Method:         config.AuthMethodPrivateKeyJwt,
+				PrivateKeyFile: filepath.Join(dir, "missing.pem"),
+			},
+			ErrorContains: "error reading the private key",
+		},
+		{
+			Purpose: "invalid private key",
+			Credentials: Credentials{
+				Method:         config.AuthMethodPrivateKeyJwt,
+				PrivateKeyFile: notPEM,
+			},
+			ErrorContains: "no PEM block found",
+		},
+		{
+			Purpose: "invalid client certificate",
+			Credentials: Credentials{
+				Method:         config.AuthMethodTLSClientAuth,
+				ClientCertFile: notPEM,
+				ClientKeyFile:  notPEM,
+			},
+			ErrorContains: "error loading the client certificate",
+		},
+		{
+			Purpose:       "invalid CA certificates",
+			Credentials:   Credentials{CACertFile: notPEM},
+			ErrorContains: "no CA certificates found",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		tc.Credentials.TokenEndpoint = "https://localhost:1/token"
+
+		// act
+		_, err := RequestToken(context.Background(), tc.Credentials)
+
+		// assert
+		assert.Error(t, err, tc.Purpose)
+		assert.Contains(t, err.Error(), tc.ErrorContains, tc.Purpose)
+	}
+}
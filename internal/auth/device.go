@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// deviceCodeGrantType is the grant type of the device authorization flow
+// (RFC 8628)
+const deviceCodeGrantType string = "urn:ietf:params:oauth:grant-type:device_code"
+
+// defaultPollInterval is the interval between token requests when the
+// server does not define one
+const defaultPollInterval time.Duration = 5 * time.Second
+
+// sleep waits for the duration or until the context is done, replaced in
+// tests to poll without waiting
+var sleep = func(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// DeviceAuthorization is the response of the device authorization endpoint,
+// with the code the user types in the verification page
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval,omitempty"`
+}
+
+// LoginWithDevice gets an access token for a user with the device
+// authorization flow: it starts the login in the device authorization
+// endpoint, shows the user code with the show function and polls the token
+// endpoint until the user logs in, denies the login or the code expires
+func LoginWithDevice(
+	ctx context.Context,
+	credentials Credentials,
+	deviceEndpoint string,
+	show func(authorization DeviceAuthorization) error) (AccessToken, error) {
+
+	form := url.Values{}
+	addAccess(form, credentials)
+	authorization := DeviceAuthorization{}
+	err := postForm(ctx, credentials, deviceEndpoint, form, true, &authorization)
+	if err != nil {
+		return AccessToken{}, err
+	}
+
+	err = show(authorization)
+	if err != nil {
+		return AccessToken{}, err
+	}
+
+	return pollDeviceToken(ctx, credentials, authorization)
+}
+
+// pollDeviceToken requests the token of the device code until the user logs
+// in, slowing down when asked by the server
+func pollDeviceToken(ctx context.Context, credentials Credentials, authorization DeviceAuthorization) (AccessToken, error) {
+	interval := time.Duration(authorization.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+	expiresAt := now().Add(time.Duration(authorization.ExpiresIn) * time.Second)
+
+	for {
+		err := sleep(ctx, interval)
+		if err != nil {
+			return AccessToken{}, err
+		}
+		if authorization.ExpiresIn > 0 && now().After(expiresAt) {
+			return AccessToken{}, errors.New("the login was not completed before the code expired")
+		}
+
+		form := url.Values{}
+		form.Set("grant_type", deviceCodeGrantType)
+		form.Set("device_code", authorization.DeviceCode)
+		token := AccessToken{}
+		err = postForm(ctx, credentials, credentials.TokenEndpoint, form, true, &token)
+
+		tokenError := &TokenError{}
+		if !errors.As(err, &tokenError) {
+			return token, err
+		}
+		switch tokenError.Code {
+		case "authorization_pending":
+			continue
+		case "slow_down":
+			interval += defaultPollInterval
+			continue
+		case "access_denied":
+			return AccessToken{}, fmt.Errorf("the login was denied: %w", err)
+		case "expired_token":
+			return AccessToken{}, fmt.Errorf("the login was not completed before the code expired: %w", err)
+		}
+		return AccessToken{}, err
+	}
+}
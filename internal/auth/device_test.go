@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSleep replaces the sleep between polls, recording the intervals
+func fakeSleep(t *testing.T) *[]time.Duration {
+	intervals := []time.Duration{}
+	original := sleep
+	sleep = func(ctx context.Context, duration time.Duration) error {
+		intervals = append(intervals, duration)
+		return nil
+	}
+	t.Cleanup(func() { sleep = original })
+	return &intervals
+}
+
+// newDeviceServer creates a server with the device authorization and token
+// endpoints, answering the polls with the given errors before the token
+func newDeviceServer(t *testing.T, pollErrors []string) *httptest.Server {
+	polls := 0
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "client_id", r.FormValue("client_id"))
+		switch r.URL.Path {
+		case "/device":
+			json.NewEncoder(w).Encode(DeviceAuthorization{
+				DeviceCode:      "device_code",
+				UserCode:        "ABCD-EFGH",
+				VerificationURI: "https://auth.example.com/device",
+				ExpiresIn:       600,
+				Interval:        2,
+			})
+		case "/token":
+			assert.Equal(t, deviceCodeGrantType, r.FormValue("grant_type"))
+			assert.Equal(t, "device_code", r.FormValue("device_code"))
+			if polls < len(pollErrors) {
+				polls++
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintf(w, `{"error": %q}`, pollErrors[polls-1])
+				return
+			}
+			json.NewEncoder(w).Encode(AccessToken{
+				AccessToken:  "token",
+				RefreshToken: "refresh_token",
+				ExpiresIn:    3600,
+			})
+		}
+	}))
+}
+
+func TestLoginWithDevice(t *testing.T) {
+	// arrange
+	intervals := fakeSleep(t)
+	srv := newDeviceServer(t, []string{"authorization_pending", "slow_down", "authorization_pending"})
+	defer srv.Close()
+	credentials := Credentials{
+		ClientId:      "client_id",
+		TokenEndpoint: srv.URL + "/token",
+		Method:        config.AuthMethodNone,
+	}
+	shown := DeviceAuthorization{}
+
+	// act
+	token, err := LoginWithDevice(context.Background(), credentials, srv.URL+"/device",
+		func(authorization DeviceAuthorization) error {
+			shown = authorization
+			return nil
+		})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "token", token.AccessToken)
+	assert.Equal(t, "refresh_token", token.RefreshToken)
+	assert.Equal(t, "ABCD-EFGH", shown.UserCode)
+	assert.Equal(t, []time.Duration{
+		2 * time.Second,
+		2 * time.Second,
+		7 * time.Second,
+		7 * time.Second,
+	}, *intervals, "The interval must grow when asked to slow down")
+}
+
+func TestLoginWithDeviceFailures(t *testing.T) {
+	testCases := []struct {
+		Purpose    string
+		PollErrors []string
+		Error      string
+	}{
+		{
+			Purpose:    "login denied",
+			PollErrors: []string{"authorization_pending", "access_denied"},
+			Error:      "the login was denied: error getting token: access_denied (HTTP 400 Bad Request)",
+		},
+		{
+			Purpose:    "code expired",
+			PollErrors: []string{"expired_token"},
+			Error:      "the login was not completed before the code expired: error getting token: expired_token (HTTP 400 Bad Request)",
+		},
+		{
+			Purpose:    "other error",
+			PollErrors: []string{"invalid_client"},
+			Error:      "error getting token: invalid_client (HTTP 400 Bad Request)",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		fakeSleep(t)
+		srv := newDeviceServer(t, tc.PollErrors)
+		credentials := Credentials{
+			ClientId:      "client_id",
+			TokenEndpoint: srv.URL + "/token",
+			Method:        config.AuthMethodNone,
+		}
+
+		// act
+		_, err := LoginWithDevice(context.Background(), credentials, srv.URL+"/device",
+			func(authorization DeviceAuthorization) error { return nil })
+		srv.Close()
+
+		// assert
+		assert.EqualError(t, err, tc.Error, tc.Purpose)
+	}
+}
+
+func TestPollDeviceTokenStopsWhenTheCodeExpires(t *testing.T) {
+	// arrange
+	start := time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC)
+	current := start
+	now = func() time.Time { return current }
+	defer func() { now = time.Now }()
+	original := sleep
+	sleep = func(ctx context.Context, duration time.Duration) error {
+		current = current.Add(duration)
+		return nil
+	}
+	defer func() { sleep = original }()
+
+	srv := newDeviceServer(t, []string{"authorization_pending", "authorization_pending"})
+	defer srv.Close()
+	credentials := Credentials{ClientId: "client_id", TokenEndpoint: srv.URL + "/token"}
+
+	// act
+	_, err := pollDeviceToken(context.Background(), credentials, DeviceAuthorization{
+		DeviceCode: "device_code",
+		ExpiresIn:  12,
+		Interval:   5,
+	})
+
+	// assert
+	assert.EqualError(t, err, "the login was not completed before the code expired")
+}
@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"context"
+	"net/url"
+)
+
+// Introspection is the response of a token introspection endpoint (RFC
+// 7662), with the active member and the metadata the server reports about
+// the token, like scope, exp or sub
+type Introspection map[string]interface{}
+
+// Active checks if the server considers the token active
+func (i Introspection) Active() bool {
+	active, _ := i["active"].(bool)
+	return active
+}
+
+// Introspect asks the introspection endpoint about the access token,
+// authenticating the client like in the token requests
+func Introspect(ctx context.Context, credentials Credentials, endpoint string, token string) (Introspection, error) {
+	form := url.Values{}
+	form.Set("token", token)
+	form.Set("token_type_hint", "access_token")
+
+	// introspection has no side effects so it can be retried
+	introspection := Introspection{}
+	err := postForm(ctx, credentials, endpoint, form, true, &introspection)
+	return introspection, err
+}
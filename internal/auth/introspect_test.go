@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntrospect(t *testing.T) {
+	testCases := []struct {
+		Purpose  string
+		Response string
+		Active   bool
+	}{
+		{
+			Purpose:  "active token with metadata",
+			Response: `{"active": true, "scope": "read write", "sub": "user"}`,
+			Active:   true,
+		},
+		{
+			Purpose:  "inactive token",
+			Response: `{"active": false}`,
+			Active:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Logf("testing %s", tc.Purpose)
+
+		// arrange
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			clientId, clientSecret, ok := r.BasicAuth()
+			assert.True(t, ok)
+			assert.Equal(t, "client_id", clientId)
+			assert.Equal(t, "client_secret", clientSecret)
+			assert.Equal(t, "access_token", r.FormValue("token"))
+			assert.Equal(t, "access_token", r.FormValue("token_type_hint"))
+			fmt.Fprint(w, tc.Response)
+		}))
+		credentials := Credentials{
+			ClientId:      "client_id",
+			ClientSecret:  "client_secret",
+			TokenEndpoint: srv.URL + "/token",
+			Method:        config.AuthMethodClientSecret,
+		}
+
+		// act
+		introspection, err := Introspect(context.Background(), credentials, srv.URL+"/introspect", "access_token")
+		srv.Close()
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, tc.Active, introspection.Active())
+		if tc.Active {
+			assert.Equal(t, "read write", introspection["scope"])
+		}
+	}
+}
+
+func TestIntrospectWithError(t *testing.T) {
+	// arrange
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error": "invalid_client", "error_description": "unknown client"}`)
+	}))
+	defer srv.Close()
+	credentials := Credentials{
+		ClientId:      "client_id",
+		ClientSecret:  "client_secret",
+		TokenEndpoint: srv.URL + "/token",
+		Method:        config.AuthMethodClientSecret,
+	}
+
+	// act
+	_, err := Introspect(context.Background(), credentials, srv.URL+"/introspect", "access_token")
+
+	// assert
+	tokenError := &TokenError{}
+	assert.ErrorAs(t, err, &tokenError)
+	assert.Equal(t, http.StatusUnauthorized, tokenError.StatusCode)
+	assert.Equal(t, "invalid_client", tokenError.Code)
+}
@@ -0,0 +1,153 @@
+package clierrors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+
+	"github.com/renato0307/learning-go-cli/internal/api"
+	"github.com/renato0307/learning-go-cli/internal/auth"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of the CLI, documented in the README
+const (
+	ExitOK             int = 0
+	ExitError          int = 1
+	ExitUsage          int = 2
+	ExitConfig         int = 3
+	ExitAuth           int = 4
+	ExitNetwork        int = 5
+	ExitAPIClientError int = 6
+	ExitAPIServerError int = 7
+	ExitCancelled      int = 130
+)
+
+// ErrCancelled is returned when the execution is cancelled with Ctrl-C
+// (SIGINT) or SIGTERM
+var ErrCancelled = errors.New("operation cancelled")
+
+// UsageError is returned when a command is called with invalid arguments or
+// flags
+type UsageError struct {
+	Err error
+}
+
+// Error returns the description of the problem
+func (e *UsageError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+// Usagef creates a UsageError with a formatted message
+func Usagef(format string, a ...interface{}) error {
+	return &UsageError{Err: fmt.Errorf(format, a...)}
+}
+
+// UsageArgs wraps the errors of a cobra arguments validator, like
+// cobra.ExactArgs, into usage errors
+func UsageArgs(args cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, positionalArgs []string) error {
+		err := args(cmd, positionalArgs)
+		if err != nil {
+			return &UsageError{Err: err}
+		}
+		return nil
+	}
+}
+
+// UsageFlagErrorFunc turns errors parsing flags into usage errors, to be set
+// with cobra.Command.SetFlagErrorFunc
+func UsageFlagErrorFunc(cmd *cobra.Command, err error) error {
+	return &UsageError{Err: err}
+}
+
+// ExitCode returns the exit code matching the kind of an error
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	usageError := &UsageError{}
+	invalidConfigError := &config.InvalidConfigError{}
+	tokenError := &auth.TokenError{}
+	apiError := &api.APIError{}
+	urlError := &url.Error{}
+	var netError net.Error
+
+	switch {
+	case errors.Is(err, ErrCancelled), errors.Is(err, context.Canceled):
+		return ExitCancelled
+	case errors.As(err, &usageError):
+		return ExitUsage
+	case errors.As(err, &invalidConfigError):
+		return ExitConfig
+	case errors.As(err, &tokenError):
+		return ExitAuth
+	case errors.As(err, &apiError):
+		if apiError.ClientError() {
+			return ExitAPIClientError
+		}
+		return ExitAPIServerError
+	case errors.As(err, &urlError),
+		errors.As(err, &netError),
+		errors.Is(err, context.DeadlineExceeded):
+		return ExitNetwork
+	}
+	return ExitError
+}
+
+// errorOutput is the JSON representation of an error, for scripts
+type errorOutput struct {
+	Error errorDetails `json:"error"`
+}
+
+// errorDetails describes an error in the JSON representation, including the
+// status, request id and details of API errors
+type errorDetails struct {
+	Message   string   `json:"message"`
+	ExitCode  int      `json:"exit_code"`
+	Status    int      `json:"status,omitempty"`
+	RequestId string   `json:"request_id,omitempty"`
+	Details   []string `json:"details,omitempty"`
+}
+
+// Print writes an error for people, or as JSON for scripts
+func Print(w io.Writer, err error, asJSON bool) error {
+	if !asJSON {
+		_, writeErr := fmt.Fprintf(w, "Error: %s\n", err)
+		return writeErr
+	}
+
+	details := errorDetails{
+		Message:  err.Error(),
+		ExitCode: ExitCode(err),
+	}
+	apiError := &api.APIError{}
+	if errors.As(err, &apiError) {
+		details.Message = apiError.Message
+		details.Status = apiError.StatusCode
+		details.RequestId = apiError.RequestId
+		details.Details = apiError.Details
+	}
+	tokenError := &auth.TokenError{}
+	if errors.As(err, &tokenError) {
+		details.Status = tokenError.StatusCode
+	}
+
+	content, marshalErr := json.MarshalIndent(errorOutput{Error: details}, "", "  ")
+	if marshalErr != nil {
+		return marshalErr
+	}
+	_, writeErr := fmt.Fprintln(w, string(content))
+	return writeErr
+}
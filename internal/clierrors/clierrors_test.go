@@ -0,0 +1,190 @@
+package clierrors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/api"
+	"github.com/renato0307/learning-go-cli/internal/auth"
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	testCases := []struct {
+		Err      error
+		Expected int
+		Purpose  string
+	}{
+		{
+			Err:      nil,
+			Expected: ExitOK,
+			Purpose:  "no error",
+		},
+		{
+			Err:      errors.New("failure"),
+			Expected: ExitError,
+			Purpose:  "generic error",
+		},
+		{
+			Err:      Usagef("must specify a subcommand"),
+			Expected: ExitUsage,
+			Purpose:  "usage error",
+		},
+		{
+			Err:      &config.InvalidConfigError{Err: errors.New("invalid")},
+			Expected: ExitConfig,
+			Purpose:  "config error",
+		},
+		{
+			Err: fmt.Errorf("error getting the JWT to call the API: %w",
+				&auth.TokenError{StatusCode: http.StatusUnauthorized}),
+			Expected: ExitAuth,
+			Purpose:  "auth error",
+		},
+		{
+			Err: &url.Error{Op: "Post", URL: "http://localhost",
+				Err: errors.New("connection refused")},
+			Expected: ExitNetwork,
+			Purpose:  "network error",
+		},
+		{
+			Err:      context.DeadlineExceeded,
+			Expected: ExitNetwork,
+			Purpose:  "timeout",
+		},
+		{
+			Err:      &api.APIError{StatusCode: http.StatusBadRequest},
+			Expected: ExitAPIClientError,
+			Purpose:  "api 4xx error",
+		},
+		{
+			Err:      &api.APIError{StatusCode: http.StatusBadGateway},
+			Expected: ExitAPIServerError,
+			Purpose:  "api 5xx error",
+		},
+		{
+			Err:      ErrCancelled,
+			Expected: ExitCancelled,
+			Purpose:  "cancelled",
+		},
+		{
+			Err: &url.Error{Op: "Post", URL: "http://localhost",
+				Err: context.Canceled},
+			Expected: ExitCancelled,
+			Purpose:  "request cancelled",
+		},
+	}
+
+	for _, tc := range testCases {
+		// act
+		code := ExitCode(tc.Err)
+
+		// assert
+		assert.Equal(t, tc.Expected, code, "invalid exit code for "+tc.Purpose)
+	}
+}
+
+func TestUsageArgs(t *testing.T) {
+	// arrange
+	args := UsageArgs(cobra.ExactArgs(1))
+
+	// act & assert
+	assert.NoError(t, args(&cobra.Command{}, []string{"a"}))
+
+	err := args(&cobra.Command{}, []string{})
+	usageError := &UsageError{}
+	assert.ErrorAs(t, err, &usageError)
+}
+
+func TestUsageFlagErrorFunc(t *testing.T) {
+	// act
+	err := UsageFlagErrorFunc(&cobra.Command{}, errors.New("unknown flag: --x"))
+
+	// assert
+	assert.Equal(t, ExitUsage, ExitCode(err))
+	assert.Equal(t, "unknown flag: --x", err.Error())
+}
+
+func TestPrint(t *testing.T) {
+	apiError := &api.APIError{
+		StatusCode: http.StatusBadRequest,
+		Message:    "request is malformed",
+		RequestId:  "1234",
+		Details:    []string{"no-hyphens is invalid"},
+	}
+
+	testCases := []struct {
+		Err      error
+		AsJSON   bool
+		Expected string
+		Purpose  string
+	}{
+		{
+			Err:      errors.New("failure"),
+			AsJSON:   false,
+			Expected: "Error: failure\n",
+			Purpose:  "error for people",
+		},
+		{
+			Err:    errors.New("failure"),
+			AsJSON: true,
+			Expected: `{
+  "error": {
+    "message": "failure",
+    "exit_code": 1
+  }
+}
+`,
+			Purpose: "error as json",
+		},
+		{
+			Err:    apiError,
+			AsJSON: true,
+			Expected: `{
+  "error": {
+    "message": "request is malformed",
+    "exit_code": 6,
+    "status": 400,
+    "request_id": "1234",
+    "details": [
+      "no-hyphens is invalid"
+    ]
+  }
+}
+`,
+			Purpose: "api error as json",
+		},
+		{
+			Err:    &auth.TokenError{StatusCode: http.StatusUnauthorized, Message: "invalid_client"},
+			AsJSON: true,
+			Expected: `{
+  "error": {
+    "message": "error getting token: invalid_client (HTTP 401 Unauthorized)",
+    "exit_code": 4,
+    "status": 401
+  }
+}
+`,
+			Purpose: "auth error as json",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		buffer := &bytes.Buffer{}
+
+		// act
+		err := Print(buffer, tc.Err, tc.AsJSON)
+
+		// assert
+		assert.NoError(t, err, "error found for "+tc.Purpose)
+		assert.Equal(t, tc.Expected, buffer.String(), "invalid output for "+tc.Purpose)
+	}
+}
@@ -1,12 +1,19 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/spf13/cast"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
@@ -17,8 +24,100 @@ const (
 	ClientSecretFlag  string = "client-secret"
 	APIEndpointFlag   string = "api-endpoint"
 	TokenEndpointFlag string = "token-endpoint"
+	TokenSkewFlag     string = "token-expiry-skew"
+	TimeoutFlag       string = "timeout"
 )
 
+// Client authentication flags and methods, named as in RFC 8414 and RFC 8705
+const (
+	AuthMethodFlag          string = "auth-method"
+	PrivateKeyFlag          string = "private-key"
+	PrivateKeyIdFlag        string = "private-key-id"
+	ClientCertFlag          string = "client-cert"
+	ClientKeyFlag           string = "client-key"
+	CACertFlag              string = "ca-cert"
+	AuthMethodClientSecret  string = "client_secret_basic"
+	AuthMethodPrivateKeyJwt string = "private_key_jwt"
+	AuthMethodTLSClientAuth string = "tls_client_auth"
+	AuthMethodNone          string = "none"
+)
+
+// Interactive login flags and settings
+const (
+	DeviceEndpointFlag        string = "device-authorization-endpoint"
+	AuthorizationEndpointFlag string = "authorization-endpoint"
+	RedirectPortFlag          string = "redirect-port"
+	RefreshTokenFlag          string = "refresh-token"
+)
+
+// Access flags and settings, defining what the access tokens are for
+const (
+	ScopesFlag       string = "scopes"
+	AudienceFlag     string = "audience"
+	ResourceFlag     string = "resource"
+	ScopesAnnotation string = "scopes"
+)
+
+// Token introspection flags and settings
+const (
+	IntrospectionEndpointFlag string = "introspection-endpoint"
+)
+
+// Authentication header flags and settings
+const (
+	AuthHeaderFlag    string = "auth-header"
+	AuthSchemeFlag    string = "auth-scheme"
+	DefaultAuthHeader string = "Authorization"
+	AuthSchemeNone    string = "none"
+)
+
+// Retry flags and settings
+const (
+	NoRetryFlag          string = "no-retry"
+	RetryMaxAttemptsFlag string = "retry-max-attempts"
+	RetryBaseDelayFlag   string = "retry-base-delay"
+	RetryMaxDelayFlag    string = "retry-max-delay"
+	RetryStatusCodesFlag string = "retry-status-codes"
+)
+
+// Output flags and formats
+const (
+	OutputFlag             string = "output"
+	OutputJSON             string = "json"
+	OutputYAML             string = "yaml"
+	OutputTable            string = "table"
+	OutputValue            string = "value"
+	OutputGoTemplatePrefix string = "go-template="
+	JqFlag                 string = "jq"
+)
+
+// Command settings
+const (
+	LocalUuidFlag string = "local-uuid"
+)
+
+// EnvPrefix is the prefix of the environment variables defining settings
+const EnvPrefix string = "LEARNING_GO_CLI"
+
+// Profile flags and settings
+const (
+	ProfileFlag        string = "profile"
+	DefaultProfileFlag string = "default-profile"
+	ProfileEnvVar      string = EnvPrefix + "_PROFILE"
+	DefaultProfile     string = "default"
+	profilesKey        string = "profiles"
+)
+
+// Config file location, relative to the home directory
+const (
+	configName string = ".learning-go-cli"
+	configExt  string = "yaml"
+)
+
+// tokenCacheFileName is the name of the file, stored next to the config
+// file, where access tokens are cached between invocations
+const tokenCacheFileName string = ".learning-go-cli-tokens.json"
+
 // initConfig reads in config file and ENV variables if set
 func InitConfig() {
 
@@ -28,14 +127,12 @@ func InitConfig() {
 
 	// search config in home directory with name
 	// ".learning-go-cli" (without extension).
-	ext := "yaml"
-	name := ".learning-go-cli"
 	viper.AddConfigPath(home)
-	viper.SetConfigType(ext)
-	viper.SetConfigName(name)
+	viper.SetConfigType(configExt)
+	viper.SetConfigName(configName)
 
 	// creates config file if it does not exist
-	_, err = CreateConfigFile(home, name, ext)
+	_, err = CreateConfigFile(home, configName, configExt)
 	cobra.CheckErr(err)
 
 	// if a config file is found, read it in.
@@ -44,24 +141,25 @@ func InitConfig() {
 	}
 }
 
-// CreateConfigFile creates the config file if it does not exist
+// CreateConfigFile creates the config file if it does not exist, readable
+// only by the current user as it may contain credentials
 func CreateConfigFile(home string, name string, ext string) (string, error) {
 	fileName := fmt.Sprintf("%s/%s.%s", home, name, ext)
 	_, err := os.Stat(fileName)
 	if os.IsNotExist(err) {
-		file, err := os.Create(fileName)
+		file, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY, 0600)
 		if err != nil {
-			defer file.Close()
-			return fileName, nil
+			return fileName, err
 		}
-		return fileName, err
+		return fileName, file.Close()
 	}
 	return fileName, nil
 }
 
-// CreateFakeConfigFile configures viper to write to a temporary file
+// CreateFakeConfigFile configures viper to write to a temporary file, in a
+// temporary directory so the config file of the user is never changed
 func CreateFakeConfigFile(t *testing.T) string {
-	home, _ := os.UserHomeDir()
+	home := t.TempDir()
 	ext := "yaml"
 	name := fmt.Sprintf(".learning-go-cli-test-%d", rand.Uint64())
 	fileName, err := CreateConfigFile(home, name, ext)
@@ -70,14 +168,13 @@ func CreateFakeConfigFile(t *testing.T) string {
 	}
 
 	viper.Reset()
-	viper.AddConfigPath(home)
-	viper.SetConfigType(ext)
-	viper.SetConfigName(name)
+	viper.SetConfigFile(fileName)
 
 	Set(APIEndpointFlag, "fake_endpoint")
 	Set(TokenEndpointFlag, "fake_endpoint")
 	Set(ClientIdFlag, "fake_client_id")
 	Set(ClientSecretFlag, "fake_client_secret")
+	Set(SecretStoreFlag, SecretStorePlaintext)
 	viper.WriteConfig()
 
 	return fileName
@@ -85,27 +182,272 @@ func CreateFakeConfigFile(t *testing.T) string {
 
 // GetString returns a configuration string
 func GetString(key string) string {
-	return viper.GetString(key)
+	value, _ := Lookup(key)
+	return cast.ToString(value)
+}
+
+// GetDuration returns a configuration duration
+func GetDuration(key string) time.Duration {
+	value, _ := Lookup(key)
+	return cast.ToDuration(value)
+}
+
+// GetInt returns a configuration integer
+func GetInt(key string) int {
+	value, _ := Lookup(key)
+	return cast.ToInt(value)
+}
+
+// GetBool returns a configuration boolean
+func GetBool(key string) bool {
+	value, _ := Lookup(key)
+	return cast.ToBool(value)
+}
+
+// ConfigFile returns the path of the config file
+func ConfigFile() (string, error) {
+	if configFile := viper.ConfigFileUsed(); configFile != "" {
+		return configFile, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, configName+"."+configExt), nil
 }
 
-// Set defines a configuration value
+// TokenCacheFile returns the path of the file used to cache access tokens,
+// located in the same directory as the config file
+func TokenCacheFile() (string, error) {
+	if configFile := viper.ConfigFileUsed(); configFile != "" {
+		return filepath.Join(filepath.Dir(configFile), tokenCacheFileName), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, tokenCacheFileName), nil
+}
+
+// Set defines a configuration value. Keys stored per profile are set in the
+// selected profile.
 func Set(key string, value interface{}) {
-	viper.Set(key, value)
+	viper.Set(resolveWriteKey(key), value)
+}
+
+// Write persists the configuration in the config file
+func Write() error {
+	return viper.WriteConfig()
+}
+
+// ErrNotSet is returned when unsetting a setting without a value
+var ErrNotSet = errors.New("not set")
+
+// Unset removes a configuration value from the config file. Keys stored per
+// profile are removed from the selected profile, or from the flat
+// configuration when the value is read from it, and secrets are also removed
+// from the secret store.
+func Unset(key string) error {
+	fullKey := resolveKey(key)
+	if setting, found := FindSetting(key); found && setting.Secret {
+		_, err := lookupSecret(key)
+		removed := err == nil || viper.IsSet(fullKey)
+		err = DeleteSecret(key)
+		if err != nil || removed {
+			return err
+		}
+		return notSetError(key)
+	}
+
+	if !viper.IsSet(fullKey) {
+		return notSetError(key)
+	}
+	return removeKeys([]string{fullKey})
+}
+
+// notSetError returns the ErrNotSet error of a setting
+func notSetError(key string) error {
+	if isProfileKey(key) {
+		return fmt.Errorf("%s of profile %q is %w", key, Profile(), ErrNotSet)
+	}
+	return fmt.Errorf("%s is %w", key, ErrNotSet)
+}
+
+// removeKeys removes the full keys from the config file
+func removeKeys(keys []string) error {
+	configFile, err := ConfigFile()
+	if err != nil {
+		return err
+	}
+
+	settings := viper.AllSettings()
+	for _, key := range keys {
+		deleteNestedKey(settings, strings.Split(key, viperKeyDelimiter))
+	}
+
+	// viper cannot remove keys so it is reset with the remaining settings
+	viper.Reset()
+	viper.SetConfigFile(configFile)
+	err = viper.MergeConfigMap(settings)
+	if err != nil {
+		return err
+	}
+	return viper.WriteConfig()
+}
+
+// deleteNestedKey removes the key with the given path from nested maps
+func deleteNestedKey(settings map[string]interface{}, path []string) {
+	if len(path) == 1 {
+		delete(settings, path[0])
+		return
+	}
+
+	nested, ok := settings[path[0]].(map[string]interface{})
+	if ok {
+		deleteNestedKey(nested, path[1:])
+	}
+}
+
+// boundFlags are the flags defining configuration values. They are not bound
+// to viper to prevent their values from being persisted in the config file.
+var boundFlags = map[string]*pflag.Flag{}
+
+// BindFlag makes the flag value be used for the configuration key when set
+func BindFlag(key string, flag *pflag.Flag) {
+	boundFlags[key] = flag
 }
 
-// WriteAuthenticationConfig persists the authentication configuration
+// AddFlags adds a flag, of the type of the setting, for each setting that
+// can be overridden with flags, and binds them to the settings. All the other
+// settings can be overridden with environment variables.
+func AddFlags(flags *pflag.FlagSet) {
+	for _, setting := range Settings {
+		if !setting.Flag || setting.Secret {
+			continue
+		}
+
+		usage := fmt.Sprintf("%s (env %s)", setting.Description, EnvVar(setting.Key))
+		switch setting.Type {
+		case TypeBool:
+			flags.BoolP(setting.Key, setting.Shorthand, false, usage)
+		case TypeInt:
+			flags.IntP(setting.Key, setting.Shorthand, 0, usage)
+		case TypeDuration:
+			flags.DurationP(setting.Key, setting.Shorthand, 0, usage)
+		default:
+			flags.StringP(setting.Key, setting.Shorthand, "", usage)
+		}
+		BindFlag(setting.Key, flags.Lookup(setting.Key))
+	}
+}
+
+// EnvVar returns the name of the environment variable defining a setting,
+// like LEARNING_GO_CLI_CLIENT_ID for client-id
+func EnvVar(key string) string {
+	name := strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+	return fmt.Sprintf("%s_%s", EnvPrefix, name)
+}
+
+// Profile returns the name of the selected profile, taken from the profile
+// flag, the profile environment variable or the default profile setting, in
+// this order
+func Profile() string {
+	if flag, found := boundFlags[ProfileFlag]; found && flag.Changed {
+		return flag.Value.String()
+	}
+	if profile := os.Getenv(ProfileEnvVar); profile != "" {
+		return profile
+	}
+	if profile := viper.GetString(DefaultProfileFlag); profile != "" {
+		return profile
+	}
+	return DefaultProfile
+}
+
+// Profiles returns the names of the profiles defined in the config file
+func Profiles() []string {
+	profiles := []string{}
+	for name := range viper.GetStringMap(profilesKey) {
+		profiles = append(profiles, name)
+	}
+	sort.Strings(profiles)
+	return profiles
+}
+
+// ValidateProfileName checks if a profile name can be used as part of a
+// configuration key
+func ValidateProfileName(profile string) error {
+	if profile == "" || strings.Contains(profile, viperKeyDelimiter) {
+		return fmt.Errorf("invalid profile name %q: profile names cannot be empty or contain %q",
+			profile,
+			viperKeyDelimiter)
+	}
+	return nil
+}
+
+// viperKeyDelimiter separates the parts of nested configuration keys
+const viperKeyDelimiter string = "."
+
+// profileKey returns the full key of a setting stored in a profile
+func profileKey(profile string, key string) string {
+	return strings.Join([]string{profilesKey, profile, key}, viperKeyDelimiter)
+}
+
+// resolveWriteKey returns the full key to write a setting, which is in the
+// selected profile for keys stored per profile
+func resolveWriteKey(key string) string {
+	if !isProfileKey(key) {
+		return key
+	}
+	return profileKey(Profile(), key)
+}
+
+// resolveKey returns the full key to read a setting. Keys stored per profile
+// are read from the selected profile, falling back to the top-level keys of
+// the flat configuration used before profiles existed, but only for the
+// default profile so other profiles never use those credentials.
+func resolveKey(key string) string {
+	if !isProfileKey(key) {
+		return key
+	}
+
+	profile := Profile()
+	fullKey := profileKey(profile, key)
+	if !viper.IsSet(fullKey) && profile == DefaultProfile && viper.IsSet(key) {
+		return key
+	}
+	return fullKey
+}
+
+// WriteAuthenticationConfig persists the authentication configuration in the
+// selected profile
 func WriteAuthenticationConfig(
 	clientId,
 	clientSecret,
 	apiEndpoint,
 	tokenEndpoint string) error {
 
-	Set(ClientSecretFlag, clientSecret)
+	err := ValidateProfileName(Profile())
+	if err != nil {
+		return err
+	}
+
+	// the secret is stored first so a failing secret store leaves the config
+	// file untouched. It is not used by other authentication methods.
+	if clientSecret != "" {
+		err = SetSecret(ClientSecretFlag, clientSecret)
+		if err != nil {
+			return err
+		}
+	}
+
 	Set(ClientIdFlag, clientId)
 	Set(APIEndpointFlag, apiEndpoint)
 	Set(TokenEndpointFlag, tokenEndpoint)
 
-	return viper.WriteConfig()
+	return Write()
 }
 
 // addCommandWithConfigPreCheck adds a command to the parentCmd configuring a
@@ -116,18 +458,138 @@ func AddCommandWithConfigPreCheck(parentCmd *cobra.Command, cmd *cobra.Command)
 	parentCmd.AddCommand(cmd)
 }
 
-// configPreCheck verifies if the base configuration is set
+// RequireScopes declares the scopes the command needs in the access tokens,
+// which are requested together with the configured ones
+func RequireScopes(cmd *cobra.Command, scopes ...string) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	required := append(strings.Fields(cmd.Annotations[ScopesAnnotation]), scopes...)
+	cmd.Annotations[ScopesAnnotation] = strings.Join(required, " ")
+}
+
+// RequiredScopes returns the scopes needed by the command, including the
+// ones declared by its parents for all their subcommands, without repetitions
+func RequiredScopes(cmd *cobra.Command) []string {
+	scopes := []string{}
+	seen := map[string]bool{}
+	for current := cmd; current != nil; current = current.Parent() {
+		for _, scope := range strings.Fields(current.Annotations[ScopesAnnotation]) {
+			if !seen[scope] {
+				seen[scope] = true
+				scopes = append(scopes, scope)
+			}
+		}
+	}
+	return scopes
+}
+
+// InvalidConfigError is returned when the configuration of a profile is
+// missing or invalid
+type InvalidConfigError struct {
+	Profile string
+	Err     error
+}
+
+// Error returns the description of the problem
+func (e *InvalidConfigError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *InvalidConfigError) Unwrap() error {
+	return e.Err
+}
+
+// configPreCheck verifies if the base configuration is set for the selected
+// profile, either in the config file, environment variables or flags
 func ConfigPreCheck(cmd *cobra.Command, args []string) error {
-	validConfig := viper.InConfig(ClientIdFlag) &&
-		viper.InConfig(ClientSecretFlag) &&
-		viper.InConfig(APIEndpointFlag) &&
-		viper.InConfig(TokenEndpointFlag)
+	profile := Profile()
+	validConfig := true
+	secrets := []Setting{}
+	for _, setting := range Settings {
+		if !setting.IsRequired() {
+			continue
+		}
+		if setting.Secret {
+			secrets = append(secrets, setting)
+			continue
+		}
+
+		defined, err := isDefined(profile, setting)
+		if err != nil {
+			return invalidConfig(profile, err)
+		}
+		validConfig = validConfig && defined
+	}
+
+	// secrets are only read for configured profiles, so a secret store that
+	// cannot be used is not reported for profiles that were never configured
+	for _, setting := range secrets {
+		if !validConfig {
+			break
+		}
+		defined, err := isDefined(profile, setting)
+		if err != nil {
+			return invalidConfig(profile, err)
+		}
+		validConfig = defined
+	}
 
 	if !validConfig {
-		return fmt.Errorf(
-			"invalid CLI configuration: " +
-				"please run `learning-go-api configure`")
+		return &InvalidConfigError{
+			Profile: profile,
+			Err: fmt.Errorf(
+				"invalid CLI configuration for profile %q: "+
+					"please run `learning-go-cli configure --profile %s`",
+				profile,
+				profile),
+		}
 	}
 
 	return nil
 }
+
+// invalidConfig returns the error of a configuration that cannot be read
+func invalidConfig(profile string, err error) error {
+	return &InvalidConfigError{
+		Profile: profile,
+		Err: fmt.Errorf("invalid CLI configuration for profile %q: %w",
+			profile,
+			err),
+	}
+}
+
+// isDefined checks if a setting is defined for the profile in the config
+// file, environment variables, flags or, for secrets, in the secret store
+func isDefined(profile string, setting Setting) (bool, error) {
+	if inProfileConfig(profile, setting.Key) || isOverridden(setting.Key) {
+		return true, nil
+	}
+	if !setting.Secret {
+		return false, nil
+	}
+
+	_, err := lookupSecret(setting.Key)
+	if errors.Is(err, ErrSecretNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// isOverridden checks if a key has a value defined by a flag or an
+// environment variable
+func isOverridden(key string) bool {
+	value, source := Lookup(key)
+	overridden := source == SourceFlag || source == SourceEnv
+	return overridden && cast.ToString(value) != ""
+}
+
+// inProfileConfig checks if a key is defined in the config file for the
+// profile, considering the flat configuration for the default profile
+func inProfileConfig(profile string, key string) bool {
+	if viper.InConfig(profileKey(profile, key)) {
+		return true
+	}
+	return profile == DefaultProfile && viper.InConfig(key)
+}
@@ -2,11 +2,15 @@ package config
 
 import (
 	"fmt"
+	"io/ioutil"
 	"math/rand"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
@@ -39,7 +43,7 @@ func TestSet(t *testing.T) {
 
 func TestCreateConfigFile(t *testing.T) {
 	// arrange
-	home, _ := os.UserHomeDir()
+	home := t.TempDir()
 	ext := "yaml"
 	name := fmt.Sprintf(".learning-go-cli-test-%d", rand.Uint64())
 
@@ -52,6 +56,8 @@ func TestCreateConfigFile(t *testing.T) {
 	}
 	defer os.Remove(fileName)
 	assert.Equal(t, fmt.Sprintf("%s/%s.%s", home, name, ext), fileName)
+	info, _ := os.Stat(fileName)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
 }
 
 func TestConfigPreCheckReturnsErrorIfMissingConfigs(t *testing.T) {
@@ -65,6 +71,8 @@ func TestConfigPreCheckReturnsErrorIfMissingConfigs(t *testing.T) {
 	// assert
 	print(err)
 	assert.Error(t, err)
+	invalidConfig := &InvalidConfigError{}
+	assert.ErrorAs(t, err, &invalidConfig)
 }
 
 func TestConfigPreCheckReturnsNoErrorIfConfigsFound(t *testing.T) {
@@ -80,6 +88,46 @@ func TestConfigPreCheckReturnsNoErrorIfConfigsFound(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestConfigPreCheckRequiresTheSettingsOfTheAuthMethod(t *testing.T) {
+	// arrange
+	fileName := CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+	Set(AuthMethodFlag, AuthMethodPrivateKeyJwt)
+	assert.NoError(t, Unset(ClientSecretFlag))
+	viper.ReadInConfig()
+
+	// act
+	errWithoutKey := ConfigPreCheck(&cobra.Command{}, []string{})
+	Set(PrivateKeyFlag, "client.pem")
+	assert.NoError(t, Write())
+	viper.ReadInConfig()
+	errWithKey := ConfigPreCheck(&cobra.Command{}, []string{})
+
+	// assert
+	invalidConfig := &InvalidConfigError{}
+	assert.ErrorAs(t, errWithoutKey, &invalidConfig)
+	assert.NoError(t, errWithKey, "The client secret must not be required")
+}
+
+func TestRequiredScopes(t *testing.T) {
+	// arrange
+	root := &cobra.Command{Use: "root"}
+	group := &cobra.Command{Use: "group"}
+	leaf := &cobra.Command{Use: "leaf"}
+	root.AddCommand(group)
+	group.AddCommand(leaf)
+
+	// act
+	RequireScopes(group, "read")
+	RequireScopes(leaf, "write", "read")
+	RequireScopes(leaf, "admin")
+
+	// assert
+	assert.Equal(t, []string{"write", "read", "admin"}, RequiredScopes(leaf))
+	assert.Equal(t, []string{"read"}, RequiredScopes(group))
+	assert.Equal(t, []string{}, RequiredScopes(root))
+}
+
 func TestWriteAuthenticationConfig(t *testing.T) {
 	// arrange
 	fileName := CreateFakeConfigFile(t)
@@ -104,6 +152,9 @@ func TestWriteAuthenticationConfig(t *testing.T) {
 }
 
 func TestInitConfig(t *testing.T) {
+	// arrange
+	t.Setenv("HOME", t.TempDir())
+
 	// act
 	InitConfig()
 
@@ -124,3 +175,266 @@ func TestAddCommandWithConfigPreCheck(t *testing.T) {
 	assert.NotNil(t, cmd.PreRunE)
 	assert.Contains(t, parentCmd.Commands(), cmd)
 }
+
+func TestTokenCacheFile(t *testing.T) {
+	// arrange
+	fileName := CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+
+	// act
+	cacheFile, err := TokenCacheFile()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(filepath.Dir(fileName), tokenCacheFileName), cacheFile)
+}
+
+func TestProfile(t *testing.T) {
+	testCases := []struct {
+		Flag           string
+		File           string
+		Env            string
+		DefaultProfile string
+		Expected       string
+		Purpose        string
+	}{
+		{
+			Expected: DefaultProfile,
+			Purpose:  "nothing defined",
+		},
+		{
+			DefaultProfile: "dev",
+			Expected:       "dev",
+			Purpose:        "default profile setting",
+		},
+		{
+			Env:            "staging",
+			DefaultProfile: "dev",
+			Expected:       "staging",
+			Purpose:        "environment variable overrides setting",
+		},
+		{
+			Flag:           "prod",
+			Env:            "staging",
+			DefaultProfile: "dev",
+			Expected:       "prod",
+			Purpose:        "flag overrides everything",
+		},
+		{
+			File:     "prod",
+			Env:      "staging",
+			Expected: "staging",
+			Purpose:  "profile key in the config file is ignored",
+		},
+	}
+	defer delete(boundFlags, ProfileFlag)
+
+	for _, tc := range testCases {
+		// arrange
+		viper.Reset()
+		viper.Set(ProfileFlag, tc.File)
+		viper.Set(DefaultProfileFlag, tc.DefaultProfile)
+		t.Setenv(ProfileEnvVar, tc.Env)
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flags.String(ProfileFlag, "", "")
+		BindFlag(ProfileFlag, flags.Lookup(ProfileFlag))
+		if tc.Flag != "" {
+			flags.Set(ProfileFlag, tc.Flag)
+		}
+
+		// act
+		profile := Profile()
+
+		// assert
+		assert.Equal(t, tc.Expected, profile, "invalid profile for "+tc.Purpose)
+	}
+	viper.Reset()
+}
+
+func TestSetAndGetStringUseSelectedProfile(t *testing.T) {
+	// arrange
+	viper.Reset()
+	defer viper.Reset()
+	Set(ClientIdFlag, "default_client_id")
+	t.Setenv(ProfileEnvVar, "staging")
+
+	// act
+	Set(ClientIdFlag, "staging_client_id")
+
+	// assert
+	assert.Equal(t, "staging_client_id", GetString(ClientIdFlag))
+	assert.Equal(t, "staging_client_id", viper.GetString("profiles.staging.client-id"))
+	assert.Equal(t, "default_client_id", viper.GetString("profiles.default.client-id"))
+	assert.Equal(t, []string{"default", "staging"}, Profiles())
+}
+
+func TestGetStringFallsBackToFlatConfigForDefaultProfile(t *testing.T) {
+	// arrange
+	viper.Reset()
+	defer viper.Reset()
+	viper.Set(ClientIdFlag, "flat_client_id")
+
+	// act & assert
+	assert.Equal(t, "flat_client_id", GetString(ClientIdFlag))
+
+	t.Setenv(ProfileEnvVar, "staging")
+	assert.Empty(t, GetString(ClientIdFlag))
+}
+
+func TestConfigPreCheckValidatesSelectedProfile(t *testing.T) {
+	// arrange
+	fileName := CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+	viper.ReadInConfig()
+
+	// act
+	t.Setenv(ProfileEnvVar, "staging")
+	err := ConfigPreCheck(&cobra.Command{}, []string{})
+
+	// assert
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "staging")
+}
+
+func TestValidateProfileName(t *testing.T) {
+	assert.NoError(t, ValidateProfileName("staging"))
+	assert.Error(t, ValidateProfileName(""))
+	assert.Error(t, ValidateProfileName("with.dot"))
+}
+
+func TestConfigFile(t *testing.T) {
+	// arrange
+	fileName := CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+
+	// act
+	configFile, err := ConfigFile()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, fileName, configFile)
+}
+
+func TestUnset(t *testing.T) {
+	// arrange
+	fileName := CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+	Set(TokenSkewFlag, "1m")
+
+	// act
+	err := Unset(ClientSecretFlag)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Empty(t, GetString(ClientSecretFlag))
+	assert.Equal(t, "fake_client_id", GetString(ClientIdFlag))
+	assert.Equal(t, "1m", GetString(TokenSkewFlag))
+
+	viper.Reset()
+	viper.SetConfigFile(fileName)
+	viper.ReadInConfig()
+	assert.False(t, viper.InConfig("profiles.default.client-secret"))
+	assert.True(t, viper.InConfig("profiles.default.client-id"))
+}
+
+func TestUnsetFlatConfig(t *testing.T) {
+	// arrange
+	fileName := filepath.Join(t.TempDir(), "config.yaml")
+	err := ioutil.WriteFile(fileName, []byte("client-id: flat_client_id\n"+
+		"api-endpoint: https://api.example.com\n"), 0600)
+	assert.NoError(t, err)
+	viper.Reset()
+	defer viper.Reset()
+	viper.SetConfigFile(fileName)
+	viper.ReadInConfig()
+
+	// act
+	err = Unset(APIEndpointFlag)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Empty(t, GetString(APIEndpointFlag))
+	assert.Equal(t, "flat_client_id", GetString(ClientIdFlag))
+	content, _ := ioutil.ReadFile(fileName)
+	assert.Equal(t, "client-id: flat_client_id\n", string(content))
+}
+
+func TestUnsetNotSet(t *testing.T) {
+	// arrange
+	fileName := CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+	viper.ReadInConfig()
+	err := Unset(APIEndpointFlag)
+	assert.NoError(t, err)
+	before, _ := ioutil.ReadFile(fileName)
+
+	// act
+	err = Unset(APIEndpointFlag)
+
+	// assert
+	assert.ErrorIs(t, err, ErrNotSet)
+	assert.EqualError(t, err, `api-endpoint of profile "default" is not set`)
+	err = Unset(TokenSkewFlag)
+	assert.EqualError(t, err, "token-expiry-skew is not set")
+	after, _ := ioutil.ReadFile(fileName)
+	assert.Equal(t, string(before), string(after), "the file must not be rewritten")
+}
+
+func TestEnvVar(t *testing.T) {
+	assert.Equal(t, "LEARNING_GO_CLI_CLIENT_ID", EnvVar(ClientIdFlag))
+	assert.Equal(t, ProfileEnvVar, EnvVar(ProfileFlag))
+}
+
+func TestAddFlags(t *testing.T) {
+	// arrange
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	defer func() { boundFlags = map[string]*pflag.Flag{} }()
+
+	// act
+	AddFlags(flags)
+
+	// assert
+	assert.NotNil(t, flags.Lookup(ClientIdFlag))
+	assert.NotNil(t, flags.Lookup(APIEndpointFlag))
+	assert.Nil(t, flags.Lookup(DefaultProfileFlag))
+	assert.Nil(t, flags.Lookup(ClientSecretFlag), "secrets must not have flags")
+	assert.Nil(t, flags.Lookup(RefreshTokenFlag), "secrets must not have flags")
+	assert.Nil(t, flags.Lookup(ScopesFlag), "only the settings with Flag have flags")
+	assert.Equal(t, "duration", flags.Lookup(TimeoutFlag).Value.Type())
+	assert.Equal(t, "string", flags.Lookup(OutputFlag).Value.Type())
+	assert.Contains(t, boundFlags, TokenEndpointFlag)
+}
+
+func TestAddFlagsOverrideSettings(t *testing.T) {
+	// arrange
+	viper.Reset()
+	defer viper.Reset()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	defer func() { boundFlags = map[string]*pflag.Flag{} }()
+	AddFlags(flags)
+
+	// act
+	err := flags.Parse([]string{"--timeout", "1m", "--api-endpoint", "https://api.example.com"})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, GetDuration(TimeoutFlag))
+	assert.Equal(t, "https://api.example.com", GetString(APIEndpointFlag))
+	assert.Error(t, flags.Parse([]string{"--timeout", "soon"}), "durations must be validated by the flag")
+}
+
+func TestConfigPreCheckAcceptsEnvironmentVariables(t *testing.T) {
+	// arrange
+	viper.Reset()
+	defer viper.Reset()
+	t.Setenv(EnvVar(ClientIdFlag), "env_client_id")
+	t.Setenv(EnvVar(ClientSecretFlag), "env_client_secret")
+	t.Setenv(EnvVar(APIEndpointFlag), "https://api.example.com")
+	t.Setenv(EnvVar(TokenEndpointFlag), "https://auth.example.com/token")
+
+	// act
+	err := ConfigPreCheck(&cobra.Command{}, []string{})
+
+	// assert
+	assert.NoError(t, err)
+}
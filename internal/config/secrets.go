@@ -0,0 +1,498 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+	"github.com/zalando/go-keyring"
+)
+
+// Secret store settings
+const (
+	SecretStoreFlag      string = "secret-store"
+	SecretStoreKeyring   string = "keyring"
+	SecretStoreFile      string = "file"
+	SecretStorePlaintext string = "plaintext"
+	PassphraseEnvVar     string = EnvPrefix + "_PASSPHRASE"
+)
+
+// keyringService is the service name used to store secrets in the keyring
+const keyringService string = "learning-go-cli"
+
+// secretsFileName is the name of the file, stored next to the config file,
+// where secrets are kept when using the encrypted file store
+const secretsFileName string = ".learning-go-cli-secrets.age"
+
+// ErrSecretNotFound is returned by secret stores when a secret does not exist
+var ErrSecretNotFound = errors.New("secret not found")
+
+// SecretStoreUnavailableError is returned when a secret store cannot be used,
+// like the keyring of a server without a desktop session
+type SecretStoreUnavailableError struct {
+	Store string
+	Err   error
+}
+
+// Error returns the description of the problem, with the ways to solve it
+func (e *SecretStoreUnavailableError) Error() string {
+	return fmt.Sprintf("the %s secret store is not available (%s): "+
+		"please select another one with `learning-go-cli configure --secret-store file` "+
+		"or `--secret-store plaintext`, or with the %s environment variable",
+		e.Store,
+		e.Err,
+		EnvVar(SecretStoreFlag))
+}
+
+// Unwrap returns the underlying error
+func (e *SecretStoreUnavailableError) Unwrap() error {
+	return e.Err
+}
+
+// SecretStore persists secrets, like the client secret, outside of the
+// config file
+type SecretStore interface {
+	Name() string
+	Get(name string) (string, error)
+	Set(name string, value string) error
+	Delete(name string) error
+}
+
+// secretStores keeps the stores already created, avoiding decrypting the
+// encrypted file each time a secret is read
+var secretStores = map[string]SecretStore{}
+
+// NewSecretStore creates the secret store with the given name
+func NewSecretStore(name string) (SecretStore, error) {
+	switch name {
+	case SecretStoreKeyring:
+		return &KeyringStore{Service: keyringService}, nil
+	case SecretStoreFile:
+		configFile, err := ConfigFile()
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join(filepath.Dir(configFile), secretsFileName)
+		return NewEncryptedFileStore(path, os.Getenv(PassphraseEnvVar)), nil
+	case SecretStorePlaintext:
+		return &PlaintextStore{}, nil
+	}
+	return nil, fmt.Errorf("unknown secret store %q, valid stores are: %s",
+		name,
+		strings.Join(secretStoreNames(), ", "))
+}
+
+// CurrentSecretStore returns the secret store selected in the configuration
+func CurrentSecretStore() (SecretStore, error) {
+	name := GetString(SecretStoreFlag)
+	if store, found := secretStores[name]; found {
+		return store, nil
+	}
+
+	store, err := NewSecretStore(name)
+	if err != nil {
+		return nil, err
+	}
+	secretStores[name] = store
+	return store, nil
+}
+
+// secretStoreNames returns the names of all the secret stores
+func secretStoreNames() []string {
+	return []string{SecretStoreKeyring, SecretStoreFile, SecretStorePlaintext}
+}
+
+// validateSecretStore checks if a value is the name of a secret store
+func validateSecretStore(value string) error {
+	for _, name := range secretStoreNames() {
+		if value == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("%q is not a secret store, valid stores are: %s",
+		value,
+		strings.Join(secretStoreNames(), ", "))
+}
+
+// secretName returns the name identifying a secret setting of a profile in
+// the secret stores
+func secretName(profile string, key string) string {
+	return fmt.Sprintf("%s/%s", profile, key)
+}
+
+// lookupSecret returns the value of a secret setting of the selected profile
+// from the current secret store
+func lookupSecret(key string) (string, error) {
+	store, err := CurrentSecretStore()
+	if err != nil {
+		return "", err
+	}
+	return store.Get(secretName(Profile(), key))
+}
+
+// SetSecret stores the value of a secret setting of the selected profile in
+// the current secret store. For stores other than the plaintext one, the value
+// is removed from the config file.
+func SetSecret(key string, value string) error {
+	store, err := CurrentSecretStore()
+	if err != nil {
+		return err
+	}
+
+	err = store.Set(secretName(Profile(), key), value)
+	unavailable := &SecretStoreUnavailableError{}
+	if errors.As(err, &unavailable) {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("error storing the secret in the %s store: %w",
+			store.Name(),
+			err)
+	}
+
+	if store.Name() != SecretStorePlaintext && viper.IsSet(resolveWriteKey(key)) {
+		return removeKeys([]string{resolveWriteKey(key)})
+	}
+	return nil
+}
+
+// DeleteSecret removes the value of a secret setting of the selected profile
+// from everywhere Lookup reads it: the current secret store and the config
+// file, including the flat configuration of the default profile. It fails if
+// the value can still be read, like from an environment variable.
+func DeleteSecret(key string) error {
+	store, err := CurrentSecretStore()
+	if err != nil {
+		return err
+	}
+
+	err = store.Delete(secretName(Profile(), key))
+	if err != nil && !errors.Is(err, ErrSecretNotFound) {
+		return err
+	}
+
+	fullKeys := []string{}
+	for _, fullKey := range []string{profileKey(Profile(), key), resolveKey(key)} {
+		if viper.IsSet(fullKey) {
+			fullKeys = append(fullKeys, fullKey)
+		}
+	}
+	if len(fullKeys) > 0 {
+		err = removeKeys(fullKeys)
+		if err != nil {
+			return err
+		}
+	}
+
+	value, source := Lookup(key)
+	switch {
+	case cast.ToString(value) == "":
+		return nil
+	case source == SourceEnv:
+		return fmt.Errorf("%s of profile %q is still defined by the %s environment variable",
+			key,
+			Profile(),
+			EnvVar(key))
+	}
+	return fmt.Errorf("%s of profile %q could not be removed from the %s",
+		key,
+		Profile(),
+		source)
+}
+
+// MigrateSecrets moves the secrets stored in plain text in the config file,
+// for all profiles, to the given secret store and selects it as the current
+// one. It returns the names of the migrated secrets.
+func MigrateSecrets(store SecretStore) ([]string, error) {
+	migrated := []string{}
+	migratedKeys := []string{}
+
+	for _, setting := range Settings {
+		if !setting.Secret {
+			continue
+		}
+
+		// secrets in the flat configuration belong to the default profile
+		candidates := []struct{ fullKey, profile string }{
+			{fullKey: setting.Key, profile: DefaultProfile},
+		}
+		for _, profile := range Profiles() {
+			candidates = append(candidates, struct{ fullKey, profile string }{
+				fullKey: profileKey(profile, setting.Key),
+				profile: profile,
+			})
+		}
+
+		for _, candidate := range candidates {
+			if !viper.InConfig(candidate.fullKey) {
+				continue
+			}
+
+			name := secretName(candidate.profile, setting.Key)
+			err := store.Set(name, viper.GetString(candidate.fullKey))
+			if err != nil {
+				return migrated, fmt.Errorf("error migrating %s: %w", name, err)
+			}
+			migrated = append(migrated, name)
+			migratedKeys = append(migratedKeys, candidate.fullKey)
+		}
+	}
+
+	Set(SecretStoreFlag, store.Name())
+	secretStores[store.Name()] = store
+
+	return migrated, removeKeys(migratedKeys)
+}
+
+// keyringProvider is the API of the OS keyring
+type keyringProvider interface {
+	Get(service, user string) (string, error)
+	Set(service, user, password string) error
+	Delete(service, user string) error
+}
+
+// osKeyring uses the keyring of the OS
+type osKeyring struct{}
+
+func (osKeyring) Get(service, user string) (string, error) {
+	return keyring.Get(service, user)
+}
+
+func (osKeyring) Set(service, user, password string) error {
+	return keyring.Set(service, user, password)
+}
+
+func (osKeyring) Delete(service, user string) error {
+	return keyring.Delete(service, user)
+}
+
+// keyringBackend is the keyring used by the keyring store, replaced in tests
+// to simulate a missing keyring
+var keyringBackend keyringProvider = osKeyring{}
+
+// KeyringStore keeps secrets in the OS keyring: the Secret Service on Linux,
+// the Keychain on macOS and the Credential Manager on Windows
+type KeyringStore struct {
+	Service string
+}
+
+// Name returns the name of the store
+func (s *KeyringStore) Name() string {
+	return SecretStoreKeyring
+}
+
+// Get returns the secret with the given name
+func (s *KeyringStore) Get(name string) (string, error) {
+	value, err := keyringBackend.Get(s.Service, name)
+	return value, keyringError(err)
+}
+
+// Set stores the secret with the given name
+func (s *KeyringStore) Set(name string, value string) error {
+	return keyringError(keyringBackend.Set(s.Service, name, value))
+}
+
+// Delete removes the secret with the given name
+func (s *KeyringStore) Delete(name string) error {
+	return keyringError(keyringBackend.Delete(s.Service, name))
+}
+
+// keyringError converts the errors of the keyring: missing secrets to
+// ErrSecretNotFound and failures to reach the keyring, like the missing
+// Secret Service of headless Linux servers, to SecretStoreUnavailableError
+func keyringError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, keyring.ErrNotFound):
+		return ErrSecretNotFound
+	}
+	return &SecretStoreUnavailableError{Store: SecretStoreKeyring, Err: err}
+}
+
+// EncryptedFileStore keeps secrets in a local file encrypted with age using a
+// passphrase
+type EncryptedFileStore struct {
+	Path       string
+	Passphrase string
+	WorkFactor int
+	secrets    map[string]string
+}
+
+// defaultWorkFactor is the scrypt work factor used to encrypt the file
+const defaultWorkFactor int = 18
+
+// NewEncryptedFileStore creates a store persisted in the file with the given
+// path and encrypted with the passphrase
+func NewEncryptedFileStore(path string, passphrase string) *EncryptedFileStore {
+	return &EncryptedFileStore{
+		Path:       path,
+		Passphrase: passphrase,
+		WorkFactor: defaultWorkFactor,
+	}
+}
+
+// Name returns the name of the store
+func (s *EncryptedFileStore) Name() string {
+	return SecretStoreFile
+}
+
+// Get returns the secret with the given name
+func (s *EncryptedFileStore) Get(name string) (string, error) {
+	secrets, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	value, found := secrets[name]
+	if !found {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+// Set stores the secret with the given name
+func (s *EncryptedFileStore) Set(name string, value string) error {
+	secrets, err := s.read()
+	if err != nil {
+		return err
+	}
+	secrets[name] = value
+	return s.write(secrets)
+}
+
+// Delete removes the secret with the given name
+func (s *EncryptedFileStore) Delete(name string) error {
+	secrets, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, found := secrets[name]; !found {
+		return ErrSecretNotFound
+	}
+	delete(secrets, name)
+	return s.write(secrets)
+}
+
+// read decrypts the secrets file, keeping its content in memory as decrypting
+// is slow on purpose. A missing file has no secrets.
+func (s *EncryptedFileStore) read() (map[string]string, error) {
+	if s.secrets != nil {
+		return s.secrets, nil
+	}
+	if s.Passphrase == "" {
+		return nil, fmt.Errorf("the passphrase of the secrets file is not defined: "+
+			"please set the %s environment variable", PassphraseEnvVar)
+	}
+
+	content, err := ioutil.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		s.secrets = map[string]string{}
+		return s.secrets, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading the secrets file: %w", err)
+	}
+
+	identity, err := age.NewScryptIdentity(s.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := age.Decrypt(bytes.NewReader(content), identity)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting the secrets file: %w", err)
+	}
+	decrypted, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting the secrets file: %w", err)
+	}
+
+	secrets := map[string]string{}
+	err = json.Unmarshal(decrypted, &secrets)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the secrets file: %w", err)
+	}
+	s.secrets = secrets
+	return secrets, nil
+}
+
+// write encrypts the secrets into the secrets file
+func (s *EncryptedFileStore) write(secrets map[string]string) error {
+	content, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+
+	recipient, err := age.NewScryptRecipient(s.Passphrase)
+	if err != nil {
+		return err
+	}
+	recipient.SetWorkFactor(s.WorkFactor)
+
+	encrypted := &bytes.Buffer{}
+	writer, err := age.Encrypt(encrypted, recipient)
+	if err != nil {
+		return fmt.Errorf("error encrypting the secrets file: %w", err)
+	}
+	_, err = writer.Write(content)
+	if err != nil {
+		return fmt.Errorf("error encrypting the secrets file: %w", err)
+	}
+	err = writer.Close()
+	if err != nil {
+		return fmt.Errorf("error encrypting the secrets file: %w", err)
+	}
+
+	err = ioutil.WriteFile(s.Path, encrypted.Bytes(), 0600)
+	if err != nil {
+		return fmt.Errorf("error writing the secrets file: %w", err)
+	}
+	s.secrets = secrets
+	return nil
+}
+
+// PlaintextStore keeps secrets in clear text in the config file, as done
+// before secret stores existed. It must be explicitly selected.
+type PlaintextStore struct{}
+
+// Name returns the name of the store
+func (s *PlaintextStore) Name() string {
+	return SecretStorePlaintext
+}
+
+// Get returns the secret with the given name
+func (s *PlaintextStore) Get(name string) (string, error) {
+	key := plaintextKey(name)
+	if !viper.IsSet(key) {
+		return "", ErrSecretNotFound
+	}
+	return viper.GetString(key), nil
+}
+
+// Set stores the secret with the given name in the config file
+func (s *PlaintextStore) Set(name string, value string) error {
+	viper.Set(plaintextKey(name), value)
+	return Write()
+}
+
+// Delete removes the secret with the given name
+func (s *PlaintextStore) Delete(name string) error {
+	key := plaintextKey(name)
+	if !viper.IsSet(key) {
+		return ErrSecretNotFound
+	}
+	return removeKeys([]string{key})
+}
+
+// plaintextKey returns the config file key of a secret name
+func plaintextKey(name string) string {
+	parts := strings.SplitN(name, "/", 2)
+	return profileKey(parts[0], parts[1])
+}
@@ -0,0 +1,279 @@
+package config
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/zalando/go-keyring"
+)
+
+func TestNewSecretStore(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		ErrorNil bool
+	}{
+		{Name: SecretStoreKeyring, ErrorNil: true},
+		{Name: SecretStoreFile, ErrorNil: true},
+		{Name: SecretStorePlaintext, ErrorNil: true},
+		{Name: "unknown", ErrorNil: false},
+	}
+
+	for _, tc := range testCases {
+		// act
+		store, err := NewSecretStore(tc.Name)
+
+		// assert
+		if tc.ErrorNil {
+			assert.NoError(t, err, "error found for "+tc.Name)
+			assert.Equal(t, tc.Name, store.Name())
+		} else {
+			assert.Error(t, err, "error not found for "+tc.Name)
+		}
+	}
+}
+
+func TestSecretStores(t *testing.T) {
+	keyring.MockInit()
+	fileName := CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+
+	fileStore := NewEncryptedFileStore(
+		filepath.Join(t.TempDir(), "secrets.age"),
+		"passphrase")
+	fileStore.WorkFactor = 10
+
+	stores := []SecretStore{
+		&KeyringStore{Service: keyringService},
+		fileStore,
+		&PlaintextStore{},
+	}
+
+	for _, store := range stores {
+		// act & assert
+		_, err := store.Get("staging/client-secret")
+		assert.ErrorIs(t, err, ErrSecretNotFound, "secret found in "+store.Name())
+
+		err = store.Set("staging/client-secret", "secret")
+		assert.NoError(t, err, "error setting secret in "+store.Name())
+
+		value, err := store.Get("staging/client-secret")
+		assert.NoError(t, err, "error getting secret from "+store.Name())
+		assert.Equal(t, "secret", value)
+
+		err = store.Delete("staging/client-secret")
+		assert.NoError(t, err, "error deleting secret from "+store.Name())
+
+		err = store.Delete("staging/client-secret")
+		assert.ErrorIs(t, err, ErrSecretNotFound, "secret deleted twice in "+store.Name())
+	}
+}
+
+func TestEncryptedFileStorePersistsEncryptedSecrets(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "secrets.age")
+	store := NewEncryptedFileStore(path, "passphrase")
+	store.WorkFactor = 10
+
+	// act
+	err := store.Set("default/client-secret", "very-secret")
+
+	// assert
+	assert.NoError(t, err)
+	content, _ := os.ReadFile(path)
+	assert.NotContains(t, string(content), "very-secret")
+
+	value, err := NewEncryptedFileStore(path, "passphrase").Get("default/client-secret")
+	assert.NoError(t, err)
+	assert.Equal(t, "very-secret", value)
+
+	_, err = NewEncryptedFileStore(path, "wrong").Get("default/client-secret")
+	assert.Error(t, err)
+
+	_, err = NewEncryptedFileStore(path, "").Get("default/client-secret")
+	assert.Error(t, err)
+}
+
+func TestSetSecretRemovesSecretFromConfigFile(t *testing.T) {
+	// arrange
+	keyring.MockInit()
+	fileName := CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+	viper.ReadInConfig()
+	Set(SecretStoreFlag, SecretStoreKeyring)
+
+	// act
+	err := SetSecret(ClientSecretFlag, "keyring_secret")
+
+	// assert
+	assert.NoError(t, err)
+	assert.False(t, viper.InConfig("profiles.default.client-secret"))
+	value, source := Lookup(ClientSecretFlag)
+	assert.Equal(t, "keyring_secret", value)
+	assert.Equal(t, SourceSecret, source)
+}
+
+func TestDeleteSecretFlatConfig(t *testing.T) {
+	// arrange
+	keyring.MockInit()
+	fileName := filepath.Join(t.TempDir(), "config.yaml")
+	err := ioutil.WriteFile(fileName, []byte("client-id: flat_client_id\n"+
+		"client-secret: flat_client_secret\n"+
+		"secret-store: keyring\n"), 0600)
+	assert.NoError(t, err)
+	viper.Reset()
+	defer viper.Reset()
+	viper.SetConfigFile(fileName)
+	viper.ReadInConfig()
+	err = keyring.Set(keyringService, secretName(DefaultProfile, ClientSecretFlag), "keyring_client_secret")
+	assert.NoError(t, err)
+
+	// act
+	err = DeleteSecret(ClientSecretFlag)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Empty(t, GetString(ClientSecretFlag), "The client secret must be removed")
+	_, err = keyring.Get(keyringService, secretName(DefaultProfile, ClientSecretFlag))
+	assert.ErrorIs(t, err, keyring.ErrNotFound, "The client secret must be removed from the keyring")
+	content, _ := ioutil.ReadFile(fileName)
+	assert.Equal(t, "client-id: flat_client_id\nsecret-store: keyring\n", string(content))
+}
+
+func TestDeleteSecretDefinedInEnvironment(t *testing.T) {
+	// arrange
+	fileName := CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+	t.Setenv(EnvVar(ClientSecretFlag), "env_client_secret")
+
+	// act
+	err := DeleteSecret(ClientSecretFlag)
+
+	// assert
+	assert.EqualError(t, err, `client-secret of profile "default" is still defined by the `+
+		`LEARNING_GO_CLI_CLIENT_SECRET environment variable`)
+	assert.False(t, viper.IsSet(profileKey(DefaultProfile, ClientSecretFlag)),
+		"The client secret must be removed from the config file")
+}
+
+func TestMigrateSecrets(t *testing.T) {
+	// arrange
+	keyring.MockInit()
+	fileName := CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+	t.Setenv(ProfileEnvVar, "staging")
+	Set(ClientSecretFlag, "staging_secret")
+	t.Setenv(ProfileEnvVar, "")
+	viper.WriteConfig()
+	viper.ReadInConfig()
+
+	store := &KeyringStore{Service: keyringService}
+
+	// act
+	migrated, err := MigrateSecrets(store)
+
+	// assert
+	assert.NoError(t, err)
+	assert.ElementsMatch(t,
+		[]string{"default/client-secret", "staging/client-secret"},
+		migrated)
+	assert.Equal(t, SecretStoreKeyring, GetString(SecretStoreFlag))
+	assert.False(t, viper.InConfig("profiles.default.client-secret"))
+	assert.False(t, viper.InConfig("profiles.staging.client-secret"))
+	assert.Equal(t, "fake_client_secret", GetString(ClientSecretFlag))
+
+	t.Setenv(ProfileEnvVar, "staging")
+	assert.Equal(t, "staging_secret", GetString(ClientSecretFlag))
+}
+
+// unavailableKeyring fails like the keyring of a server without the Secret
+// Service
+type unavailableKeyring struct{}
+
+func (unavailableKeyring) Get(service, user string) (string, error) {
+	return "", errors.New("The name org.freedesktop.secrets was not provided by any .service files")
+}
+
+func (unavailableKeyring) Set(service, user, password string) error {
+	return errors.New("The name org.freedesktop.secrets was not provided by any .service files")
+}
+
+func (unavailableKeyring) Delete(service, user string) error {
+	return errors.New("The name org.freedesktop.secrets was not provided by any .service files")
+}
+
+// withUnavailableKeyring replaces the keyring with one that cannot be used
+func withUnavailableKeyring(t *testing.T) {
+	original := keyringBackend
+	keyringBackend = unavailableKeyring{}
+	t.Cleanup(func() { keyringBackend = original })
+}
+
+func TestKeyringStoreUnavailable(t *testing.T) {
+	// arrange
+	withUnavailableKeyring(t)
+	store := &KeyringStore{Service: keyringService}
+
+	// act
+	_, getErr := store.Get("default/client-secret")
+	setErr := store.Set("default/client-secret", "secret")
+	deleteErr := store.Delete("default/client-secret")
+
+	// assert
+	for _, err := range []error{getErr, setErr, deleteErr} {
+		unavailable := &SecretStoreUnavailableError{}
+		assert.ErrorAs(t, err, &unavailable)
+		assert.Equal(t, SecretStoreKeyring, unavailable.Store)
+		assert.Contains(t, err.Error(), "org.freedesktop.secrets")
+		assert.Contains(t, err.Error(), "--secret-store file")
+		assert.Contains(t, err.Error(), "--secret-store plaintext")
+	}
+}
+
+func TestWriteAuthenticationConfigWithUnavailableKeyring(t *testing.T) {
+	// arrange
+	withUnavailableKeyring(t)
+	fileName := filepath.Join(t.TempDir(), "config.yaml")
+	viper.Reset()
+	defer viper.Reset()
+	viper.SetConfigFile(fileName)
+
+	// act
+	err := WriteAuthenticationConfig("client_id", "secret", "https://api.example.com", "https://auth.example.com")
+
+	// assert
+	unavailable := &SecretStoreUnavailableError{}
+	assert.ErrorAs(t, err, &unavailable)
+	_, statErr := os.Stat(fileName)
+	assert.True(t, os.IsNotExist(statErr), "the config file must not be written")
+}
+
+func TestConfigPreCheckWithUnavailableKeyring(t *testing.T) {
+	// arrange
+	withUnavailableKeyring(t)
+	viper.Reset()
+	defer viper.Reset()
+	viper.SetConfigFile(filepath.Join(t.TempDir(), "config.yaml"))
+
+	// act
+	notConfigured := ConfigPreCheck(&cobra.Command{}, []string{})
+	Set(ClientIdFlag, "client_id")
+	Set(APIEndpointFlag, "https://api.example.com")
+	Set(TokenEndpointFlag, "https://auth.example.com")
+	Write()
+	viper.ReadInConfig()
+	configured := ConfigPreCheck(&cobra.Command{}, []string{})
+
+	// assert
+	assert.EqualError(t, notConfigured, `invalid CLI configuration for profile "default": `+
+		"please run `learning-go-cli configure --profile default`",
+		"profiles not configured must not read the secret store")
+	invalidConfig := &InvalidConfigError{}
+	assert.ErrorAs(t, configured, &invalidConfig)
+	assert.Contains(t, configured.Error(), "--secret-store file")
+}
@@ -0,0 +1,474 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+)
+
+// Source identifies where the value of a setting comes from
+type Source string
+
+// Sources of setting values, from the highest to the lowest precedence.
+// Secrets are read from the secret store before the config file.
+const (
+	SourceFlag    Source = "flag"
+	SourceEnv     Source = "env"
+	SourceFile    Source = "file"
+	SourceSecret  Source = "secret-store"
+	SourceDefault Source = "default"
+)
+
+// Types of setting values, defining the type of their flags
+const (
+	TypeString   string = "string"
+	TypeBool     string = "bool"
+	TypeInt      string = "int"
+	TypeDuration string = "duration"
+)
+
+// Setting describes a configuration key known by the CLI. Settings with Flag
+// set can also be overridden with a persistent root flag of the given Type,
+// a string if empty. Secrets never have flags as they would be visible in
+// the shell history and the process list.
+type Setting struct {
+	Key         string
+	Shorthand   string
+	Description string
+	Default     string
+	Type        string
+	Flag        bool
+	PerProfile  bool
+	Required    bool
+	RequiredBy  string
+	Secret      bool
+	Validate    func(value string) error
+}
+
+// Settings are all the configuration keys known by the CLI
+var Settings = []Setting{
+	{
+		Key:         ClientIdFlag,
+		Flag:        true,
+		Description: "the client id to call the API",
+		PerProfile:  true,
+		Required:    true,
+	},
+	{
+		Key:         ClientSecretFlag,
+		Description: "the client secret to call the API",
+		PerProfile:  true,
+		RequiredBy:  AuthMethodClientSecret,
+		Secret:      true,
+	},
+	{
+		Key:         APIEndpointFlag,
+		Flag:        true,
+		Description: "the API endpoint",
+		PerProfile:  true,
+		Required:    true,
+		Validate:    validateURL,
+	},
+	{
+		Key:         TokenEndpointFlag,
+		Flag:        true,
+		Description: "the endpoint to get authentication tokens",
+		PerProfile:  true,
+		Required:    true,
+		Validate:    validateURL,
+	},
+	{
+		Key:         AuthMethodFlag,
+		Description: "how the client authenticates to get tokens: client_secret_basic, private_key_jwt, tls_client_auth or none",
+		Default:     AuthMethodClientSecret,
+		PerProfile:  true,
+		Validate:    validateAuthMethod,
+	},
+	{
+		Key:         PrivateKeyFlag,
+		Description: "the PEM file with the RSA or EC key signing the client assertions of private_key_jwt",
+		PerProfile:  true,
+		RequiredBy:  AuthMethodPrivateKeyJwt,
+	},
+	{
+		Key:         PrivateKeyIdFlag,
+		Description: "the key id of the client assertions, when required by the token endpoint",
+		PerProfile:  true,
+	},
+	{
+		Key:         ClientCertFlag,
+		Description: "the PEM file with the client certificate of tls_client_auth",
+		PerProfile:  true,
+		RequiredBy:  AuthMethodTLSClientAuth,
+	},
+	{
+		Key:         ClientKeyFlag,
+		Description: "the PEM file with the private key of the client certificate",
+		PerProfile:  true,
+		RequiredBy:  AuthMethodTLSClientAuth,
+	},
+	{
+		Key:         CACertFlag,
+		Description: "the PEM file with the CA certificates trusted for the token endpoint, the system ones if empty",
+		PerProfile:  true,
+	},
+	{
+		Key:         ScopesFlag,
+		Description: "the space separated scopes requested for the access tokens",
+		PerProfile:  true,
+	},
+	{
+		Key:         AudienceFlag,
+		Description: "the audience requested for the access tokens, when required by the token endpoint",
+		PerProfile:  true,
+	},
+	{
+		Key:         ResourceFlag,
+		Description: "the resource indicator (RFC 8707) requested for the access tokens",
+		PerProfile:  true,
+		Validate:    validateURL,
+	},
+	{
+		Key:         DeviceEndpointFlag,
+		Description: "the endpoint starting the device logins of auth login --device",
+		PerProfile:  true,
+		Validate:    validateURL,
+	},
+	{
+		Key:         AuthorizationEndpointFlag,
+		Description: "the endpoint where users log in with auth login --web",
+		PerProfile:  true,
+		Validate:    validateURL,
+	},
+	{
+		Key:         RedirectPortFlag,
+		Type:        TypeInt,
+		Description: "the local port receiving the logins of auth login --web, a random one if 0",
+		Default:     "0",
+		PerProfile:  true,
+		Validate:    validatePort,
+	},
+	{
+		Key:         RefreshTokenFlag,
+		Description: "the refresh token of the interactive logins, set by auth login",
+		PerProfile:  true,
+		Secret:      true,
+	},
+	{
+		Key:         IntrospectionEndpointFlag,
+		Description: "the token introspection endpoint (RFC 7662) used by auth inspect --introspect",
+		PerProfile:  true,
+		Validate:    validateURL,
+	},
+	{
+		Key:         DefaultProfileFlag,
+		Description: "the profile used when none is selected",
+		Default:     DefaultProfile,
+		Validate:    ValidateProfileName,
+	},
+	{
+		Key:         SecretStoreFlag,
+		Description: "where secrets are stored: keyring, file or plaintext",
+		Default:     SecretStoreKeyring,
+		Validate:    validateSecretStore,
+	},
+	{
+		Key:         TokenSkewFlag,
+		Type:        TypeDuration,
+		Description: "the margin before expiration to consider tokens expired, 0 for none",
+		Default:     "30s",
+		Validate:    validateDuration,
+	},
+	{
+		Key:         AuthHeaderFlag,
+		Description: "the header sending the access token to the API",
+		Default:     DefaultAuthHeader,
+		PerProfile:  true,
+		Validate:    validateHeaderName,
+	},
+	{
+		Key:         AuthSchemeFlag,
+		Description: "the scheme before the access token, the token type if empty or none to send the token alone",
+		PerProfile:  true,
+		Validate:    validateAuthScheme,
+	},
+	{
+		Key:         OutputFlag,
+		Shorthand:   "o",
+		Flag:        true,
+		Description: "the output format: json, yaml, table, value or go-template=...",
+		Default:     OutputJSON,
+		Validate:    validateOutputFormat,
+	},
+	{
+		Key:         TimeoutFlag,
+		Type:        TypeDuration,
+		Flag:        true,
+		Description: "the maximum duration of each request, including retries, 0 for none",
+		Default:     "30s",
+		Validate:    validateDuration,
+	},
+	{
+		Key:         RetryMaxAttemptsFlag,
+		Type:        TypeInt,
+		Description: "the maximum number of attempts of failed requests",
+		Default:     "3",
+		Validate:    validatePositiveInt,
+	},
+	{
+		Key:         RetryBaseDelayFlag,
+		Type:        TypeDuration,
+		Description: "the delay before the first retry, doubled for each retry",
+		Default:     "200ms",
+		Validate:    validateDuration,
+	},
+	{
+		Key:         RetryMaxDelayFlag,
+		Type:        TypeDuration,
+		Description: "the maximum delay between retries",
+		Default:     "5s",
+		Validate:    validateDuration,
+	},
+	{
+		Key:         RetryStatusCodesFlag,
+		Description: "the comma separated HTTP status codes to retry",
+		Default:     "429,502,503,504",
+		Validate:    validateStatusCodes,
+	},
+	{
+		Key:         LocalUuidFlag,
+		Type:        TypeBool,
+		Description: "if true UUIDs are generated locally instead of calling the API",
+		Default:     "false",
+		Validate:    validateBool,
+	},
+}
+
+// IsRequired checks if the setting must be defined, either always or for
+// the configured authentication method
+func (s Setting) IsRequired() bool {
+	if s.Required {
+		return true
+	}
+	return s.RequiredBy != "" && s.RequiredBy == GetString(AuthMethodFlag)
+}
+
+// FindSetting returns the setting with the given key
+func FindSetting(key string) (Setting, bool) {
+	for _, setting := range Settings {
+		if setting.Key == key {
+			return setting, true
+		}
+	}
+	return Setting{}, false
+}
+
+// isProfileKey checks if a key is stored per profile
+func isProfileKey(key string) bool {
+	setting, found := FindSetting(key)
+	return found && setting.PerProfile
+}
+
+// Lookup returns the value of a setting and where it comes from, following
+// the precedence: flag, environment variable, config file and default
+func Lookup(key string) (interface{}, Source) {
+	if flag, found := boundFlags[key]; found && flag.Changed {
+		return flag.Value.String(), SourceFlag
+	}
+	if value := os.Getenv(EnvVar(key)); value != "" {
+		return value, SourceEnv
+	}
+
+	setting, found := FindSetting(key)
+	if found && setting.Secret {
+		// secrets still in the config file are read from it below
+		value, err := lookupSecret(key)
+		if err == nil {
+			return value, secretSource()
+		}
+	}
+	if fullKey := resolveKey(key); viper.IsSet(fullKey) {
+		return viper.Get(fullKey), SourceFile
+	}
+
+	if found && setting.Default != "" {
+		return setting.Default, SourceDefault
+	}
+	return nil, SourceDefault
+}
+
+// secretSource returns the source of values read from the current secret
+// store
+func secretSource() Source {
+	if GetString(SecretStoreFlag) == SecretStorePlaintext {
+		return SourceFile
+	}
+	return SourceSecret
+}
+
+// tokenRegexp matches the tokens of HTTP, used in header names and
+// authentication schemes
+var tokenRegexp = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+
+// Validate checks the configuration of the selected profile, returning all
+// the problems found
+func Validate() []error {
+	problems := []error{}
+
+	for _, setting := range Settings {
+		value, _ := Lookup(setting.Key)
+		stringValue := cast.ToString(value)
+		if stringValue == "" {
+			if setting.IsRequired() {
+				problems = append(problems,
+					fmt.Errorf("%s is not defined for profile %q",
+						setting.Key,
+						Profile()))
+			}
+			continue
+		}
+
+		if setting.Validate != nil {
+			err := setting.Validate(stringValue)
+			if err != nil {
+				problems = append(problems,
+					fmt.Errorf("%s is invalid: %w", setting.Key, err))
+			}
+		}
+	}
+
+	for _, key := range unknownKeys() {
+		problems = append(problems, fmt.Errorf("%s is not a known setting", key))
+	}
+
+	return problems
+}
+
+// unknownKeys returns the keys in the configuration which are not known
+// settings, considering the keys stored inside profiles
+func unknownKeys() []string {
+	unknown := []string{}
+	for _, key := range viper.AllKeys() {
+		parts := strings.Split(key, viperKeyDelimiter)
+		if len(parts) == 3 && parts[0] == profilesKey {
+			if !isProfileKey(parts[2]) {
+				unknown = append(unknown, key)
+			}
+			continue
+		}
+
+		_, found := FindSetting(key)
+		if !found {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// validateURL checks if a value is an absolute URL
+func validateURL(value string) error {
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return fmt.Errorf("%q is not an absolute URL", value)
+	}
+	return nil
+}
+
+// validateAuthMethod checks if a value is a supported client authentication
+// method
+func validateAuthMethod(value string) error {
+	switch value {
+	case AuthMethodClientSecret, AuthMethodPrivateKeyJwt, AuthMethodTLSClientAuth, AuthMethodNone:
+		return nil
+	}
+	return fmt.Errorf("%q is not an authentication method, valid methods are: "+
+		"client_secret_basic, private_key_jwt, tls_client_auth or none", value)
+}
+
+// validateHeaderName checks if a value is a valid HTTP header name
+func validateHeaderName(value string) error {
+	if !tokenRegexp.MatchString(value) {
+		return fmt.Errorf("%q is not an HTTP header name", value)
+	}
+	return nil
+}
+
+// validateAuthScheme checks if a value is an authentication scheme, like
+// Bearer, or none
+func validateAuthScheme(value string) error {
+	if !tokenRegexp.MatchString(value) {
+		return fmt.Errorf("%q is not an authentication scheme", value)
+	}
+	return nil
+}
+
+// validatePositiveInt checks if a value is an integer greater than zero
+func validatePositiveInt(value string) error {
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		return fmt.Errorf("%q is not a positive integer", value)
+	}
+	return nil
+}
+
+// validatePort checks if a value is a TCP port, or 0 for a random one
+func validatePort(value string) error {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 0 || port > 65535 {
+		return fmt.Errorf("%q is not a port", value)
+	}
+	return nil
+}
+
+// validateStatusCodes checks if a value is a comma separated list of HTTP
+// status codes
+func validateStatusCodes(value string) error {
+	for _, part := range strings.Split(value, ",") {
+		code, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || code < 100 || code > 599 {
+			return fmt.Errorf("%q is not an HTTP status code", part)
+		}
+	}
+	return nil
+}
+
+// validateBool checks if a value is true or false
+func validateBool(value string) error {
+	_, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("%q is not true or false", value)
+	}
+	return nil
+}
+
+// validateOutputFormat checks if a value is a known output format or a Go
+// template
+func validateOutputFormat(value string) error {
+	switch value {
+	case OutputJSON, OutputYAML, OutputTable, OutputValue:
+		return nil
+	}
+	if strings.HasPrefix(value, OutputGoTemplatePrefix) {
+		return nil
+	}
+	return fmt.Errorf("%q is not an output format, valid formats are: "+
+		"json, yaml, table, value or go-template=...", value)
+}
+
+// validateDuration checks if a value is a duration like 30s or 1m
+func validateDuration(value string) error {
+	_, err := time.ParseDuration(value)
+	return err
+}
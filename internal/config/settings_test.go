@@ -0,0 +1,143 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindSetting(t *testing.T) {
+	// act
+	setting, found := FindSetting(ClientSecretFlag)
+	_, unknownFound := FindSetting("unknown")
+
+	// assert
+	assert.True(t, found)
+	assert.True(t, setting.Secret)
+	assert.False(t, unknownFound)
+}
+
+func TestLookup(t *testing.T) {
+	// arrange
+	viper.Reset()
+	defer viper.Reset()
+	Set(ClientIdFlag, "client_id")
+
+	// act
+	clientId, clientIdSource := Lookup(ClientIdFlag)
+	skew, skewSource := Lookup(TokenSkewFlag)
+	unknown, _ := Lookup("unknown")
+
+	// assert
+	assert.Equal(t, "client_id", clientId)
+	assert.Equal(t, SourceFile, clientIdSource)
+	assert.Equal(t, "30s", skew)
+	assert.Equal(t, SourceDefault, skewSource)
+	assert.Nil(t, unknown)
+}
+
+func TestValidate(t *testing.T) {
+	// arrange
+	fileName := CreateFakeConfigFile(t)
+	defer os.Remove(fileName)
+	Set(APIEndpointFlag, "https://api.example.com")
+	Set(TokenEndpointFlag, "https://auth.example.com/token")
+
+	// act & assert
+	assert.Empty(t, Validate())
+
+	Set(TokenEndpointFlag, "fake_endpoint")
+	Set("profiles.default.unknown", "value")
+	Set("unknown", "value")
+	assert.Len(t, Validate(), 3)
+}
+
+func TestValidateURL(t *testing.T) {
+	assert.NoError(t, validateURL("https://api.example.com/v1"))
+	assert.Error(t, validateURL("api.example.com"))
+	assert.Error(t, validateURL("%%"))
+}
+
+func TestValidateBool(t *testing.T) {
+	assert.NoError(t, validateBool("true"))
+	assert.NoError(t, validateBool("false"))
+	assert.Error(t, validateBool("yes please"))
+}
+
+func TestIsRequired(t *testing.T) {
+	// arrange
+	viper.Reset()
+	defer viper.Reset()
+	clientSecret, _ := FindSetting(ClientSecretFlag)
+	privateKey, _ := FindSetting(PrivateKeyFlag)
+	clientId, _ := FindSetting(ClientIdFlag)
+
+	// act & assert
+	assert.True(t, clientSecret.IsRequired())
+	assert.False(t, privateKey.IsRequired())
+
+	Set(AuthMethodFlag, AuthMethodPrivateKeyJwt)
+	assert.False(t, clientSecret.IsRequired())
+	assert.True(t, privateKey.IsRequired())
+	assert.True(t, clientId.IsRequired())
+}
+
+func TestValidateAuthMethod(t *testing.T) {
+	assert.NoError(t, validateAuthMethod(AuthMethodClientSecret))
+	assert.NoError(t, validateAuthMethod(AuthMethodPrivateKeyJwt))
+	assert.NoError(t, validateAuthMethod(AuthMethodTLSClientAuth))
+	assert.NoError(t, validateAuthMethod(AuthMethodNone))
+	assert.Error(t, validateAuthMethod("client_secret_post"))
+}
+
+func TestValidatePort(t *testing.T) {
+	assert.NoError(t, validatePort("0"))
+	assert.NoError(t, validatePort("8085"))
+	assert.Error(t, validatePort("65536"))
+	assert.Error(t, validatePort("http"))
+}
+
+func TestValidateHeaderName(t *testing.T) {
+	assert.NoError(t, validateHeaderName("Authorization"))
+	assert.NoError(t, validateHeaderName("X-Api-Token"))
+	assert.Error(t, validateHeaderName("X Api Token"))
+	assert.Error(t, validateHeaderName("X-Api-Token:"))
+}
+
+func TestValidateAuthScheme(t *testing.T) {
+	assert.NoError(t, validateAuthScheme("Bearer"))
+	assert.NoError(t, validateAuthScheme(AuthSchemeNone))
+	assert.Error(t, validateAuthScheme("Bearer token"))
+}
+
+func TestLookupPrecedence(t *testing.T) {
+	// arrange
+	viper.Reset()
+	defer viper.Reset()
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	AddFlags(flags)
+	defer func() { boundFlags = map[string]*pflag.Flag{} }()
+
+	// act & assert
+	value, source := Lookup(APIEndpointFlag)
+	assert.Nil(t, value)
+	assert.Equal(t, SourceDefault, source)
+
+	Set(APIEndpointFlag, "https://file.example.com")
+	value, source = Lookup(APIEndpointFlag)
+	assert.Equal(t, "https://file.example.com", value)
+	assert.Equal(t, SourceFile, source)
+
+	t.Setenv("LEARNING_GO_CLI_API_ENDPOINT", "https://env.example.com")
+	value, source = Lookup(APIEndpointFlag)
+	assert.Equal(t, "https://env.example.com", value)
+	assert.Equal(t, SourceEnv, source)
+
+	flags.Parse([]string{"--api-endpoint", "https://flag.example.com"})
+	value, source = Lookup(APIEndpointFlag)
+	assert.Equal(t, "https://flag.example.com", value)
+	assert.Equal(t, SourceFlag, source)
+}
@@ -0,0 +1,122 @@
+package decimal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxExponent limits the exponent of parsed numbers, so values like 1e999999
+// cannot allocate huge amounts of memory
+const maxExponent int = 1000
+
+// pattern matches decimal numbers, with an optional exponent like JSON
+// numbers
+var pattern = regexp.MustCompile(`^([+-]?)(\d*)(?:\.(\d*))?(?:[eE]([+-]?\d+))?$`)
+
+// Decimal is an exact decimal number, used for amounts of money which cannot
+// be represented exactly as float64. The number of decimal places is kept, so
+// 12.50 is not shown as 12.5.
+type Decimal struct {
+	value *big.Rat
+	scale int
+}
+
+// Parse reads a decimal number like 12.5, -0.01 or 1.5e3
+func Parse(text string) (Decimal, error) {
+	match := pattern.FindStringSubmatch(strings.TrimSpace(text))
+	if match == nil || match[2]+match[3] == "" {
+		return Decimal{}, fmt.Errorf("%q is not a decimal number", text)
+	}
+
+	exponent := 0
+	if match[4] != "" {
+		var err error
+		exponent, err = strconv.Atoi(match[4])
+		if err != nil || exponent > maxExponent || exponent < -maxExponent {
+			return Decimal{}, fmt.Errorf("the exponent of %q is out of range", text)
+		}
+	}
+
+	value, ok := new(big.Rat).SetString(match[1] + zeroIfEmpty(match[2]) + "." + zeroIfEmpty(match[3]))
+	if !ok {
+		return Decimal{}, fmt.Errorf("%q is not a decimal number", text)
+	}
+	scale := len(match[3]) - exponent
+	if exponent != 0 {
+		power := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(exponent))), nil))
+		if exponent > 0 {
+			value.Mul(value, power)
+		} else {
+			value.Quo(value, power)
+		}
+	}
+	if scale < 0 {
+		scale = 0
+	}
+	return Decimal{value: value, scale: scale}, nil
+}
+
+// zeroIfEmpty returns "0" for empty digits, like the integer part of .5
+func zeroIfEmpty(digits string) string {
+	if digits == "" {
+		return "0"
+	}
+	return digits
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// rat returns the value as a rational number, zero for the zero Decimal
+func (d Decimal) rat() *big.Rat {
+	if d.value == nil {
+		return new(big.Rat)
+	}
+	return d.value
+}
+
+// Sign returns -1, 0 or 1 when the number is negative, zero or positive
+func (d Decimal) Sign() int {
+	return d.rat().Sign()
+}
+
+// String returns the number with its decimal places, like 12.50
+func (d Decimal) String() string {
+	return d.rat().FloatString(d.scale)
+}
+
+// MarshalJSON writes the number as a JSON number, without rounding
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON reads the number from a JSON number or string, without
+// converting it to float64
+func (d *Decimal) UnmarshalJSON(content []byte) error {
+	content = bytes.TrimSpace(content)
+	if bytes.HasPrefix(content, []byte(`"`)) {
+		var text string
+		err := json.Unmarshal(content, &text)
+		if err != nil {
+			return err
+		}
+		content = []byte(text)
+	}
+
+	parsed, err := Parse(string(content))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
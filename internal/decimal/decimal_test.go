@@ -0,0 +1,100 @@
+package decimal
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		Purpose  string
+		Text     string
+		Expected string
+		Sign     int
+	}{
+		{Purpose: "integer", Text: "12", Expected: "12", Sign: 1},
+		{Purpose: "decimal places are kept", Text: "12.50", Expected: "12.50", Sign: 1},
+		{Purpose: "no integer part", Text: ".5", Expected: "0.5", Sign: 1},
+		{Purpose: "negative", Text: "-0.01", Expected: "-0.01", Sign: -1},
+		{Purpose: "zero", Text: "0.00", Expected: "0.00", Sign: 0},
+		{Purpose: "exponent", Text: "1.5e3", Expected: "1500", Sign: 1},
+		{Purpose: "negative exponent", Text: "15E-4", Expected: "0.0015", Sign: 1},
+		{
+			Purpose:  "more digits than float64",
+			Text:     "12345678901234567890.123456789",
+			Expected: "12345678901234567890.123456789",
+			Sign:     1,
+		},
+	}
+
+	for _, tc := range testCases {
+		// act
+		d, err := Parse(tc.Text)
+
+		// assert
+		assert.NoError(t, err, tc.Purpose)
+		assert.Equal(t, tc.Expected, d.String(), tc.Purpose)
+		assert.Equal(t, tc.Sign, d.Sign(), tc.Purpose)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	testCases := []struct {
+		Purpose string
+		Text    string
+		Error   string
+	}{
+		{Purpose: "empty", Text: "", Error: `"" is not a decimal number`},
+		{Purpose: "only a dot", Text: ".", Error: `"." is not a decimal number`},
+		{Purpose: "comma", Text: "12,5", Error: `"12,5" is not a decimal number`},
+		{Purpose: "not a number", Text: "NaN", Error: `"NaN" is not a decimal number`},
+		{Purpose: "exponent too large", Text: "1e999999", Error: `the exponent of "1e999999" is out of range`},
+	}
+
+	for _, tc := range testCases {
+		// act
+		_, err := Parse(tc.Text)
+
+		// assert
+		assert.EqualError(t, err, tc.Error, tc.Purpose)
+	}
+}
+
+func TestZeroValue(t *testing.T) {
+	// arrange
+	d := Decimal{}
+
+	// act & assert
+	assert.Equal(t, "0", d.String())
+	assert.Equal(t, 0, d.Sign())
+}
+
+func TestJSON(t *testing.T) {
+	// arrange
+	value := struct {
+		Number Decimal `json:"number"`
+		String Decimal `json:"string"`
+	}{}
+
+	// act
+	err := json.Unmarshal([]byte(`{"number": 0.10000000000000000001, "string": "13.540"}`), &value)
+	assert.NoError(t, err)
+	content, marshalErr := json.Marshal(value)
+
+	// assert
+	assert.NoError(t, marshalErr)
+	assert.Equal(t, `{"number":0.10000000000000000001,"string":13.540}`, string(content))
+}
+
+func TestUnmarshalJSONInvalid(t *testing.T) {
+	// arrange
+	d := Decimal{}
+
+	// act
+	err := json.Unmarshal([]byte(`"twelve"`), &d)
+
+	// assert
+	assert.EqualError(t, err, `"twelve" is not a decimal number`)
+}
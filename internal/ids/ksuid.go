@@ -0,0 +1,40 @@
+package ids
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// base62Alphabet is the alphabet used by KSUIDs
+const base62Alphabet string = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// ksuidLength is the number of characters of an encoded KSUID
+const ksuidLength int = 27
+
+// ksuidEpoch is the Unix timestamp where KSUID timestamps start, to extend
+// their lifetime
+const ksuidEpoch int64 = 1400000000
+
+// KSUID is a K-Sortable Unique IDentifier, as defined in
+// https://github.com/segmentio/ksuid: a timestamp in seconds followed by 128
+// random bits
+type KSUID [20]byte
+
+// NewKSUID generates a KSUID with the current time
+func NewKSUID() (KSUID, error) {
+	ksuid := KSUID{}
+	_, err := io.ReadFull(randReader, ksuid[4:])
+	if err != nil {
+		return ksuid, fmt.Errorf("error generating random bytes: %w", err)
+	}
+
+	binary.BigEndian.PutUint32(ksuid[0:4], uint32(now().Unix()-ksuidEpoch))
+	return ksuid, nil
+}
+
+// String returns the KSUID encoded in base 62, like
+// 0ujtsYcgvSTl8PAuAdqWYSMnLOv
+func (k KSUID) String() string {
+	return encode(k[:], base62Alphabet, ksuidLength)
+}
@@ -0,0 +1,53 @@
+package ids
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKSUIDString(t *testing.T) {
+	// arrange
+	ksuid := KSUID{}
+	content, _ := hex.DecodeString("0669F7EFB5A1CD34B5F99D1154FB6853345C9735")
+	copy(ksuid[:], content)
+
+	// act
+	text := ksuid.String()
+
+	// assert
+	assert.Equal(t, "0ujtsYcgvSTl8PAuAdqWYSMnLOv", text)
+}
+
+func TestNewKSUID(t *testing.T) {
+	// arrange
+	now = func() time.Time { return time.Unix(ksuidEpoch+1, 0) }
+	randReader = bytes.NewReader(make([]byte, 16))
+	defer func() {
+		now = time.Now
+		randReader = defaultRandReader
+	}()
+
+	// act
+	ksuid, err := NewKSUID()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, KSUID{0, 0, 0, 1}, ksuid)
+	assert.Len(t, ksuid.String(), ksuidLength)
+}
+
+func TestNewKSUIDRandomnessFailure(t *testing.T) {
+	// arrange
+	randReader = &failingReader{}
+	defer func() { randReader = defaultRandReader }()
+
+	// act
+	_, err := NewKSUID()
+
+	// assert
+	assert.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package ids
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+)
+
+// crockfordAlphabet is the Crockford's Base32 alphabet used by ULIDs, without
+// the letters I, L, O and U
+const crockfordAlphabet string = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ulidLength is the number of characters of an encoded ULID
+const ulidLength int = 26
+
+// ULID is a Universally Unique Lexicographically Sortable Identifier, as
+// defined in https://github.com/ulid/spec: a timestamp in milliseconds
+// followed by random bits
+type ULID [16]byte
+
+// NewULID generates a ULID with the current time
+func NewULID() (ULID, error) {
+	ulid := ULID{}
+	_, err := io.ReadFull(randReader, ulid[6:])
+	if err != nil {
+		return ulid, fmt.Errorf("error generating random bytes: %w", err)
+	}
+
+	milliseconds := uint64(now().UnixNano() / int64(time.Millisecond))
+	ulid[0] = byte(milliseconds >> 40)
+	ulid[1] = byte(milliseconds >> 32)
+	binary.BigEndian.PutUint32(ulid[2:6], uint32(milliseconds))
+	return ulid, nil
+}
+
+// String returns the ULID encoded in Crockford's Base32, like
+// 01ARZ3NDEKTSV4RRFFQ69G5FAV
+func (u ULID) String() string {
+	return encode(u[:], crockfordAlphabet, ulidLength)
+}
+
+// encode writes the bytes, as a big-endian number, in the base of the
+// alphabet, padded with the first character of the alphabet to the length
+func encode(content []byte, alphabet string, length int) string {
+	number := new(big.Int).SetBytes(content)
+	base := big.NewInt(int64(len(alphabet)))
+	remainder := new(big.Int)
+
+	encoded := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		number.DivMod(number, base, remainder)
+		encoded[i] = alphabet[remainder.Int64()]
+	}
+	return string(encoded)
+}
@@ -0,0 +1,54 @@
+package ids
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewULID(t *testing.T) {
+	// arrange
+	now = func() time.Time { return time.UnixMilli(1469918176385) }
+	randReader = bytes.NewReader(make([]byte, 10))
+	defer func() {
+		now = time.Now
+		randReader = defaultRandReader
+	}()
+
+	// act
+	ulid, err := NewULID()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "01ARYZ6S410000000000000000", ulid.String())
+}
+
+func TestNewULIDIsSortable(t *testing.T) {
+	// arrange
+	first, err := NewULID()
+	assert.NoError(t, err)
+	now = func() time.Time { return time.Now().Add(time.Second) }
+	defer func() { now = time.Now }()
+
+	// act
+	second, err := NewULID()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Len(t, first.String(), ulidLength)
+	assert.Less(t, first.String(), second.String())
+}
+
+func TestNewULIDRandomnessFailure(t *testing.T) {
+	// arrange
+	randReader = &failingReader{}
+	defer func() { randReader = defaultRandReader }()
+
+	// act
+	_, err := NewULID()
+
+	// assert
+	assert.Error(t, err)
+}
@@ -0,0 +1,272 @@
+package ids
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultRandReader is the cryptographically secure source of randomness
+var defaultRandReader io.Reader = rand.Reader
+
+// randReader is the source of randomness, replaced in tests
+var randReader io.Reader = defaultRandReader
+
+// now returns the current time, replaced in tests
+var now = time.Now
+
+// UUID is a universally unique identifier as defined in RFC 4122
+type UUID [16]byte
+
+// Namespaces defined in RFC 4122 to generate name-based UUIDs
+var (
+	NamespaceDNS  = UUID{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	NamespaceURL  = UUID{0x6b, 0xa7, 0xb8, 0x11, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	NamespaceOID  = UUID{0x6b, 0xa7, 0xb8, 0x12, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	NamespaceX500 = UUID{0x6b, 0xa7, 0xb8, 0x14, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+)
+
+// gregorianOffset is the number of 100 nanoseconds intervals between the
+// start of the Gregorian calendar (1582-10-15), used by version 1 UUIDs, and
+// the Unix epoch
+const gregorianOffset int64 = 122192928000000000
+
+// v1State keeps the last timestamp, clock sequence and node of version 1
+// UUIDs, so UUIDs generated in the same interval are still unique
+var v1State = struct {
+	sync.Mutex
+	lastTimestamp int64
+	clockSequence uint16
+	node          []byte
+}{}
+
+// NewV1 generates a time-based UUID (version 1). A random node is used
+// instead of the MAC address, as allowed by RFC 4122, to avoid exposing it.
+func NewV1() (UUID, error) {
+	v1State.Lock()
+	defer v1State.Unlock()
+
+	uuid := UUID{}
+	if v1State.node == nil {
+		random := make([]byte, 8)
+		_, err := io.ReadFull(randReader, random)
+		if err != nil {
+			return uuid, fmt.Errorf("error generating random bytes: %w", err)
+		}
+		v1State.clockSequence = binary.BigEndian.Uint16(random[0:2]) & 0x3fff
+		v1State.node = random[2:8]
+		v1State.node[0] |= 0x01 // the multicast bit marks random nodes
+	}
+
+	timestamp := now().UnixNano()/100 + gregorianOffset
+	if timestamp <= v1State.lastTimestamp {
+		timestamp = v1State.lastTimestamp + 1
+	}
+	v1State.lastTimestamp = timestamp
+
+	binary.BigEndian.PutUint32(uuid[0:4], uint32(timestamp))
+	binary.BigEndian.PutUint16(uuid[4:6], uint16(timestamp>>32))
+	binary.BigEndian.PutUint16(uuid[6:8], uint16(timestamp>>48))
+	binary.BigEndian.PutUint16(uuid[8:10], v1State.clockSequence)
+	copy(uuid[10:16], v1State.node)
+
+	uuid.setVersion(1)
+	uuid.setVariant()
+	return uuid, nil
+}
+
+// NewV4 generates a random UUID (version 4)
+func NewV4() (UUID, error) {
+	uuid := UUID{}
+	_, err := io.ReadFull(randReader, uuid[:])
+	if err != nil {
+		return uuid, fmt.Errorf("error generating random bytes: %w", err)
+	}
+	uuid.setVersion(4)
+	uuid.setVariant()
+	return uuid, nil
+}
+
+// NewV5 generates a name-based UUID (version 5), always the same for the same
+// namespace and name
+func NewV5(namespace UUID, name string) UUID {
+	hash := sha1.New()
+	hash.Write(namespace[:])
+	hash.Write([]byte(name))
+
+	uuid := UUID{}
+	copy(uuid[:], hash.Sum(nil))
+	uuid.setVersion(5)
+	uuid.setVariant()
+	return uuid
+}
+
+// NewV7 generates a time-ordered UUID (version 7), starting with the Unix
+// timestamp in milliseconds followed by random bits
+func NewV7() (UUID, error) {
+	uuid := UUID{}
+	_, err := io.ReadFull(randReader, uuid[6:])
+	if err != nil {
+		return uuid, fmt.Errorf("error generating random bytes: %w", err)
+	}
+
+	milliseconds := uint64(now().UnixNano() / int64(time.Millisecond))
+	uuid[0] = byte(milliseconds >> 40)
+	uuid[1] = byte(milliseconds >> 32)
+	binary.BigEndian.PutUint32(uuid[2:6], uint32(milliseconds))
+
+	uuid.setVersion(7)
+	uuid.setVariant()
+	return uuid, nil
+}
+
+// Namespace returns the UUID of a namespace given its name (dns, url, oid or
+// x500) or its UUID
+func Namespace(value string) (UUID, error) {
+	switch strings.ToLower(value) {
+	case "dns":
+		return NamespaceDNS, nil
+	case "url":
+		return NamespaceURL, nil
+	case "oid":
+		return NamespaceOID, nil
+	case "x500":
+		return NamespaceX500, nil
+	}
+
+	uuid, err := Parse(value)
+	if err != nil {
+		return uuid, fmt.Errorf("%q is not a namespace: use dns, url, oid, x500 or an UUID", value)
+	}
+	return uuid, nil
+}
+
+// urnPrefix is the prefix of UUIDs in the URN form defined in RFC 4122
+const urnPrefix string = "urn:uuid:"
+
+// Parse reads an UUID in the canonical form, with hyphens, without hyphens,
+// between braces or as an URN, like da308fbd-cba9-485a-b4c1-6677aaa732a4,
+// da308fbdcba9485ab4c16677aaa732a4, {da308fbd-cba9-485a-b4c1-6677aaa732a4} or
+// urn:uuid:da308fbd-cba9-485a-b4c1-6677aaa732a4
+func Parse(value string) (UUID, error) {
+	uuid := UUID{}
+	text := value
+	switch {
+	case len(text) > len(urnPrefix) && strings.EqualFold(text[:len(urnPrefix)], urnPrefix):
+		text = text[len(urnPrefix):]
+	case strings.HasPrefix(text, "{") && strings.HasSuffix(text, "}"):
+		text = text[1 : len(text)-1]
+	}
+
+	if len(text) == 36 {
+		if text[8] != '-' || text[13] != '-' || text[18] != '-' || text[23] != '-' {
+			return uuid, fmt.Errorf("%q is not an UUID", value)
+		}
+		text = strings.ReplaceAll(text, "-", "")
+	}
+	if len(text) != 32 {
+		return uuid, fmt.Errorf("%q is not an UUID", value)
+	}
+
+	_, err := hex.Decode(uuid[:], []byte(text))
+	if err != nil {
+		return uuid, fmt.Errorf("%q is not an UUID", value)
+	}
+	return uuid, nil
+}
+
+// Version returns the version of the UUID, stored in the 4 most significant
+// bits of byte 6
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// Variants of UUIDs, defining their layout
+const (
+	VariantNCS       string = "NCS"
+	VariantRFC4122   string = "RFC 4122"
+	VariantMicrosoft string = "Microsoft"
+	VariantFuture    string = "Future"
+)
+
+// Variant returns the variant of the UUID, stored in the most significant
+// bits of byte 8
+func (u UUID) Variant() string {
+	switch {
+	case u[8]&0x80 == 0x00:
+		return VariantNCS
+	case u[8]&0xc0 == 0x80:
+		return VariantRFC4122
+	case u[8]&0xe0 == 0xc0:
+		return VariantMicrosoft
+	}
+	return VariantFuture
+}
+
+// Time returns the moment when time-based UUIDs (versions 1 and 7) were
+// generated. It returns false for other versions.
+func (u UUID) Time() (time.Time, bool) {
+	if u.Variant() != VariantRFC4122 {
+		return time.Time{}, false
+	}
+
+	switch u.Version() {
+	case 1:
+		timestamp := int64(binary.BigEndian.Uint16(u[6:8])&0x0fff)<<48 |
+			int64(binary.BigEndian.Uint16(u[4:6]))<<32 |
+			int64(binary.BigEndian.Uint32(u[0:4]))
+		return time.Unix(0, (timestamp-gregorianOffset)*100).UTC(), true
+	case 7:
+		milliseconds := int64(u[0])<<40 |
+			int64(u[1])<<32 |
+			int64(binary.BigEndian.Uint32(u[2:6]))
+		return time.Unix(0, milliseconds*int64(time.Millisecond)).UTC(), true
+	}
+	return time.Time{}, false
+}
+
+// setVersion sets the version in the 4 most significant bits of byte 6
+func (u *UUID) setVersion(version byte) {
+	u[6] = (u[6] & 0x0f) | (version << 4)
+}
+
+// setVariant sets the RFC 4122 variant (10xx) in the most significant bits
+// of byte 8
+func (u *UUID) setVariant() {
+	u[8] = (u[8] & 0x3f) | 0x80
+}
+
+// String returns the canonical form of the UUID, with hyphens, like
+// da308fbd-cba9-485a-b4c1-6677aaa732a4
+func (u UUID) String() string {
+	text := hex.EncodeToString(u[:])
+	return fmt.Sprintf("%s-%s-%s-%s-%s",
+		text[0:8],
+		text[8:12],
+		text[12:16],
+		text[16:20],
+		text[20:32])
+}
+
+// Hex returns the UUID without hyphens, like da308fbdcba9485ab4c16677aaa732a4
+func (u UUID) Hex() string {
+	return hex.EncodeToString(u[:])
+}
+
+// Braced returns the UUID between braces, like
+// {da308fbd-cba9-485a-b4c1-6677aaa732a4}
+func (u UUID) Braced() string {
+	return "{" + u.String() + "}"
+}
+
+// URN returns the UUID as an URN, like
+// urn:uuid:da308fbd-cba9-485a-b4c1-6677aaa732a4
+func (u UUID) URN() string {
+	return urnPrefix + u.String()
+}
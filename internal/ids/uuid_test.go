@@ -0,0 +1,297 @@
+package ids
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewV4(t *testing.T) {
+	// arrange
+	pattern := regexp.MustCompile(
+		"^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$")
+
+	// act
+	first, err := NewV4()
+	assert.NoError(t, err)
+	second, err := NewV4()
+	assert.NoError(t, err)
+
+	// assert
+	assert.Regexp(t, pattern, first.String())
+	assert.NotEqual(t, first, second)
+}
+
+func TestNewV4WithFixedRandomness(t *testing.T) {
+	// arrange
+	randReader = bytes.NewReader(bytes.Repeat([]byte{0xff}, 16))
+	defer func() { randReader = defaultRandReader }()
+
+	// act
+	uuid, err := NewV4()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "ffffffff-ffff-4fff-bfff-ffffffffffff", uuid.String())
+	assert.Equal(t, "ffffffffffff4fffbfffffffffffffff", uuid.Hex())
+}
+
+func TestNewV4RandomnessFailure(t *testing.T) {
+	// arrange
+	randReader = &failingReader{}
+	defer func() { randReader = defaultRandReader }()
+
+	// act
+	_, err := NewV4()
+
+	// assert
+	assert.Error(t, err)
+}
+
+// failingReader is an io.Reader always failing
+type failingReader struct{}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("no randomness")
+}
+
+func TestNewV1(t *testing.T) {
+	// arrange
+	fixed := time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC)
+	now = func() time.Time { return fixed }
+	defer func() { now = time.Now }()
+
+	// act
+	first, err := NewV1()
+	assert.NoError(t, err)
+	second, err := NewV1()
+	assert.NoError(t, err)
+
+	// assert
+	assert.Equal(t, 1, first.Version())
+	assert.Equal(t, byte(0x80), first[8]&0xc0, "invalid variant")
+	assert.Equal(t, byte(0x01), first[10]&0x01, "random nodes must set the multicast bit")
+	assert.NotEqual(t, first, second, "UUIDs in the same interval must be unique")
+	assert.Equal(t, first[8:], second[8:], "clock sequence and node must be kept")
+
+	timestamp := int64(binary.BigEndian.Uint16(first[6:8])&0x0fff)<<48 |
+		int64(binary.BigEndian.Uint16(first[4:6]))<<32 |
+		int64(binary.BigEndian.Uint32(first[0:4]))
+	assert.Equal(t, fixed.UnixNano()/100+gregorianOffset, timestamp)
+}
+
+func TestNewV5(t *testing.T) {
+	// act
+	uuid := NewV5(NamespaceDNS, "www.example.com")
+
+	// assert
+	assert.Equal(t, "2ed6657d-e927-568b-95e1-2665a8aea6a2", uuid.String())
+	assert.Equal(t, 5, uuid.Version())
+}
+
+func TestNewV7(t *testing.T) {
+	// arrange
+	fixed := time.UnixMilli(1645557742000)
+	now = func() time.Time { return fixed }
+	randReader = bytes.NewReader(bytes.Repeat([]byte{0xff}, 10))
+	defer func() {
+		now = time.Now
+		randReader = defaultRandReader
+	}()
+
+	// act
+	uuid, err := NewV7()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "017f22e2-79b0-7fff-bfff-ffffffffffff", uuid.String())
+	assert.Equal(t, 7, uuid.Version())
+}
+
+func TestNamespace(t *testing.T) {
+	testCases := []struct {
+		Value    string
+		Expected UUID
+		ErrorNil bool
+		Purpose  string
+	}{
+		{
+			Value:    "dns",
+			Expected: NamespaceDNS,
+			ErrorNil: true,
+			Purpose:  "dns",
+		},
+		{
+			Value:    "URL",
+			Expected: NamespaceURL,
+			ErrorNil: true,
+			Purpose:  "url in uppercase",
+		},
+		{
+			Value:    "oid",
+			Expected: NamespaceOID,
+			ErrorNil: true,
+			Purpose:  "oid",
+		},
+		{
+			Value:    "x500",
+			Expected: NamespaceX500,
+			ErrorNil: true,
+			Purpose:  "x500",
+		},
+		{
+			Value:    "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+			Expected: NamespaceDNS,
+			ErrorNil: true,
+			Purpose:  "uuid",
+		},
+		{
+			Value:    "unknown",
+			ErrorNil: false,
+			Purpose:  "unknown namespace",
+		},
+	}
+
+	for _, tc := range testCases {
+		// act
+		namespace, err := Namespace(tc.Value)
+
+		// assert
+		if tc.ErrorNil {
+			assert.NoError(t, err, "error found for "+tc.Purpose)
+			assert.Equal(t, tc.Expected, namespace, "invalid namespace for "+tc.Purpose)
+		} else {
+			assert.Error(t, err, "error not found for "+tc.Purpose)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		Value    string
+		ErrorNil bool
+		Purpose  string
+	}{
+		{
+			Value:    "da308fbd-cba9-485a-b4c1-6677aaa732a4",
+			ErrorNil: true,
+			Purpose:  "canonical form",
+		},
+		{
+			Value:    "da308fbdcba9485ab4c16677aaa732a4",
+			ErrorNil: true,
+			Purpose:  "without hyphens",
+		},
+		{
+			Value:    "{da308fbd-cba9-485a-b4c1-6677aaa732a4}",
+			ErrorNil: true,
+			Purpose:  "between braces",
+		},
+		{
+			Value:    "urn:uuid:da308fbd-cba9-485a-b4c1-6677aaa732a4",
+			ErrorNil: true,
+			Purpose:  "urn",
+		},
+		{
+			Value:    "URN:UUID:DA308FBD-CBA9-485A-B4C1-6677AAA732A4",
+			ErrorNil: true,
+			Purpose:  "urn in uppercase",
+		},
+		{
+			Value:    "{da308fbd-cba9-485a-b4c1-6677aaa732a4",
+			ErrorNil: false,
+			Purpose:  "missing brace",
+		},
+		{
+			Value:    "da308fbd+cba9+485a+b4c1+6677aaa732a4",
+			ErrorNil: false,
+			Purpose:  "invalid separators",
+		},
+		{
+			Value:    "zz308fbdcba9485ab4c16677aaa732a4",
+			ErrorNil: false,
+			Purpose:  "invalid characters",
+		},
+		{
+			Value:    "da308fbd",
+			ErrorNil: false,
+			Purpose:  "invalid length",
+		},
+	}
+
+	for _, tc := range testCases {
+		// act
+		uuid, err := Parse(tc.Value)
+
+		// assert
+		if tc.ErrorNil {
+			assert.NoError(t, err, "error found for "+tc.Purpose)
+			assert.Equal(t, "da308fbd-cba9-485a-b4c1-6677aaa732a4", uuid.String(), "invalid uuid for "+tc.Purpose)
+		} else {
+			assert.Error(t, err, "error not found for "+tc.Purpose)
+		}
+	}
+}
+
+func TestVariant(t *testing.T) {
+	testCases := []struct {
+		Byte     byte
+		Expected string
+	}{
+		{Byte: 0x00, Expected: VariantNCS},
+		{Byte: 0x7f, Expected: VariantNCS},
+		{Byte: 0x80, Expected: VariantRFC4122},
+		{Byte: 0xbf, Expected: VariantRFC4122},
+		{Byte: 0xc0, Expected: VariantMicrosoft},
+		{Byte: 0xe0, Expected: VariantFuture},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		uuid := UUID{}
+		uuid[8] = tc.Byte
+
+		// act
+		variant := uuid.Variant()
+
+		// assert
+		assert.Equal(t, tc.Expected, variant, "invalid variant for byte %x", tc.Byte)
+	}
+}
+
+func TestTime(t *testing.T) {
+	// arrange
+	fixed := time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)
+	now = func() time.Time { return fixed }
+	defer func() { now = time.Now }()
+	v1, _ := NewV1()
+	v4, _ := NewV4()
+	v7, _ := NewV7()
+
+	// act & assert
+	timestamp, found := v1.Time()
+	assert.True(t, found)
+	assert.Equal(t, fixed, timestamp.Truncate(time.Second), "invalid v1 time")
+
+	timestamp, found = v7.Time()
+	assert.True(t, found)
+	assert.Equal(t, fixed, timestamp, "invalid v7 time")
+
+	_, found = v4.Time()
+	assert.False(t, found, "v4 UUIDs have no time")
+}
+
+func TestFormats(t *testing.T) {
+	// arrange
+	uuid, _ := Parse("da308fbd-cba9-485a-b4c1-6677aaa732a4")
+
+	// assert
+	assert.Equal(t, "da308fbdcba9485ab4c16677aaa732a4", uuid.Hex())
+	assert.Equal(t, "{da308fbd-cba9-485a-b4c1-6677aaa732a4}", uuid.Braced())
+	assert.Equal(t, "urn:uuid:da308fbd-cba9-485a-b4c1-6677aaa732a4", uuid.URN())
+}
@@ -1,17 +1,176 @@
 package iostreams
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// Environment variables controlling the use of colors, following the
+// conventions in https://no-color.org and https://bixense.com/clicolors
+const (
+	NoColorEnvVar       string = "NO_COLOR"
+	CliColorForceEnvVar string = "CLICOLOR_FORCE"
 )
 
-// IOStreams represents the structures needed for input/output in commands
-// Currently only supports output.
+// DefaultTerminalWidth is the width used when the output is not a terminal or
+// its size cannot be found
+const DefaultTerminalWidth int = 80
+
+// IOStreams represents the structures needed for input/output in commands:
+// the input, the output for results and the error output for diagnostics,
+// together with what is known about the terminals behind them
 type IOStreams struct {
-	Out io.Writer
+	In     io.Reader
+	Out    io.Writer
+	ErrOut io.Writer
+
+	stdinTTY      bool
+	stdoutTTY     bool
+	stderrTTY     bool
+	colorEnabled  bool
+	terminalWidth int
+}
+
+// System creates the IOStreams for the standard input, output and error,
+// detecting which of them are terminals
+func System() *IOStreams {
+	iostreams := &IOStreams{
+		In:        os.Stdin,
+		Out:       os.Stdout,
+		ErrOut:    os.Stderr,
+		stdinTTY:  isTerminal(os.Stdin),
+		stdoutTTY: isTerminal(os.Stdout),
+		stderrTTY: isTerminal(os.Stderr),
+	}
+
+	if iostreams.stdoutTTY {
+		width, _, err := term.GetSize(int(os.Stdout.Fd()))
+		if err == nil && width > 0 {
+			iostreams.terminalWidth = width
+		}
+	}
+	iostreams.colorEnabled = detectColor(iostreams.stdoutTTY)
+
+	return iostreams
+}
+
+// Test creates IOStreams backed by in-memory buffers, returned so tests can
+// fill the input and check the outputs. None of the streams is a terminal.
+func Test() (*IOStreams, *bytes.Buffer, *bytes.Buffer, *bytes.Buffer) {
+	in := &bytes.Buffer{}
+	out := &bytes.Buffer{}
+	errOut := &bytes.Buffer{}
+	return &IOStreams{In: in, Out: out, ErrOut: errOut}, in, out, errOut
 }
 
 // PrintOutput knows how to print using an IOStreams struct
 func (iostreams *IOStreams) Fprint(v interface{}) (n int, err error) {
 	return fmt.Fprint(iostreams.Out, v)
 }
+
+// Errorf prints a diagnostic message to the error output, which is discarded
+// if not defined
+func (iostreams *IOStreams) Errorf(format string, a ...interface{}) (n int, err error) {
+	return fmt.Fprintf(iostreams.errOut(), format, a...)
+}
+
+// errOut returns the error output, discarding it if not defined
+func (iostreams *IOStreams) errOut() io.Writer {
+	if iostreams.ErrOut == nil {
+		return ioutil.Discard
+	}
+	return iostreams.ErrOut
+}
+
+// IsStdinTTY checks if the input is a terminal, meaning nothing was piped
+func (iostreams *IOStreams) IsStdinTTY() bool {
+	return iostreams.stdinTTY
+}
+
+// SetStdinTTY defines if the input is a terminal
+func (iostreams *IOStreams) SetStdinTTY(isTTY bool) {
+	iostreams.stdinTTY = isTTY
+}
+
+// IsStdoutTTY checks if the output is a terminal, meaning it is read by a
+// person and not by another program
+func (iostreams *IOStreams) IsStdoutTTY() bool {
+	return iostreams.stdoutTTY
+}
+
+// SetStdoutTTY defines if the output is a terminal
+func (iostreams *IOStreams) SetStdoutTTY(isTTY bool) {
+	iostreams.stdoutTTY = isTTY
+}
+
+// IsStderrTTY checks if the error output is a terminal
+func (iostreams *IOStreams) IsStderrTTY() bool {
+	return iostreams.stderrTTY
+}
+
+// SetStderrTTY defines if the error output is a terminal
+func (iostreams *IOStreams) SetStderrTTY(isTTY bool) {
+	iostreams.stderrTTY = isTTY
+}
+
+// TerminalWidth returns the number of columns of the output terminal, or
+// DefaultTerminalWidth if unknown
+func (iostreams *IOStreams) TerminalWidth() int {
+	if iostreams.terminalWidth > 0 {
+		return iostreams.terminalWidth
+	}
+	return DefaultTerminalWidth
+}
+
+// SetTerminalWidth defines the number of columns of the output terminal
+func (iostreams *IOStreams) SetTerminalWidth(width int) {
+	iostreams.terminalWidth = width
+}
+
+// ColorEnabled checks if colors can be used in the output
+func (iostreams *IOStreams) ColorEnabled() bool {
+	return iostreams.colorEnabled
+}
+
+// SetColorEnabled defines if colors can be used in the output
+func (iostreams *IOStreams) SetColorEnabled(enabled bool) {
+	iostreams.colorEnabled = enabled
+}
+
+// ReadPassword reads a line from the input without echoing it, to ask for
+// secrets. It fails when the input is not a terminal.
+func (iostreams *IOStreams) ReadPassword() (string, error) {
+	f, ok := iostreams.In.(*os.File)
+	if !ok || !iostreams.stdinTTY {
+		return "", errors.New("the input is not a terminal")
+	}
+	content, err := term.ReadPassword(int(f.Fd()))
+	if err != nil {
+		return "", fmt.Errorf("error reading the input: %w", err)
+	}
+	return string(content), nil
+}
+
+// isTerminal checks if a file is a terminal
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// detectColor checks if colors can be used: NO_COLOR disables them,
+// CLICOLOR_FORCE enables them even when the output is not a terminal and
+// otherwise they are used only on terminals supporting them
+func detectColor(stdoutTTY bool) bool {
+	if os.Getenv(NoColorEnvVar) != "" {
+		return false
+	}
+	if force := os.Getenv(CliColorForceEnvVar); force != "" && force != "0" {
+		return true
+	}
+	return stdoutTTY && os.Getenv("TERM") != "dumb"
+}
@@ -2,6 +2,8 @@ package iostreams
 
 import (
 	"bytes"
+	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -19,3 +21,146 @@ func TestPrintOutput(t *testing.T) {
 	// assert
 	assert.Equal(t, s, buffer.String())
 }
+
+func TestTest(t *testing.T) {
+	// arrange
+	iostreams, in, out, errOut := Test()
+	in.WriteString("input")
+
+	// act
+	input, _ := ioutil.ReadAll(iostreams.In)
+	iostreams.Fprint("output")
+	iostreams.Errorf("error %d", 1)
+
+	// assert
+	assert.Equal(t, "input", string(input))
+	assert.Equal(t, "output", out.String())
+	assert.Equal(t, "error 1", errOut.String())
+	assert.False(t, iostreams.IsStdinTTY())
+	assert.False(t, iostreams.IsStdoutTTY())
+	assert.False(t, iostreams.IsStderrTTY())
+	assert.False(t, iostreams.ColorEnabled())
+	assert.Equal(t, DefaultTerminalWidth, iostreams.TerminalWidth())
+}
+
+func TestErrorfWithoutErrOut(t *testing.T) {
+	// arrange
+	iostreams := IOStreams{Out: &bytes.Buffer{}}
+
+	// act
+	_, err := iostreams.Errorf("error")
+
+	// assert
+	assert.NoError(t, err)
+}
+
+func TestSetters(t *testing.T) {
+	// arrange
+	iostreams, _, _, _ := Test()
+
+	// act
+	iostreams.SetStdinTTY(true)
+	iostreams.SetStdoutTTY(true)
+	iostreams.SetStderrTTY(true)
+	iostreams.SetColorEnabled(true)
+	iostreams.SetTerminalWidth(120)
+
+	// assert
+	assert.True(t, iostreams.IsStdinTTY())
+	assert.True(t, iostreams.IsStdoutTTY())
+	assert.True(t, iostreams.IsStderrTTY())
+	assert.True(t, iostreams.ColorEnabled())
+	assert.Equal(t, 120, iostreams.TerminalWidth())
+}
+
+func TestDetectColor(t *testing.T) {
+	testCases := []struct {
+		NoColor       string
+		CliColorForce string
+		Term          string
+		StdoutTTY     bool
+		Expected      bool
+		Purpose       string
+	}{
+		{
+			Term:      "xterm",
+			StdoutTTY: true,
+			Expected:  true,
+			Purpose:   "terminal",
+		},
+		{
+			Term:      "xterm",
+			StdoutTTY: false,
+			Expected:  false,
+			Purpose:   "pipe",
+		},
+		{
+			Term:      "dumb",
+			StdoutTTY: true,
+			Expected:  false,
+			Purpose:   "dumb terminal",
+		},
+		{
+			NoColor:   "1",
+			Term:      "xterm",
+			StdoutTTY: true,
+			Expected:  false,
+			Purpose:   "NO_COLOR on a terminal",
+		},
+		{
+			CliColorForce: "1",
+			StdoutTTY:     false,
+			Expected:      true,
+			Purpose:       "CLICOLOR_FORCE on a pipe",
+		},
+		{
+			CliColorForce: "0",
+			StdoutTTY:     false,
+			Expected:      false,
+			Purpose:       "CLICOLOR_FORCE disabled",
+		},
+		{
+			NoColor:       "1",
+			CliColorForce: "1",
+			StdoutTTY:     true,
+			Expected:      false,
+			Purpose:       "NO_COLOR wins over CLICOLOR_FORCE",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		t.Setenv(NoColorEnvVar, tc.NoColor)
+		t.Setenv(CliColorForceEnvVar, tc.CliColorForce)
+		t.Setenv("TERM", tc.Term)
+
+		// act
+		enabled := detectColor(tc.StdoutTTY)
+
+		// assert
+		assert.Equal(t, tc.Expected, enabled, "invalid color detection for "+tc.Purpose)
+	}
+}
+
+func TestSystem(t *testing.T) {
+	// act
+	iostreams := System()
+
+	// assert
+	assert.Equal(t, os.Stdin, iostreams.In)
+	assert.Equal(t, os.Stdout, iostreams.Out)
+	assert.Equal(t, os.Stderr, iostreams.ErrOut)
+	assert.Greater(t, iostreams.TerminalWidth(), 0)
+}
+
+func TestReadPasswordWithoutTerminal(t *testing.T) {
+	// arrange
+	iostreams, in, _, _ := Test()
+	in.WriteString("secret\n")
+
+	// act
+	_, err := iostreams.ReadPassword()
+
+	// assert
+	assert.EqualError(t, err, "the input is not a terminal")
+}
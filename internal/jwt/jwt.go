@@ -0,0 +1,223 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSignature is returned when the signature of a token does not
+// match the key
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// Token is a decoded JSON Web Token (RFC 7519) in the JWS compact
+// serialization
+type Token struct {
+	Raw       string
+	Header    map[string]interface{}
+	Claims    map[string]interface{}
+	Signature []byte
+
+	// signingInput is the signed part of the token: header and claims
+	signingInput string
+}
+
+// Decode decodes a token without verifying its signature. A leading
+// "Bearer " is ignored so Authorization headers can be decoded as they are.
+func Decode(raw string) (*Token, error) {
+	raw = strings.TrimSpace(raw)
+	if len(raw) > 7 && strings.EqualFold(raw[:7], "bearer ") {
+		raw = strings.TrimSpace(raw[7:])
+	}
+
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid token: expected 3 parts separated by dots, found %d", len(parts))
+	}
+
+	token := &Token{
+		Raw:          raw,
+		signingInput: parts[0] + "." + parts[1],
+	}
+
+	err := decodePart(parts[0], &token.Header)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token header: %w", err)
+	}
+	err = decodePart(parts[1], &token.Claims)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token claims: %w", err)
+	}
+	token.Signature, err = decodeSegment(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token signature: %w", err)
+	}
+
+	return token, nil
+}
+
+// decodeSegment decodes a base64url segment, with or without padding
+func decodeSegment(segment string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+}
+
+// decodePart decodes a base64url segment containing a JSON object, keeping
+// numbers as json.Number to avoid losing precision
+func decodePart(segment string, value *map[string]interface{}) error {
+	content, err := decodeSegment(segment)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	return decoder.Decode(value)
+}
+
+// Algorithm returns the signing algorithm in the header, like HS256
+func (t *Token) Algorithm() string {
+	alg, _ := t.Header["alg"].(string)
+	return alg
+}
+
+// KeyId returns the id of the signing key in the header, if any
+func (t *Token) KeyId() string {
+	kid, _ := t.Header["kid"].(string)
+	return kid
+}
+
+// Time returns the value of a date claim, like exp, as a time
+func (t *Token) Time(claim string) (time.Time, bool) {
+	number, ok := t.Claims[claim].(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	seconds, err := number.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, int64(seconds*float64(time.Second))).UTC(), true
+}
+
+// Expired checks if the token is expired at the given moment. Tokens without
+// the exp claim never expire.
+func (t *Token) Expired(at time.Time) bool {
+	expiresAt, found := t.Time("exp")
+	return found && !at.Before(expiresAt)
+}
+
+// hashes are the hash functions of each algorithm size
+var hashes = map[string]crypto.Hash{
+	"256": crypto.SHA256,
+	"384": crypto.SHA384,
+	"512": crypto.SHA512,
+}
+
+// hash returns the hash function of the algorithm and the digest of the
+// signing input
+func (t *Token) hash() (crypto.Hash, []byte, error) {
+	alg := t.Algorithm()
+	if len(alg) < 5 {
+		return 0, nil, fmt.Errorf("unsupported algorithm %q", alg)
+	}
+	hash, found := hashes[alg[2:]]
+	if !found {
+		return 0, nil, fmt.Errorf("unsupported algorithm %q", alg)
+	}
+
+	hasher := hash.New()
+	hasher.Write([]byte(t.signingInput))
+	return hash, hasher.Sum(nil), nil
+}
+
+// VerifyHMAC verifies the signature of tokens signed with a shared secret
+// (HS256, HS384 or HS512)
+func (t *Token) VerifyHMAC(secret []byte) error {
+	if !strings.HasPrefix(t.Algorithm(), "HS") {
+		return fmt.Errorf("a secret cannot verify tokens signed with %q", t.Algorithm())
+	}
+	hash, _, err := t.hash()
+	if err != nil {
+		return err
+	}
+
+	newHash := sha256.New
+	switch hash {
+	case crypto.SHA384:
+		newHash = sha512.New384
+	case crypto.SHA512:
+		newHash = sha512.New
+	}
+	mac := hmac.New(newHash, secret)
+	mac.Write([]byte(t.signingInput))
+	if !hmac.Equal(mac.Sum(nil), t.Signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// VerifyPublicKey verifies the signature of tokens signed with a private key
+// (RS*, PS*, ES* or EdDSA) using the matching public key
+func (t *Token) VerifyPublicKey(key crypto.PublicKey) error {
+	alg := t.Algorithm()
+	switch publicKey := key.(type) {
+	case *rsa.PublicKey:
+		hash, digest, err := t.hash()
+		if err != nil {
+			return err
+		}
+		switch {
+		case strings.HasPrefix(alg, "RS"):
+			err = rsa.VerifyPKCS1v15(publicKey, hash, digest, t.Signature)
+		case strings.HasPrefix(alg, "PS"):
+			err = rsa.VerifyPSS(publicKey, hash, digest, t.Signature,
+				&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		default:
+			return fmt.Errorf("a RSA key cannot verify tokens signed with %q", alg)
+		}
+		if err != nil {
+			return ErrInvalidSignature
+		}
+		return nil
+
+	case *ecdsa.PublicKey:
+		if !strings.HasPrefix(alg, "ES") {
+			return fmt.Errorf("an EC key cannot verify tokens signed with %q", alg)
+		}
+		_, digest, err := t.hash()
+		if err != nil {
+			return err
+		}
+		size := (publicKey.Curve.Params().BitSize + 7) / 8
+		if len(t.Signature) != 2*size {
+			return ErrInvalidSignature
+		}
+		r := new(big.Int).SetBytes(t.Signature[:size])
+		s := new(big.Int).SetBytes(t.Signature[size:])
+		if !ecdsa.Verify(publicKey, digest, r, s) {
+			return ErrInvalidSignature
+		}
+		return nil
+
+	case ed25519.PublicKey:
+		if alg != "EdDSA" {
+			return fmt.Errorf("an Ed25519 key cannot verify tokens signed with %q", alg)
+		}
+		if !ed25519.Verify(publicKey, []byte(t.signingInput), t.Signature) {
+			return ErrInvalidSignature
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported key type %T", key)
+}
@@ -0,0 +1,222 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// jwtIoToken is the example token of jwt.io, signed with the secret
+// "your-256-bit-secret"
+const jwtIoToken string = "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9." +
+	"eyJzdWIiOiIxMjM0NTY3ODkwIiwibmFtZSI6IkpvaG4gRG9lIiwiaWF0IjoxNTE2MjM5MDIyfQ." +
+	"SflKxwRJSMeKKF2QT4fwpMeJf36POk6yJV_adQssw5c"
+
+// signToken creates a token with the header and claims, signing it with
+// the function
+func signToken(t *testing.T, header map[string]interface{}, claims map[string]interface{}, sign func(input []byte) []byte) string {
+	encode := func(value interface{}) string {
+		content, err := json.Marshal(value)
+		assert.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(content)
+	}
+	input := encode(header) + "." + encode(claims)
+	return input + "." + base64.RawURLEncoding.EncodeToString(sign([]byte(input)))
+}
+
+func TestDecode(t *testing.T) {
+	// act
+	token, err := Decode("Bearer " + jwtIoToken)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, jwtIoToken, token.Raw)
+	assert.Equal(t, "HS256", token.Algorithm())
+	assert.Equal(t, "", token.KeyId())
+	assert.Equal(t, "John Doe", token.Claims["name"])
+	assert.Equal(t, json.Number("1516239022"), token.Claims["iat"])
+	assert.Len(t, token.Signature, 32)
+
+	issuedAt, found := token.Time("iat")
+	assert.True(t, found)
+	assert.Equal(t, time.Date(2018, 1, 18, 1, 30, 22, 0, time.UTC), issuedAt)
+	_, found = token.Time("exp")
+	assert.False(t, found)
+	assert.False(t, token.Expired(time.Now()), "Tokens without exp never expire")
+}
+
+func TestDecodeInvalidTokens(t *testing.T) {
+	testCases := []struct {
+		Purpose string
+		Token   string
+		Error   string
+	}{
+		{
+			Purpose: "not a token",
+			Token:   "not-a-token",
+			Error:   "invalid token: expected 3 parts separated by dots, found 1",
+		},
+		{
+			Purpose: "invalid header",
+			Token:   "e3*.e30.",
+			Error:   "invalid token header: illegal base64 data at input byte 2",
+		},
+		{
+			Purpose: "claims are not an object",
+			Token:   "e30.WzFd.",
+			Error:   "invalid token claims: json: cannot unmarshal array into Go value of type map[string]interface {}",
+		},
+		{
+			Purpose: "invalid signature encoding",
+			Token:   "e30.e30.***",
+			Error:   "invalid token signature: illegal base64 data at input byte 0",
+		},
+	}
+
+	for _, tc := range testCases {
+		// act
+		_, err := Decode(tc.Token)
+
+		// assert
+		assert.EqualError(t, err, tc.Error, tc.Purpose)
+	}
+}
+
+func TestExpired(t *testing.T) {
+	// arrange
+	expiresAt := time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)
+	raw := signToken(t,
+		map[string]interface{}{"alg": "none"},
+		map[string]interface{}{"exp": expiresAt.Unix()},
+		func(input []byte) []byte { return nil })
+
+	// act
+	token, err := Decode(raw)
+
+	// assert
+	assert.NoError(t, err)
+	assert.False(t, token.Expired(expiresAt.Add(-time.Second)))
+	assert.True(t, token.Expired(expiresAt))
+}
+
+func TestVerifyHMAC(t *testing.T) {
+	// arrange
+	token, err := Decode(jwtIoToken)
+	assert.NoError(t, err)
+
+	// act & assert
+	assert.NoError(t, token.VerifyHMAC([]byte("your-256-bit-secret")))
+	assert.ErrorIs(t, token.VerifyHMAC([]byte("another-secret")), ErrInvalidSignature)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	assert.EqualError(t, token.VerifyPublicKey(&rsaKey.PublicKey),
+		"a RSA key cannot verify tokens signed with \"HS256\"")
+}
+
+func TestVerifyHMACWithUnsupportedAlgorithm(t *testing.T) {
+	// arrange
+	token, err := Decode(signToken(t,
+		map[string]interface{}{"alg": "none"},
+		map[string]interface{}{},
+		func(input []byte) []byte { return nil }))
+	assert.NoError(t, err)
+
+	// act
+	err = token.VerifyHMAC([]byte("secret"))
+
+	// assert
+	assert.EqualError(t, err, "a secret cannot verify tokens signed with \"none\"")
+}
+
+func TestVerifyPublicKey(t *testing.T) {
+	// arrange
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	edPublicKey, edPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	digest := func(input []byte) []byte {
+		hash := crypto.SHA256.New()
+		hash.Write(input)
+		return hash.Sum(nil)
+	}
+
+	testCases := []struct {
+		Purpose   string
+		Alg       string
+		PublicKey crypto.PublicKey
+		Sign      func(input []byte) []byte
+	}{
+		{
+			Purpose:   "RSA PKCS #1 v1.5",
+			Alg:       "RS256",
+			PublicKey: &rsaKey.PublicKey,
+			Sign: func(input []byte) []byte {
+				signature, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest(input))
+				assert.NoError(t, err)
+				return signature
+			},
+		},
+		{
+			Purpose:   "RSA PSS",
+			Alg:       "PS256",
+			PublicKey: &rsaKey.PublicKey,
+			Sign: func(input []byte) []byte {
+				signature, err := rsa.SignPSS(rand.Reader, rsaKey, crypto.SHA256, digest(input),
+					&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+				assert.NoError(t, err)
+				return signature
+			},
+		},
+		{
+			Purpose:   "ECDSA",
+			Alg:       "ES256",
+			PublicKey: &ecKey.PublicKey,
+			Sign: func(input []byte) []byte {
+				r, s, err := ecdsa.Sign(rand.Reader, ecKey, digest(input))
+				assert.NoError(t, err)
+				signature := make([]byte, 64)
+				r.FillBytes(signature[:32])
+				s.FillBytes(signature[32:])
+				return signature
+			},
+		},
+		{
+			Purpose:   "Ed25519",
+			Alg:       "EdDSA",
+			PublicKey: edPublicKey,
+			Sign: func(input []byte) []byte {
+				return ed25519.Sign(edPrivateKey, input)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		raw := signToken(t,
+			map[string]interface{}{"alg": tc.Alg},
+			map[string]interface{}{"sub": "1234567890"},
+			tc.Sign)
+		token, err := Decode(raw)
+		assert.NoError(t, err, tc.Purpose)
+		tampered, err := Decode(raw[:len(raw)-4] + "AAAA")
+		assert.NoError(t, err, tc.Purpose)
+
+		// act & assert
+		assert.NoError(t, token.VerifyPublicKey(tc.PublicKey), tc.Purpose)
+		assert.ErrorIs(t, tampered.VerifyPublicKey(tc.PublicKey), ErrInvalidSignature, tc.Purpose)
+		assert.Error(t, token.VerifyHMAC([]byte("secret")), tc.Purpose)
+	}
+}
@@ -0,0 +1,194 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ParsePublicKeyPEM reads a public key, or the public key of a certificate,
+// in the PEM format
+func ParsePublicKeyPEM(content []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "CERTIFICATE":
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing the certificate: %w", err)
+		}
+		return certificate.PublicKey, nil
+	case "RSA PUBLIC KEY":
+		key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing the public key: %w", err)
+		}
+		return key, nil
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing the public key: %w", err)
+		}
+		return key, nil
+	}
+	return nil, fmt.Errorf("unsupported PEM block %q, expected a public key or a certificate", block.Type)
+}
+
+// ParsePrivateKeyPEM reads a RSA, EC or Ed25519 private key in the PEM
+// format, either PKCS #1, SEC 1 or PKCS #8. Encrypted keys are not supported.
+func ParsePrivateKeyPEM(content []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing the private key: %w", err)
+		}
+		return key, nil
+	case "EC PRIVATE KEY":
+		key, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing the private key: %w", err)
+		}
+		return key, nil
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing the private key: %w", err)
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported key type %T", key)
+		}
+		return signer, nil
+	}
+	return nil, fmt.Errorf("unsupported PEM block %q, expected a private key", block.Type)
+}
+
+// JWK is a JSON Web Key (RFC 7517), with the fields of RSA, EC and OKP
+// public keys
+type JWK struct {
+	KeyType string `json:"kty"`
+	KeyId   string `json:"kid"`
+	Use     string `json:"use,omitempty"`
+	Alg     string `json:"alg,omitempty"`
+
+	// RSA keys
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// EC and OKP keys
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+	Y     string `json:"y,omitempty"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// ParseJWKS reads a JSON Web Key Set
+func ParseJWKS(content []byte) (*JWKS, error) {
+	jwks := &JWKS{}
+	err := json.Unmarshal(content, jwks)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the JWKS: %w", err)
+	}
+	return jwks, nil
+}
+
+// Key returns the public key with the given id. Without an id, the set must
+// have a single key.
+func (s *JWKS) Key(keyId string) (crypto.PublicKey, error) {
+	if keyId == "" {
+		if len(s.Keys) != 1 {
+			return nil, fmt.Errorf("the token has no key id and the JWKS has %d keys", len(s.Keys))
+		}
+		return s.Keys[0].PublicKey()
+	}
+
+	for _, key := range s.Keys {
+		if key.KeyId == keyId {
+			return key.PublicKey()
+		}
+	}
+	return nil, fmt.Errorf("key %q not found in the JWKS", keyId)
+}
+
+// PublicKey returns the public key represented by the JWK
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+	switch k.KeyType {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RSA modulus: %w", err)
+		}
+		e, err := decodeInt(k.E)
+		if err != nil || !e.IsInt64() {
+			return nil, fmt.Errorf("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		curves := map[string]elliptic.Curve{
+			"P-256": elliptic.P256(),
+			"P-384": elliptic.P384(),
+			"P-521": elliptic.P521(),
+		}
+		curve, found := curves[k.Curve]
+		if !found {
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC x coordinate: %w", err)
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EC y coordinate: %w", err)
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("invalid EC key: the point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+
+	case "OKP":
+		if k.Curve != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := decodeSegment(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.KeyType)
+}
+
+// decodeInt decodes a base64url big-endian unsigned integer
+func decodeInt(value string) (*big.Int, error) {
+	content, err := decodeSegment(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(content) == 0 {
+		return nil, errors.New("empty value")
+	}
+	return new(big.Int).SetBytes(content), nil
+}
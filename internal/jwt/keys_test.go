@@ -0,0 +1,241 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePublicKeyPEM(t *testing.T) {
+	// arrange
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	pkix, err := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		Purpose string
+		PEM     []byte
+		Key     interface{}
+		Error   string
+	}{
+		{
+			Purpose: "PKIX public key",
+			PEM:     pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkix}),
+			Key:     &ecKey.PublicKey,
+		},
+		{
+			Purpose: "PKCS #1 public key",
+			PEM: pem.EncodeToMemory(&pem.Block{
+				Type:  "RSA PUBLIC KEY",
+				Bytes: x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey),
+			}),
+			Key: &rsaKey.PublicKey,
+		},
+		{
+			Purpose: "private key",
+			PEM: pem.EncodeToMemory(&pem.Block{
+				Type:  "RSA PRIVATE KEY",
+				Bytes: x509.MarshalPKCS1PrivateKey(rsaKey),
+			}),
+			Error: "unsupported PEM block \"RSA PRIVATE KEY\", expected a public key or a certificate",
+		},
+		{
+			Purpose: "not PEM",
+			PEM:     []byte("not a key"),
+			Error:   "no PEM block found",
+		},
+	}
+
+	for _, tc := range testCases {
+		// act
+		key, err := ParsePublicKeyPEM(tc.PEM)
+
+		// assert
+		if tc.Error != "" {
+			assert.EqualError(t, err, tc.Error, tc.Purpose)
+			continue
+		}
+		assert.NoError(t, err, tc.Purpose)
+		assert.Equal(t, tc.Key, key, tc.Purpose)
+	}
+}
+
+func TestParsePrivateKeyPEM(t *testing.T) {
+	// arrange
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	sec1, err := x509.MarshalECPrivateKey(ecKey)
+	assert.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(edKey)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		Purpose string
+		PEM     []byte
+		Key     interface{}
+		Error   string
+	}{
+		{
+			Purpose: "PKCS #1 private key",
+			PEM: pem.EncodeToMemory(&pem.Block{
+				Type:  "RSA PRIVATE KEY",
+				Bytes: x509.MarshalPKCS1PrivateKey(rsaKey),
+			}),
+			Key: rsaKey,
+		},
+		{
+			Purpose: "SEC 1 private key",
+			PEM:     pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1}),
+			Key:     ecKey,
+		},
+		{
+			Purpose: "PKCS #8 private key",
+			PEM:     pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}),
+			Key:     edKey,
+		},
+		{
+			Purpose: "public key",
+			PEM: pem.EncodeToMemory(&pem.Block{
+				Type:  "RSA PUBLIC KEY",
+				Bytes: x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey),
+			}),
+			Error: "unsupported PEM block \"RSA PUBLIC KEY\", expected a private key",
+		},
+		{
+			Purpose: "not PEM",
+			PEM:     []byte("not a key"),
+			Error:   "no PEM block found",
+		},
+	}
+
+	for _, tc := range testCases {
+		// act
+		key, err := ParsePrivateKeyPEM(tc.PEM)
+
+		// assert
+		if tc.Error != "" {
+			assert.EqualError(t, err, tc.Error, tc.Purpose)
+			continue
+		}
+		assert.NoError(t, err, tc.Purpose)
+		// keys are compared with Equal as their precomputed values may differ
+		parsed, ok := key.(interface{ Equal(crypto.PrivateKey) bool })
+		assert.True(t, ok && parsed.Equal(tc.Key), tc.Purpose)
+	}
+}
+
+func TestJWKSKey(t *testing.T) {
+	// arrange
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	encode := base64.RawURLEncoding.EncodeToString
+	content := fmt.Sprintf(`{"keys": [
+		{"kty": "RSA", "kid": "rsa", "n": "%s", "e": "%s"},
+		{"kty": "EC", "kid": "ec", "crv": "P-256", "x": "%s", "y": "%s"},
+		{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": "%s"},
+		{"kty": "oct", "kid": "secret", "k": "c2VjcmV0"}
+	]}`,
+		encode(rsaKey.N.Bytes()),
+		encode(big.NewInt(int64(rsaKey.E)).Bytes()),
+		encode(ecKey.X.Bytes()),
+		encode(ecKey.Y.Bytes()),
+		encode(edKey))
+
+	jwks, err := ParseJWKS([]byte(content))
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		Purpose string
+		KeyId   string
+		Key     interface{}
+		Error   string
+	}{
+		{Purpose: "RSA key", KeyId: "rsa", Key: &rsaKey.PublicKey},
+		{Purpose: "EC key", KeyId: "ec", Key: &ecKey.PublicKey},
+		{Purpose: "Ed25519 key", KeyId: "ed", Key: edKey},
+		{Purpose: "symmetric key", KeyId: "secret", Error: "unsupported key type \"oct\""},
+		{Purpose: "unknown key", KeyId: "other", Error: "key \"other\" not found in the JWKS"},
+		{Purpose: "no key id", KeyId: "", Error: "the token has no key id and the JWKS has 4 keys"},
+	}
+
+	for _, tc := range testCases {
+		// act
+		key, err := jwks.Key(tc.KeyId)
+
+		// assert
+		if tc.Error != "" {
+			assert.EqualError(t, err, tc.Error, tc.Purpose)
+			continue
+		}
+		assert.NoError(t, err, tc.Purpose)
+		assert.Equal(t, tc.Key, key, tc.Purpose)
+	}
+}
+
+func TestJWKSKeyWithInvalidKeys(t *testing.T) {
+	testCases := []struct {
+		Purpose string
+		JWK     JWK
+		Error   string
+	}{
+		{
+			Purpose: "RSA key without modulus",
+			JWK:     JWK{KeyType: "RSA", E: "AQAB"},
+			Error:   "invalid RSA modulus: empty value",
+		},
+		{
+			Purpose: "EC key with unsupported curve",
+			JWK:     JWK{KeyType: "EC", Curve: "secp256k1"},
+			Error:   "unsupported curve \"secp256k1\"",
+		},
+		{
+			Purpose: "EC key not on the curve",
+			JWK:     JWK{KeyType: "EC", Curve: "P-256", X: "AQ", Y: "AQ"},
+			Error:   "invalid EC key: the point is not on the curve",
+		},
+		{
+			Purpose: "Ed25519 key too short",
+			JWK:     JWK{KeyType: "OKP", Curve: "Ed25519", X: "AQ"},
+			Error:   "invalid Ed25519 key",
+		},
+	}
+
+	for _, tc := range testCases {
+		// act
+		_, err := tc.JWK.PublicKey()
+
+		// assert
+		assert.EqualError(t, err, tc.Error, tc.Purpose)
+	}
+}
+
+func TestParseJWKSWithInvalidContent(t *testing.T) {
+	// act
+	_, err := ParseJWKS([]byte("not json"))
+
+	// assert
+	assert.Error(t, err)
+}
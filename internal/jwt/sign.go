@@ -0,0 +1,103 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SigningAlgorithm returns the algorithm used to sign tokens with a private
+// key: RS256 for RSA keys, ES256, ES384 or ES512 for EC keys, depending on
+// the curve, and EdDSA for Ed25519 keys
+func SigningAlgorithm(key crypto.Signer) (string, error) {
+	switch privateKey := key.(type) {
+	case *rsa.PrivateKey:
+		return "RS256", nil
+	case *ecdsa.PrivateKey:
+		switch privateKey.Curve.Params().BitSize {
+		case 256:
+			return "ES256", nil
+		case 384:
+			return "ES384", nil
+		case 521:
+			return "ES512", nil
+		}
+		return "", fmt.Errorf("unsupported curve %s", privateKey.Curve.Params().Name)
+	case ed25519.PrivateKey:
+		return "EdDSA", nil
+	}
+	return "", fmt.Errorf("unsupported key type %T", key)
+}
+
+// Sign creates a token with the claims signed with the private key, using
+// the algorithm of SigningAlgorithm. The key id is added to the header when
+// not empty.
+func Sign(claims map[string]interface{}, key crypto.Signer, keyId string) (string, error) {
+	alg, err := SigningAlgorithm(key)
+	if err != nil {
+		return "", err
+	}
+
+	header := map[string]interface{}{"alg": alg, "typ": "JWT"}
+	if keyId != "" {
+		header["kid"] = keyId
+	}
+	encodedHeader, err := encodePart(header)
+	if err != nil {
+		return "", err
+	}
+	encodedClaims, err := encodePart(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := encodedHeader + "." + encodedClaims
+
+	signature, err := sign(key, alg, []byte(signingInput))
+	if err != nil {
+		return "", fmt.Errorf("error signing the token: %w", err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// encodePart encodes the header or the claims of a token
+func encodePart(value map[string]interface{}) (string, error) {
+	content, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("error encoding the token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(content), nil
+}
+
+// sign signs the input with the algorithm, encoding EC signatures as the
+// fixed size r and s values required by JWS
+func sign(key crypto.Signer, alg string, input []byte) ([]byte, error) {
+	if alg == "EdDSA" {
+		return key.Sign(rand.Reader, input, crypto.Hash(0))
+	}
+
+	hash := hashes[alg[2:]]
+	hasher := hash.New()
+	hasher.Write(input)
+	digest := hasher.Sum(nil)
+
+	if !strings.HasPrefix(alg, "ES") {
+		return key.Sign(rand.Reader, digest, hash)
+	}
+
+	privateKey := key.(*ecdsa.PrivateKey)
+	r, s, err := ecdsa.Sign(rand.Reader, privateKey, digest)
+	if err != nil {
+		return nil, err
+	}
+	size := (privateKey.Curve.Params().BitSize + 7) / 8
+	signature := make([]byte, 2*size)
+	r.FillBytes(signature[:size])
+	s.FillBytes(signature[size:])
+	return signature, nil
+}
@@ -0,0 +1,78 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	// arrange
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	assert.NoError(t, err)
+	edPublicKey, edPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		Purpose    string
+		PrivateKey crypto.Signer
+		PublicKey  crypto.PublicKey
+		KeyId      string
+		Alg        string
+	}{
+		{
+			Purpose:    "RSA",
+			PrivateKey: rsaKey,
+			PublicKey:  &rsaKey.PublicKey,
+			KeyId:      "rsa-key",
+			Alg:        "RS256",
+		},
+		{
+			Purpose:    "ECDSA",
+			PrivateKey: ecKey,
+			PublicKey:  &ecKey.PublicKey,
+			Alg:        "ES384",
+		},
+		{
+			Purpose:    "Ed25519",
+			PrivateKey: edPrivateKey,
+			PublicKey:  edPublicKey,
+			Alg:        "EdDSA",
+		},
+	}
+
+	for _, tc := range testCases {
+		// act
+		raw, err := Sign(map[string]interface{}{"sub": "client"}, tc.PrivateKey, tc.KeyId)
+
+		// assert
+		assert.NoError(t, err, tc.Purpose)
+		token, err := Decode(raw)
+		assert.NoError(t, err, tc.Purpose)
+		assert.Equal(t, tc.Alg, token.Algorithm(), tc.Purpose)
+		assert.Equal(t, tc.KeyId, token.KeyId(), tc.Purpose)
+		assert.Equal(t, "JWT", token.Header["typ"], tc.Purpose)
+		assert.Equal(t, "client", token.Claims["sub"], tc.Purpose)
+		assert.NoError(t, token.VerifyPublicKey(tc.PublicKey), tc.Purpose)
+	}
+}
+
+func TestSigningAlgorithmWithUnsupportedCurve(t *testing.T) {
+	// arrange
+	key, err := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	assert.NoError(t, err)
+
+	// act
+	_, err = SigningAlgorithm(key)
+
+	// assert
+	assert.EqualError(t, err, "unsupported curve P-224")
+}
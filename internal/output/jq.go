@@ -0,0 +1,109 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/itchyny/gojq"
+)
+
+// Filter evaluates a jq filter against the data, returning all the values it
+// produces. Numbers are kept exact: integers of any size are big integers and
+// decimals are float64, so they can be compared and computed, but decimals a
+// float64 cannot hold exactly, like 12.50 or amounts above 2^53, are written
+// with their original text when they pass through the filter unchanged.
+func Filter(filter string, data interface{}) ([]interface{}, error) {
+	query, err := gojq.Parse(filter)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the jq filter: %w", err)
+	}
+
+	normalized, err := normalize(data)
+	if err != nil {
+		return nil, err
+	}
+
+	texts := map[float64]string{}
+	results := []interface{}{}
+	iter := query.Run(exactNumbers(normalized, texts))
+	for {
+		result, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, isError := result.(error); isError {
+			return nil, fmt.Errorf("error evaluating the jq filter: %w", err)
+		}
+		results = append(results, restoreNumbers(result, texts))
+	}
+	return results, nil
+}
+
+// exactNumbers replaces the numbers of a normalized value by the types gojq
+// evaluates without rounding integers, keeping the text of the decimals that
+// change when converted to float64
+func exactNumbers(value interface{}, texts map[float64]string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = exactNumbers(item, texts)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = exactNumbers(item, texts)
+		}
+		return v
+	case json.Number:
+		return exactNumber(v, texts)
+	}
+	return value
+}
+
+// exactNumber converts a number into a big integer or a float64, keeping its
+// text when the float64 is written differently. Decimals with the same
+// float64 but different texts are ambiguous and their texts are not kept.
+func exactNumber(number json.Number, texts map[float64]string) interface{} {
+	text := string(number)
+	if !strings.ContainsAny(text, ".eE") {
+		if integer, ok := new(big.Int).SetString(text, 10); ok {
+			return integer
+		}
+	}
+
+	float, err := number.Float64()
+	if err != nil {
+		return text
+	}
+	content, err := json.Marshal(float)
+	if err == nil && string(content) != text {
+		if previous, found := texts[float]; found && previous != text {
+			texts[float] = ""
+		} else {
+			texts[float] = text
+		}
+	}
+	return float
+}
+
+// restoreNumbers replaces the float64 values of a filter result by the text
+// of the decimals they were converted from
+func restoreNumbers(value interface{}, texts map[float64]string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = restoreNumbers(item, texts)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = restoreNumbers(item, texts)
+		}
+	case float64:
+		if text := texts[v]; text != "" {
+			return json.Number(text)
+		}
+	}
+	return value
+}
@@ -0,0 +1,121 @@
+package output
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter(t *testing.T) {
+	testCases := []struct {
+		Filter   string
+		Data     interface{}
+		Expected []interface{}
+		ErrorNil bool
+		Purpose  string
+	}{
+		{
+			Filter:   ".uuid",
+			Data:     item{Uuid: "da308fbd"},
+			Expected: []interface{}{"da308fbd"},
+			ErrorNil: true,
+			Purpose:  "field",
+		},
+		{
+			Filter:   ".[].uuid",
+			Data:     []item{{Uuid: "da308fbd"}, {Uuid: "cba9485a"}},
+			Expected: []interface{}{"da308fbd", "cba9485a"},
+			ErrorNil: true,
+			Purpose:  "several results",
+		},
+		{
+			Filter:   ".amount * 2",
+			Data:     item{Amount: 1.5},
+			Expected: []interface{}{3.0},
+			ErrorNil: true,
+			Purpose:  "numbers",
+		},
+		{
+			Filter:   ". + 1",
+			Data:     json.Number("9007199254740993"),
+			Expected: []interface{}{9007199254740994},
+			ErrorNil: true,
+			Purpose:  "integers above 2^53",
+		},
+		{
+			Filter:   ".",
+			Data:     json.Number("123456789012345678901234567890"),
+			Expected: []interface{}{bigInt("123456789012345678901234567890")},
+			ErrorNil: true,
+			Purpose:  "integers above the int64 range",
+		},
+		{
+			Filter:   ".result",
+			Data:     map[string]interface{}{"result": json.Number("13372839025857403.40")},
+			Expected: []interface{}{json.Number("13372839025857403.40")},
+			ErrorNil: true,
+			Purpose:  "decimals above 2^53 with two fraction digits",
+		},
+		{
+			Filter:   ".[]",
+			Data:     []interface{}{json.Number("12.50"), json.Number("0.1")},
+			Expected: []interface{}{json.Number("12.50"), 0.1},
+			ErrorNil: true,
+			Purpose:  "decimals with trailing zeros",
+		},
+		{
+			Filter:   "map(. > 10)",
+			Data:     []interface{}{json.Number("12.50"), json.Number("0.10"), json.Number("9.90")},
+			Expected: []interface{}{[]interface{}{true, false, false}},
+			ErrorNil: true,
+			Purpose:  "comparisons of decimals with trailing zeros",
+		},
+		{
+			Filter:   ".rate * 2",
+			Data:     map[string]interface{}{"rate": json.Number("12.50")},
+			Expected: []interface{}{25.0},
+			ErrorNil: true,
+			Purpose:  "arithmetic on decimals with trailing zeros",
+		},
+		{
+			Filter:   "[.[] | select(. < 1)] | sort",
+			Data:     []interface{}{json.Number("12.50"), json.Number("0.30"), json.Number("0.10")},
+			Expected: []interface{}{[]interface{}{json.Number("0.10"), json.Number("0.30")}},
+			ErrorNil: true,
+			Purpose:  "decimals selected without changes keep their text",
+		},
+		{
+			Filter:   ".uuid |",
+			Data:     item{Uuid: "da308fbd"},
+			ErrorNil: false,
+			Purpose:  "invalid filter",
+		},
+		{
+			Filter:   ".uuid | tonumber",
+			Data:     item{Uuid: "da308fbd"},
+			ErrorNil: false,
+			Purpose:  "evaluation error",
+		},
+	}
+
+	for _, tc := range testCases {
+		// act
+		results, err := Filter(tc.Filter, tc.Data)
+
+		// assert
+		if tc.ErrorNil {
+			assert.NoError(t, err, "error found for "+tc.Purpose)
+			assert.Equal(t, tc.Expected, results, "invalid results for "+tc.Purpose)
+		} else {
+			assert.Error(t, err, "error not found for "+tc.Purpose)
+		}
+	}
+}
+
+// bigInt parses a big integer for the expected results
+func bigInt(text string) *big.Int {
+	value, _ := new(big.Int).SetString(text, 10)
+	return value
+}
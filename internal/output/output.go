@@ -0,0 +1,391 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"gopkg.in/yaml.v3"
+)
+
+// Formatter writes data in a given output format
+type Formatter interface {
+	Format(w io.Writer, data interface{}) error
+}
+
+// NewFormatter creates the formatter for the given output format
+func NewFormatter(format string) (Formatter, error) {
+	switch {
+	case format == config.OutputJSON:
+		return &JSONFormatter{}, nil
+	case format == config.OutputYAML:
+		return &YAMLFormatter{}, nil
+	case format == config.OutputTable:
+		return &TableFormatter{}, nil
+	case format == config.OutputValue:
+		return &ValueFormatter{}, nil
+	case strings.HasPrefix(format, config.OutputGoTemplatePrefix):
+		text := strings.TrimPrefix(format, config.OutputGoTemplatePrefix)
+		tmpl, err := template.New("output").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing the output template: %w", err)
+		}
+		return &TemplateFormatter{Template: tmpl}, nil
+	}
+	return nil, fmt.Errorf("unknown output format %q", format)
+}
+
+// Print writes the data to the output stream using the output format
+// selected in the configuration.
+// When a jq filter is defined, it is applied to the data and each result is
+// written: strings as is, like jq --raw-output, and other values using the
+// output format.
+func Print(iostreams *iostreams.IOStreams, data interface{}) error {
+	formatter, err := NewFormatter(config.GetString(config.OutputFlag))
+	if err != nil {
+		return err
+	}
+
+	results, filtered, err := apply(config.GetString(config.JqFlag), data)
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		if text, isString := result.(string); isString && filtered {
+			_, err = fmt.Fprintln(iostreams.Out, text)
+		} else {
+			err = formatter.Format(iostreams.Out, result)
+		}
+		if err != nil {
+			return write(err)
+		}
+	}
+	return nil
+}
+
+// apply applies the jq filter to the data, if defined, returning the values
+// to print and if they were filtered
+func apply(filter string, data interface{}) ([]interface{}, bool, error) {
+	if filter == "" {
+		return []interface{}{data}, false, nil
+	}
+	results, err := Filter(filter, data)
+	return results, true, err
+}
+
+// Stream prints items one at a time as they are produced, for commands
+// returning many results, like Print does for each item. With the table
+// format the rows are kept and written on Close, so the columns are aligned.
+// With the JSON format the items are always written as JSON Lines (NDJSON),
+// a compact value per line, however many there are.
+type Stream struct {
+	iostreams *iostreams.IOStreams
+	formatter Formatter
+	filter    string
+	printed   int
+	rows      []interface{}
+}
+
+// NewStream creates a stream using the output format and jq filter selected
+// in the configuration
+func NewStream(iostreams *iostreams.IOStreams) (*Stream, error) {
+	formatter, err := NewFormatter(config.GetString(config.OutputFlag))
+	if err != nil {
+		return nil, err
+	}
+	return &Stream{
+		iostreams: iostreams,
+		formatter: formatter,
+		filter:    config.GetString(config.JqFlag),
+	}, nil
+}
+
+// Print writes an item to the output stream. YAML documents are separated
+// with ---.
+func (s *Stream) Print(item interface{}) error {
+	results, filtered, err := apply(s.filter, item)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		if text, isString := result.(string); isString && filtered {
+			_, err = fmt.Fprintln(s.iostreams.Out, text)
+			if err != nil {
+				return write(err)
+			}
+			continue
+		}
+
+		switch s.formatter.(type) {
+		case *TableFormatter:
+			s.rows = append(s.rows, result)
+			continue
+		case *JSONFormatter:
+			err = s.printLine(result)
+			if err != nil {
+				return write(err)
+			}
+			continue
+		case *YAMLFormatter:
+			if s.printed > 0 {
+				_, err = fmt.Fprintln(s.iostreams.Out, "---")
+				if err != nil {
+					return write(err)
+				}
+			}
+		}
+		err = s.formatter.Format(s.iostreams.Out, result)
+		if err != nil {
+			return write(err)
+		}
+		s.printed++
+	}
+	return nil
+}
+
+// printLine writes a JSON item in its own line
+func (s *Stream) printLine(item interface{}) error {
+	content, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(s.iostreams.Out, string(content))
+	if err != nil {
+		return err
+	}
+	s.printed++
+	return nil
+}
+
+// Close writes the rows kept by the table format
+func (s *Stream) Close() error {
+	if len(s.rows) == 0 {
+		return nil
+	}
+	return write(s.formatter.Format(s.iostreams.Out, s.rows))
+}
+
+// write wraps errors writing to the output
+func write(err error) error {
+	if err != nil {
+		return fmt.Errorf("error writing to the output: %w", err)
+	}
+	return nil
+}
+
+// normalize converts data into the generic values produced by decoding
+// JSON, honoring the JSON struct tags. Numbers are kept as json.Number to
+// avoid losing precision.
+func normalize(data interface{}) (interface{}, error) {
+	content, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	var normalized interface{}
+	err = decoder.Decode(&normalized)
+	return normalized, err
+}
+
+// JSONFormatter writes data as indented JSON
+type JSONFormatter struct{}
+
+// Format writes the data
+func (f *JSONFormatter) Format(w io.Writer, data interface{}) error {
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(content))
+	return err
+}
+
+// YAMLFormatter writes data as YAML
+type YAMLFormatter struct{}
+
+// Format writes the data
+func (f *YAMLFormatter) Format(w io.Writer, data interface{}) error {
+	normalized, err := normalize(data)
+	if err != nil {
+		return err
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	err = encoder.Encode(yamlNode(normalized))
+	if err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// yamlNode converts a normalized value into a YAML node, writing numbers
+// exactly as received and object keys in alphabetical order
+func yamlNode(value interface{}) *yaml.Node {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, key := range sortedKeys(v) {
+			node.Content = append(node.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+				yamlNode(v[key]))
+		}
+		return node
+	case []interface{}:
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range v {
+			node.Content = append(node.Content, yamlNode(item))
+		}
+		return node
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(string(v), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: string(v)}
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(v)}
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+}
+
+// TableFormatter writes objects, or lists of objects, as a table with a
+// column for each field
+type TableFormatter struct{}
+
+// Format writes the data
+func (f *TableFormatter) Format(w io.Writer, data interface{}) error {
+	normalized, err := normalize(data)
+	if err != nil {
+		return err
+	}
+
+	rows := []map[string]interface{}{}
+	switch v := normalized.(type) {
+	case map[string]interface{}:
+		rows = append(rows, v)
+	case []interface{}:
+		for _, item := range v {
+			row, ok := item.(map[string]interface{})
+			if !ok {
+				row = map[string]interface{}{"value": item}
+			}
+			rows = append(rows, row)
+		}
+	default:
+		rows = append(rows, map[string]interface{}{"value": v})
+	}
+
+	// the columns are all the fields found in the rows
+	columnSet := map[string]interface{}{}
+	for _, row := range rows {
+		for key := range row {
+			columnSet[key] = nil
+		}
+	}
+	columns := sortedKeys(columnSet)
+
+	writer := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	header := []string{}
+	for _, column := range columns {
+		header = append(header, strings.ToUpper(column))
+	}
+	fmt.Fprintln(writer, strings.Join(header, "\t"))
+	for _, row := range rows {
+		cells := []string{}
+		for _, column := range columns {
+			cells = append(cells, scalarText(row[column]))
+		}
+		fmt.Fprintln(writer, strings.Join(cells, "\t"))
+	}
+	return writer.Flush()
+}
+
+// ValueFormatter writes only the values, without any decoration, to be
+// used in shell substitutions. Objects must have a single field and lists
+// have each value written in its own line.
+type ValueFormatter struct{}
+
+// Format writes the data
+func (f *ValueFormatter) Format(w io.Writer, data interface{}) error {
+	normalized, err := normalize(data)
+	if err != nil {
+		return err
+	}
+
+	values := []interface{}{normalized}
+	if list, ok := normalized.([]interface{}); ok {
+		values = list
+	}
+
+	for _, value := range values {
+		if object, ok := value.(map[string]interface{}); ok {
+			if len(object) != 1 {
+				return fmt.Errorf("the value output format requires a single field, " +
+					"use another output format or a template")
+			}
+			for _, field := range object {
+				value = field
+			}
+		}
+
+		_, err = fmt.Fprintln(w, scalarText(value))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TemplateFormatter writes data using a Go template
+type TemplateFormatter struct {
+	Template *template.Template
+}
+
+// Format writes the data
+func (f *TemplateFormatter) Format(w io.Writer, data interface{}) error {
+	normalized, err := normalize(data)
+	if err != nil {
+		return err
+	}
+	return f.Template.Execute(w, normalized)
+}
+
+// scalarText returns the text of a value in tables and value outputs.
+// Nested objects and lists are written as compact JSON.
+func scalarText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case map[string]interface{}, []interface{}:
+		content, _ := json.Marshal(v)
+		return string(content)
+	}
+	return fmt.Sprint(value)
+}
+
+// sortedKeys returns the keys of an object in alphabetical order
+func sortedKeys(object map[string]interface{}) []string {
+	keys := []string{}
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,255 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/renato0307/learning-go-cli/internal/iostreams"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+type item struct {
+	Uuid   string  `json:"uuid"`
+	Amount float64 `json:"amount,omitempty"`
+}
+
+func TestFormatters(t *testing.T) {
+	testCases := []struct {
+		Format   string
+		Data     interface{}
+		Expected string
+		ErrorNil bool
+		Purpose  string
+	}{
+		{
+			Format:   config.OutputJSON,
+			Data:     item{Uuid: "da308fbd"},
+			Expected: "{\n  \"uuid\": \"da308fbd\"\n}\n",
+			ErrorNil: true,
+			Purpose:  "json",
+		},
+		{
+			Format:   config.OutputYAML,
+			Data:     item{Uuid: "da308fbd", Amount: 12.5},
+			Expected: "amount: 12.5\nuuid: da308fbd\n",
+			ErrorNil: true,
+			Purpose:  "yaml",
+		},
+		{
+			Format:   config.OutputYAML,
+			Data:     []interface{}{"a", 1, true, nil},
+			Expected: "- a\n- 1\n- true\n- null\n",
+			ErrorNil: true,
+			Purpose:  "yaml list",
+		},
+		{
+			Format:   config.OutputTable,
+			Data:     []item{{Uuid: "da308fbd", Amount: 1}, {Uuid: "cba9485a"}},
+			Expected: "AMOUNT  UUID\n1       da308fbd\n        cba9485a\n",
+			ErrorNil: true,
+			Purpose:  "table",
+		},
+		{
+			Format:   config.OutputTable,
+			Data:     "scalar",
+			Expected: "VALUE\nscalar\n",
+			ErrorNil: true,
+			Purpose:  "table with scalar",
+		},
+		{
+			Format:   config.OutputValue,
+			Data:     item{Uuid: "da308fbd"},
+			Expected: "da308fbd\n",
+			ErrorNil: true,
+			Purpose:  "value",
+		},
+		{
+			Format:   config.OutputValue,
+			Data:     []item{{Uuid: "da308fbd"}, {Uuid: "cba9485a"}},
+			Expected: "da308fbd\ncba9485a\n",
+			ErrorNil: true,
+			Purpose:  "value list",
+		},
+		{
+			Format:   config.OutputValue,
+			Data:     item{Uuid: "da308fbd", Amount: 1},
+			ErrorNil: false,
+			Purpose:  "value with several fields",
+		},
+		{
+			Format:   "go-template={{.uuid}}",
+			Data:     item{Uuid: "da308fbd"},
+			Expected: "da308fbd",
+			ErrorNil: true,
+			Purpose:  "go template",
+		},
+		{
+			Format:   "go-template={{.uuid",
+			Data:     item{Uuid: "da308fbd"},
+			ErrorNil: false,
+			Purpose:  "invalid go template",
+		},
+		{
+			Format:   "xml",
+			Data:     item{Uuid: "da308fbd"},
+			ErrorNil: false,
+			Purpose:  "unknown format",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		buffer := &bytes.Buffer{}
+
+		// act
+		formatter, err := NewFormatter(tc.Format)
+		if err == nil {
+			err = formatter.Format(buffer, tc.Data)
+		}
+
+		// assert
+		if tc.ErrorNil {
+			assert.NoError(t, err, "error found for "+tc.Purpose)
+			assert.Equal(t, tc.Expected, buffer.String(), "invalid output for "+tc.Purpose)
+		} else {
+			assert.Error(t, err, "error not found for "+tc.Purpose)
+		}
+	}
+}
+
+func TestYAMLFormatterKeepsNumbersExact(t *testing.T) {
+	// arrange
+	buffer := &bytes.Buffer{}
+	data := map[string]interface{}{"amount": json.Number("12.50")}
+
+	// act
+	err := (&YAMLFormatter{}).Format(buffer, data)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "amount: 12.50\n", buffer.String())
+}
+
+func TestPrint(t *testing.T) {
+	// arrange
+	viper.Reset()
+	defer viper.Reset()
+	buffer := &bytes.Buffer{}
+	iostreams := &iostreams.IOStreams{Out: buffer}
+
+	// act & assert
+	err := Print(iostreams, item{Uuid: "da308fbd"})
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n  \"uuid\": \"da308fbd\"\n}\n", buffer.String())
+
+	buffer.Reset()
+	config.Set(config.OutputFlag, config.OutputValue)
+	err = Print(iostreams, item{Uuid: "da308fbd"})
+	assert.NoError(t, err)
+	assert.Equal(t, "da308fbd\n", buffer.String())
+}
+
+func TestPrintWithJqFilter(t *testing.T) {
+	// arrange
+	viper.Reset()
+	defer viper.Reset()
+	buffer := &bytes.Buffer{}
+	iostreams := &iostreams.IOStreams{Out: buffer}
+	data := []item{{Uuid: "da308fbd", Amount: 1}, {Uuid: "cba9485a", Amount: 2}}
+
+	// act & assert
+	config.Set(config.JqFlag, ".[].uuid")
+	err := Print(iostreams, data)
+	assert.NoError(t, err)
+	assert.Equal(t, "da308fbd\ncba9485a\n", buffer.String())
+
+	buffer.Reset()
+	config.Set(config.JqFlag, ".[0]")
+	err = Print(iostreams, data)
+	assert.NoError(t, err)
+	assert.Equal(t, "{\n  \"amount\": 1,\n  \"uuid\": \"da308fbd\"\n}\n", buffer.String())
+
+	config.Set(config.JqFlag, ".[")
+	err = Print(iostreams, data)
+	assert.Error(t, err)
+}
+
+func TestStreamWithSingleJSONItem(t *testing.T) {
+	// arrange
+	viper.Reset()
+	defer viper.Reset()
+	config.Set(config.OutputFlag, config.OutputJSON)
+	buffer := &bytes.Buffer{}
+	iostreams := &iostreams.IOStreams{Out: buffer}
+
+	// act
+	stream, err := NewStream(iostreams)
+	assert.NoError(t, err)
+	assert.NoError(t, stream.Print(item{Uuid: "da308fbd"}))
+	assert.Equal(t, "{\"uuid\":\"da308fbd\"}\n", buffer.String(), "items must be written as they are printed")
+	err = stream.Close()
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"uuid\":\"da308fbd\"}\n", buffer.String())
+}
+
+func TestStream(t *testing.T) {
+	testCases := []struct {
+		Format   string
+		Filter   string
+		Expected string
+		Purpose  string
+	}{
+		{
+			Format:   config.OutputJSON,
+			Expected: "{\"uuid\":\"da308fbd\"}\n{\"uuid\":\"cba9485a\"}\n",
+			Purpose:  "json lines",
+		},
+		{
+			Format:   config.OutputYAML,
+			Expected: "uuid: da308fbd\n---\nuuid: cba9485a\n",
+			Purpose:  "yaml",
+		},
+		{
+			Format:   config.OutputTable,
+			Expected: "UUID\nda308fbd\ncba9485a\n",
+			Purpose:  "table",
+		},
+		{
+			Format:   config.OutputValue,
+			Expected: "da308fbd\ncba9485a\n",
+			Purpose:  "value",
+		},
+		{
+			Format:   config.OutputJSON,
+			Filter:   ".uuid",
+			Expected: "da308fbd\ncba9485a\n",
+			Purpose:  "jq filter",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		viper.Reset()
+		config.Set(config.OutputFlag, tc.Format)
+		config.Set(config.JqFlag, tc.Filter)
+		buffer := &bytes.Buffer{}
+		iostreams := &iostreams.IOStreams{Out: buffer}
+
+		// act
+		stream, err := NewStream(iostreams)
+		assert.NoError(t, err, "error found for "+tc.Purpose)
+		assert.NoError(t, stream.Print(item{Uuid: "da308fbd"}), "error found for "+tc.Purpose)
+		assert.NoError(t, stream.Print(item{Uuid: "cba9485a"}), "error found for "+tc.Purpose)
+		err = stream.Close()
+
+		// assert
+		assert.NoError(t, err, "error found for "+tc.Purpose)
+		assert.Equal(t, tc.Expected, buffer.String(), "invalid output for "+tc.Purpose)
+	}
+	viper.Reset()
+}
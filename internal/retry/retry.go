@@ -0,0 +1,203 @@
+package retry
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/renato0307/learning-go-cli/internal/config"
+)
+
+// Policy defines how failed requests are retried
+type Policy struct {
+	MaxAttempts          int
+	BaseDelay            time.Duration
+	MaxDelay             time.Duration
+	RetryableStatusCodes []int
+}
+
+// PolicyFromConfig creates the retry policy defined in the configuration.
+// When retries are disabled the policy makes a single attempt.
+func PolicyFromConfig() Policy {
+	policy := Policy{
+		MaxAttempts:          config.GetInt(config.RetryMaxAttemptsFlag),
+		BaseDelay:            config.GetDuration(config.RetryBaseDelayFlag),
+		MaxDelay:             config.GetDuration(config.RetryMaxDelayFlag),
+		RetryableStatusCodes: ParseStatusCodes(config.GetString(config.RetryStatusCodesFlag)),
+	}
+	if config.GetBool(config.NoRetryFlag) {
+		policy.MaxAttempts = 1
+	}
+	return policy
+}
+
+// ParseStatusCodes parses a comma separated list of HTTP status codes,
+// ignoring invalid values
+func ParseStatusCodes(value string) []int {
+	codes := []int{}
+	for _, part := range strings.Split(value, ",") {
+		code, err := strconv.Atoi(strings.TrimSpace(part))
+		if err == nil {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// Backoff returns the delay before the retry following the given attempt,
+// starting at 1, using exponential backoff with full jitter
+func (p Policy) Backoff(attempt int) time.Duration {
+	ceiling := float64(p.BaseDelay) * math.Pow(2, float64(attempt-1))
+	if ceiling > float64(p.MaxDelay) {
+		ceiling = float64(p.MaxDelay)
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// retryableStatus checks if a response status code must be retried
+func (p Policy) retryableStatus(statusCode int) bool {
+	for _, code := range p.RetryableStatusCodes {
+		if code == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// idempotentKey is the context key marking requests which can be retried
+type idempotentKey struct{}
+
+// MarkIdempotent marks the request as safe to be retried, for requests whose
+// method is not idempotent by definition, like POST
+func MarkIdempotent(request *http.Request) *http.Request {
+	ctx := context.WithValue(request.Context(), idempotentKey{}, true)
+	return request.WithContext(ctx)
+}
+
+// idempotent checks if a request can be retried: its method is idempotent or
+// it was marked as idempotent
+func idempotent(request *http.Request) bool {
+	switch request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions,
+		http.MethodPut, http.MethodDelete, http.MethodTrace:
+		return true
+	}
+	marked, _ := request.Context().Value(idempotentKey{}).(bool)
+	return marked
+}
+
+// Transport is an http.RoundTripper retrying idempotent requests that fail
+// with transport errors or retryable status codes
+type Transport struct {
+	Base   http.RoundTripper
+	Policy Policy
+}
+
+// NewTransport creates a transport retrying the requests made with base
+func NewTransport(base http.RoundTripper, policy Policy) *Transport {
+	return &Transport{Base: base, Policy: policy}
+}
+
+// NewClient creates an HTTP client retrying requests with the policy. The
+// timeout limits the time of each request, including retries, and can be
+// zero for no timeout.
+func NewClient(policy Policy, timeout time.Duration) *http.Client {
+	return &http.Client{
+		Transport: NewTransport(http.DefaultTransport, policy),
+		Timeout:   timeout,
+	}
+}
+
+// RoundTrip executes the request, retrying it according to the policy.
+// The Retry-After header of 429 and 503 responses is honored, giving up when
+// it asks to wait longer than the maximum delay.
+func (t *Transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	// requests whose body cannot be read again can only be attempted once
+	canRetry := idempotent(request) && (request.Body == nil || request.GetBody != nil)
+
+	for attempt := 1; ; attempt++ {
+		// retries send a copy with a new body as round trippers must not
+		// modify the request
+		current := request
+		if attempt > 1 && request.Body != nil {
+			body, err := request.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			current = request.Clone(request.Context())
+			current.Body = body
+		}
+
+		response, err := t.Base.RoundTrip(current)
+
+		lastAttempt := !canRetry || attempt >= t.Policy.MaxAttempts
+		if lastAttempt || request.Context().Err() != nil {
+			return response, err
+		}
+		if err == nil && !t.Policy.retryableStatus(response.StatusCode) {
+			return response, nil
+		}
+
+		delay := t.Policy.Backoff(attempt)
+		if err == nil {
+			retryAfter, found := parseRetryAfter(response)
+			if found && retryAfter > t.Policy.MaxDelay {
+				return response, nil
+			}
+			if found && retryAfter > delay {
+				delay = retryAfter
+			}
+			response.Body.Close()
+		}
+
+		err = sleep(request.Context(), delay)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sleep waits for the delay unless the context is done first
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// parseRetryAfter returns the delay asked by the Retry-After header of 429
+// and 503 responses, either in seconds or as an HTTP date
+func parseRetryAfter(response *http.Response) (time.Duration, bool) {
+	if response.StatusCode != http.StatusTooManyRequests &&
+		response.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+
+	value := response.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
@@ -0,0 +1,295 @@
+package retry
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/renato0307/learning-go-cli/internal/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func testPolicy() Policy {
+	return Policy{
+		MaxAttempts:          3,
+		BaseDelay:            time.Millisecond,
+		MaxDelay:             10 * time.Millisecond,
+		RetryableStatusCodes: []int{http.StatusTooManyRequests, http.StatusServiceUnavailable},
+	}
+}
+
+func TestPolicyFromConfig(t *testing.T) {
+	// arrange
+	viper.Reset()
+	defer viper.Reset()
+
+	// act
+	policy := PolicyFromConfig()
+
+	// assert
+	assert.Equal(t, 3, policy.MaxAttempts)
+	assert.Equal(t, 200*time.Millisecond, policy.BaseDelay)
+	assert.Equal(t, 5*time.Second, policy.MaxDelay)
+	assert.Equal(t, []int{429, 502, 503, 504}, policy.RetryableStatusCodes)
+
+	config.Set(config.NoRetryFlag, true)
+	assert.Equal(t, 1, PolicyFromConfig().MaxAttempts)
+}
+
+func TestParseStatusCodes(t *testing.T) {
+	assert.Equal(t, []int{429, 503}, ParseStatusCodes("429, 503"))
+	assert.Equal(t, []int{503}, ParseStatusCodes("invalid,503"))
+	assert.Empty(t, ParseStatusCodes(""))
+}
+
+func TestBackoff(t *testing.T) {
+	// arrange
+	policy := Policy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		// act
+		delay := policy.Backoff(attempt)
+
+		// assert
+		assert.GreaterOrEqual(t, delay, time.Duration(0))
+		assert.LessOrEqual(t, delay, time.Second)
+	}
+	assert.LessOrEqual(t, policy.Backoff(1), 100*time.Millisecond)
+}
+
+func TestTransport(t *testing.T) {
+	testCases := []struct {
+		Method           string
+		Body             string
+		MarkIdempotent   bool
+		Statuses         []int
+		RetryAfter       string
+		ExpectedAttempts int
+		ExpectedStatus   int
+		Purpose          string
+	}{
+		{
+			Method:           http.MethodGet,
+			Statuses:         []int{http.StatusOK},
+			ExpectedAttempts: 1,
+			ExpectedStatus:   http.StatusOK,
+			Purpose:          "success at first attempt",
+		},
+		{
+			Method:           http.MethodGet,
+			Statuses:         []int{http.StatusServiceUnavailable, http.StatusOK},
+			ExpectedAttempts: 2,
+			ExpectedStatus:   http.StatusOK,
+			Purpose:          "success after retry",
+		},
+		{
+			Method:           http.MethodGet,
+			Statuses:         []int{http.StatusServiceUnavailable},
+			ExpectedAttempts: 3,
+			ExpectedStatus:   http.StatusServiceUnavailable,
+			Purpose:          "maximum attempts reached",
+		},
+		{
+			Method:           http.MethodGet,
+			Statuses:         []int{http.StatusBadRequest},
+			ExpectedAttempts: 1,
+			ExpectedStatus:   http.StatusBadRequest,
+			Purpose:          "status not retryable",
+		},
+		{
+			Method:           http.MethodPost,
+			Statuses:         []int{http.StatusServiceUnavailable},
+			ExpectedAttempts: 1,
+			ExpectedStatus:   http.StatusServiceUnavailable,
+			Purpose:          "post is not retried",
+		},
+		{
+			Method:           http.MethodPost,
+			Body:             "grant_type=client_credentials",
+			MarkIdempotent:   true,
+			Statuses:         []int{http.StatusServiceUnavailable, http.StatusOK},
+			ExpectedAttempts: 2,
+			ExpectedStatus:   http.StatusOK,
+			Purpose:          "post marked as idempotent is retried with body",
+		},
+		{
+			Method:           http.MethodGet,
+			Statuses:         []int{http.StatusTooManyRequests, http.StatusOK},
+			RetryAfter:       "0",
+			ExpectedAttempts: 2,
+			ExpectedStatus:   http.StatusOK,
+			Purpose:          "retry after honored",
+		},
+		{
+			Method:           http.MethodGet,
+			Statuses:         []int{http.StatusTooManyRequests, http.StatusOK},
+			RetryAfter:       "3600",
+			ExpectedAttempts: 1,
+			ExpectedStatus:   http.StatusTooManyRequests,
+			Purpose:          "retry after longer than maximum delay",
+		},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		attempts := 0
+		srv := httptest.NewServer(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				assert.Equal(t, tc.Body, string(body), "invalid body for "+tc.Purpose)
+
+				status := tc.Statuses[len(tc.Statuses)-1]
+				if attempts < len(tc.Statuses) {
+					status = tc.Statuses[attempts]
+				}
+				attempts++
+
+				if tc.RetryAfter != "" {
+					w.Header().Set("Retry-After", tc.RetryAfter)
+				}
+				w.WriteHeader(status)
+			}))
+		defer srv.Close()
+
+		client := NewClient(testPolicy(), 0)
+		request, _ := http.NewRequest(tc.Method, srv.URL, strings.NewReader(tc.Body))
+		if tc.MarkIdempotent {
+			request = MarkIdempotent(request)
+		}
+
+		// act
+		response, err := client.Do(request)
+
+		// assert
+		assert.NoError(t, err, "error found for "+tc.Purpose)
+		assert.Equal(t, tc.ExpectedStatus, response.StatusCode, "invalid status for "+tc.Purpose)
+		assert.Equal(t, tc.ExpectedAttempts, attempts, "invalid attempts for "+tc.Purpose)
+	}
+}
+
+func TestTransportRetriesTransportErrors(t *testing.T) {
+	// arrange
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := srv.URL
+	srv.Close()
+	client := NewClient(testPolicy(), 0)
+
+	// act
+	start := time.Now()
+	_, err := client.Get(url)
+
+	// assert
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+// roundTripFunc is a round tripper implemented by a function
+type roundTripFunc func(request *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+func TestTransportDoesNotModifyTheRequest(t *testing.T) {
+	// arrange
+	bodies := []string{}
+	sent := []*http.Request{}
+	base := roundTripFunc(func(request *http.Request) (*http.Response, error) {
+		content, _ := ioutil.ReadAll(request.Body)
+		bodies = append(bodies, string(content))
+		sent = append(sent, request)
+		status := http.StatusServiceUnavailable
+		if len(sent) == 2 {
+			status = http.StatusOK
+		}
+		return &http.Response{StatusCode: status, Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+	})
+	transport := NewTransport(base, testPolicy())
+
+	request, _ := http.NewRequest(http.MethodPost, "https://api.example.com", strings.NewReader("payload"))
+	request = MarkIdempotent(request)
+	originalBody := request.Body
+
+	// act
+	response, err := transport.RoundTrip(request)
+
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, []string{"payload", "payload"}, bodies)
+	assert.Same(t, request, sent[0])
+	assert.NotSame(t, request, sent[1], "retries must send a copy of the request")
+	assert.True(t, originalBody == request.Body, "the body of the request must not be replaced")
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	testCases := []struct {
+		Status   int
+		Value    string
+		Expected time.Duration
+		Found    bool
+		Purpose  string
+	}{
+		{http.StatusTooManyRequests, "2", 2 * time.Second, true, "seconds"},
+		{http.StatusServiceUnavailable, "Mon, 01 Jan 2001 00:00:00 GMT", 0, true, "date in the past"},
+		{http.StatusBadGateway, "2", 0, false, "status without retry after"},
+		{http.StatusTooManyRequests, "", 0, false, "missing header"},
+		{http.StatusTooManyRequests, "soon", 0, false, "invalid header"},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		response := &http.Response{StatusCode: tc.Status, Header: http.Header{}}
+		response.Header.Set("Retry-After", tc.Value)
+
+		// act
+		delay, found := parseRetryAfter(response)
+
+		// assert
+		assert.Equal(t, tc.Expected, delay, "invalid delay for "+tc.Purpose)
+		assert.Equal(t, tc.Found, found, "invalid result for "+tc.Purpose)
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	// arrange
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(100 * time.Millisecond)
+		}))
+	defer srv.Close()
+	client := NewClient(testPolicy(), 10*time.Millisecond)
+
+	// act
+	_, err := client.Get(srv.URL)
+
+	// assert
+	assert.Error(t, err)
+}
+
+func TestTransportStopsWhenContextIsCancelled(t *testing.T) {
+	// arrange
+	attempts := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	srv := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			cancel()
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+	defer srv.Close()
+	client := NewClient(testPolicy(), 0)
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+
+	// act
+	_, err := client.Do(request)
+
+	// assert
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, attempts)
+}
@@ -1,11 +1,14 @@
 package testhelpers
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 
+	"github.com/renato0307/learning-go-cli/internal/api"
 	"github.com/renato0307/learning-go-cli/internal/auth"
 )
 
@@ -46,3 +49,41 @@ func NewAPITestServer(body string, expectedQueryParams []string, httpStatus int)
 
 	return srv
 }
+
+// FakeRequester implements api.Requester to test commands without calling
+// the API, returning the configured response or error. When Handler is
+// defined, it returns the response or error of each request instead.
+// It can be used concurrently.
+type FakeRequester struct {
+	Response string
+	Err      error
+	Handler  func(request api.Request) (string, error)
+	Requests []api.Request
+	mu       sync.Mutex
+}
+
+// Do records the request and decodes the configured response
+func (f *FakeRequester) Do(ctx context.Context, request api.Request, response interface{}) error {
+	f.mu.Lock()
+	f.Requests = append(f.Requests, request)
+	f.mu.Unlock()
+
+	content, err := f.Response, f.Err
+	if f.Handler != nil {
+		content, err = f.Handler(request)
+	}
+	if err != nil {
+		return err
+	}
+	if response == nil {
+		return nil
+	}
+	return json.Unmarshal([]byte(content), response)
+}
+
+// Factory returns an api.Factory always returning this requester
+func (f *FakeRequester) Factory() api.Factory {
+	return func() api.Requester {
+		return f
+	}
+}